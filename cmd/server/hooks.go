@@ -0,0 +1,16 @@
+package main
+
+import (
+	"go.uber.org/zap"
+
+	"sse_demo/internal/service/notify"
+)
+
+// provideHooks assembles the create hooks of the notification service.
+// Deployments register their hooks here; before-create hooks run in the
+// order listed.
+func provideHooks(logger *zap.Logger) *notify.Hooks {
+	var before []notify.BeforeCreateHook
+	var after []notify.AfterCreateHook
+	return notify.NewHooks(before, after, logger)
+}
@@ -7,6 +7,8 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Quiet hours resolve user time zones; the runtime image has no zoneinfo.
+	_ "time/tzdata"
 
 	"go.uber.org/zap"
 
@@ -19,6 +21,18 @@ func main() {
 	defer stop()
 
 	cfg := config.New()
+	if len(os.Args) > 1 {
+		switch command := os.Args[1]; command {
+		case "export", "import":
+			if err := runTransfer(ctx, cfg, command, os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", command, err)
+			}
+			return
+		default:
+			log.Fatalf("unknown command %q; use export or import", command)
+		}
+	}
+
 	shutdownTelemetry, err := telemetry.Init(ctx, cfg)
 	if err != nil {
 		log.Fatalf("init telemetry: %v", err)
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sse_demo/internal/config"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/service/notify"
+)
+
+// runTransfer implements the export and import subcommands:
+//
+//	server export [-room ROOM] [-since RFC3339] [-until RFC3339] [-file PATH]
+//	server import [-ids remap|preserve] [-file PATH]
+//
+// Without -file the dump is written to stdout or read from stdin.
+func runTransfer(ctx context.Context, cfg *config.Config, command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	file := flags.String("file", "", "JSON Lines file; defaults to stdout for export and stdin for import")
+	room := flags.String("room", "", "export only this room")
+	since := flags.String("since", "", "export notifications created at or after this RFC 3339 time")
+	until := flags.String("until", "", "export notifications created before this RFC 3339 time")
+	ids := flags.String("ids", string(notify.IDsRemap), "import id handling: remap or preserve")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	transfer, err := InitializeTransferService(cfg)
+	if err != nil {
+		return fmt.Errorf("init transfer: %w", err)
+	}
+
+	switch command {
+	case "export":
+		filter := repository.ExportFilter{Room: *room}
+		if filter.Since, err = parseTimeFlag("since", *since); err != nil {
+			return err
+		}
+		if filter.Until, err = parseTimeFlag("until", *until); err != nil {
+			return err
+		}
+		var out io.Writer = os.Stdout
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		written, err := transfer.Export(ctx, out, filter)
+		if err != nil {
+			return fmt.Errorf("export stopped after %d notifications: %w", written, err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d notifications\n", written)
+	case "import":
+		mode, err := notify.ParseIDMode(*ids)
+		if err != nil {
+			return fmt.Errorf("-ids must be remap or preserve")
+		}
+		var in io.Reader = os.Stdin
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		imported, err := transfer.Import(ctx, in, mode)
+		if err != nil {
+			return fmt.Errorf("import stopped after %d notifications: %w", imported, err)
+		}
+		fmt.Fprintf(os.Stderr, "imported %d notifications\n", imported)
+	}
+	return nil
+}
+
+func parseTimeFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("-%s must be an RFC 3339 timestamp", name)
+	}
+	t = t.UTC()
+	return &t, nil
+}
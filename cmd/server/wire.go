@@ -9,8 +9,10 @@ import (
 	"sse_demo/internal/config"
 	"sse_demo/internal/http"
 	"sse_demo/internal/http/controller"
+	"sse_demo/internal/i18n"
 	"sse_demo/internal/logging"
 	"sse_demo/internal/queue/rabbitmq"
+	"sse_demo/internal/repository"
 	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
 	"sse_demo/internal/store"
@@ -20,8 +22,39 @@ func InitializeApp(cfg *config.Config) (*app.App, error) {
 	wire.Build(
 		logging.New,
 		store.NewStore,
+		wire.Bind(new(repository.NotificationRepository), new(store.Store)),
+		wire.Bind(new(repository.NotificationTypeRepository), new(store.Store)),
+		wire.Bind(new(repository.ActionInvocationRepository), new(store.Store)),
+		wire.Bind(new(repository.IdempotencyKeyRepository), new(store.Store)),
+		wire.Bind(new(repository.RetentionRepository), new(store.Store)),
+		wire.Bind(new(repository.TransferRepository), new(store.Store)),
+		wire.Bind(new(repository.RoomRepository), new(store.Store)),
+		wire.Bind(new(repository.PreferenceRepository), new(store.Store)),
+		wire.Bind(new(repository.MaintenanceWindowRepository), new(store.Store)),
+		wire.Bind(new(repository.PinRepository), new(store.Store)),
+		wire.Bind(new(repository.RoomStateRepository), new(store.Store)),
+		wire.Bind(new(repository.AnnouncementRepository), new(store.Store)),
+		wire.Bind(new(repository.DeliveryRepository), new(store.Store)),
+		wire.Bind(new(repository.EscalationRepository), new(store.Store)),
 		sse.NewHub,
+		i18n.NewCatalog,
+		notify.NewTypeService,
+		notify.NewTypeRegistry,
+		notify.NewDigester,
+		notify.NewMaintenanceService,
+		notify.NewEscalationService,
+		provideHooks,
 		notify.NewService,
+		notify.NewActionService,
+		notify.NewTransferService,
+		notify.NewRoomService,
+		notify.NewPreferenceService,
+		notify.NewPinService,
+		notify.NewStateService,
+		notify.NewAnnouncementService,
+		notify.NewDeliveryTracker,
+		notify.NewIdempotencyPurger,
+		notify.NewRetentionPurger,
 		controller.NewHandler,
 		http.NewRouter,
 		rabbitmq.NewConsumer,
@@ -30,3 +63,17 @@ func InitializeApp(cfg *config.Config) (*app.App, error) {
 	)
 	return &app.App{}, nil
 }
+
+func InitializeTransferService(cfg *config.Config) (*notify.TransferService, error) {
+	wire.Build(
+		logging.New,
+		store.NewStore,
+		wire.Bind(new(repository.TransferRepository), new(store.Store)),
+		wire.Bind(new(repository.RoomRepository), new(store.Store)),
+		wire.Bind(new(repository.NotificationTypeRepository), new(store.Store)),
+		notify.NewTypeService,
+		notify.NewTypeRegistry,
+		notify.NewTransferService,
+	)
+	return &notify.TransferService{}, nil
+}
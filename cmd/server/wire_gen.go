@@ -11,6 +11,7 @@ import (
 	"sse_demo/internal/config"
 	"sse_demo/internal/http"
 	"sse_demo/internal/http/controller"
+	"sse_demo/internal/i18n"
 	"sse_demo/internal/logging"
 	"sse_demo/internal/queue/rabbitmq"
 	"sse_demo/internal/service/notify"
@@ -18,6 +19,10 @@ import (
 	"sse_demo/internal/store"
 )
 
+import (
+	_ "time/tzdata"
+)
+
 // Injectors from wire.go:
 
 func InitializeApp(cfg *config.Config) (*app.App, error) {
@@ -26,15 +31,59 @@ func InitializeApp(cfg *config.Config) (*app.App, error) {
 	if err != nil {
 		return nil, err
 	}
-	notificationRepository, err := store.NewStore(cfg, logger)
+	storeStore, err := store.NewStore(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	typeService, err := notify.NewTypeService(cfg, storeStore, logger)
+	if err != nil {
+		return nil, err
+	}
+	typeRegistry := notify.NewTypeRegistry(typeService)
+	catalog, err := i18n.NewCatalog(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	digester := notify.NewDigester(cfg, storeStore, hub, logger)
+	maintenanceService := notify.NewMaintenanceService(cfg, storeStore, hub, logger)
+	escalationService, err := notify.NewEscalationService(cfg, storeStore, storeStore, hub, logger)
 	if err != nil {
 		return nil, err
 	}
-	service := notify.NewService(notificationRepository, hub, logger)
-	consumer := rabbitmq.NewConsumer(cfg, service, logger)
+	hooks := provideHooks(logger)
+	service := notify.NewService(cfg, storeStore, storeStore, storeStore, hub, typeRegistry, catalog, digester, maintenanceService, escalationService, hooks, logger)
+	announcementService := notify.NewAnnouncementService(storeStore, hub, logger)
+	consumer := rabbitmq.NewConsumer(cfg, service, announcementService, logger)
+	idempotencyPurger := notify.NewIdempotencyPurger(cfg, storeStore, logger)
+	retentionPurger := notify.NewRetentionPurger(cfg, storeStore, storeStore, logger)
+	deliveryTracker := notify.NewDeliveryTracker(storeStore, storeStore, hub, logger)
 	publisher := rabbitmq.NewPublisher(cfg, logger)
-	handler := controller.NewHandler(cfg, service, hub, logger, publisher)
+	actionService := notify.NewActionService(cfg, storeStore, storeStore, publisher, hub, logger)
+	transferService := notify.NewTransferService(cfg, storeStore, storeStore, typeRegistry, logger)
+	roomService := notify.NewRoomService(storeStore, logger)
+	preferenceService := notify.NewPreferenceService(storeStore, hub, logger)
+	pinService := notify.NewPinService(cfg, storeStore, storeStore, hub, logger)
+	stateService := notify.NewStateService(storeStore, hub, logger)
+	handler := controller.NewHandler(cfg, service, typeService, actionService, transferService, roomService, preferenceService, maintenanceService, pinService, stateService, announcementService, deliveryTracker, escalationService, hub, logger, publisher)
 	engine := http.NewRouter(handler, logger, cfg)
-	appApp := app.NewApp(cfg, hub, consumer, engine, logger)
+	appApp := app.NewApp(cfg, hub, typeService, consumer, idempotencyPurger, retentionPurger, digester, maintenanceService, deliveryTracker, escalationService, hooks, engine, logger)
 	return appApp, nil
 }
+
+func InitializeTransferService(cfg *config.Config) (*notify.TransferService, error) {
+	logger, err := logging.New()
+	if err != nil {
+		return nil, err
+	}
+	storeStore, err := store.NewStore(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	typeService, err := notify.NewTypeService(cfg, storeStore, logger)
+	if err != nil {
+		return nil, err
+	}
+	typeRegistry := notify.NewTypeRegistry(typeService)
+	transferService := notify.NewTransferService(cfg, storeStore, storeStore, typeRegistry, logger)
+	return transferService, nil
+}
@@ -1,9 +1,322 @@
 -- name: CreateNotification :execresult
-INSERT INTO notifications (room, type, title, body) VALUES (?, ?, ?, ?);
+INSERT INTO notifications (room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, collapse_key, replaces_id, priority, seq) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
--- name: ListNotificationsByRoom :many
-SELECT id, room, type, title, body, created_at
+-- name: GetNotification :one
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
 FROM notifications
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: UpdateNotification :execrows
+UPDATE notifications
+SET title = ?, body = ?, severity = ?, priority = ?, data = ?, link = ?, actions = ?, localizations = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: SoftDeleteNotification :execrows
+UPDATE notifications SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetLatestCollapsedNotificationID :one
+SELECT id FROM notifications
+WHERE room = ? AND collapse_key = ? AND deleted_at IS NULL AND superseded_by IS NULL
+ORDER BY id DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: NextRoomSequence :execlastid
+-- LAST_INSERT_ID(expr) hands the new value back as the insert id. The row
+-- stays locked until the transaction ends, so sequences in a room are
+-- assigned in commit order.
+INSERT INTO room_sequences (room, seq) VALUES (?, LAST_INSERT_ID(1))
+ON DUPLICATE KEY UPDATE seq = LAST_INSERT_ID(seq + 1);
+
+-- name: SupersedeNotification :exec
+UPDATE notifications SET superseded_by = ? WHERE id = ?;
+
+-- name: ListRoomNotificationsAsc :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = sqlc.arg(room) AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= sqlc.arg(min_priority)
+  AND id < sqlc.arg(before_id) AND id > sqlc.arg(after_id) AND seq > sqlc.arg(after_seq)
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(since) IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until) IS NULL OR created_at < sqlc.narg(until))
+ORDER BY id ASC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListRoomNotificationsBySeq :many
+-- Resync pages walk the room sequence, which imported notifications with
+-- preserved ids do not follow in id order.
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = sqlc.arg(room) AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= sqlc.arg(min_priority)
+  AND id < sqlc.arg(before_id) AND id > sqlc.arg(after_id) AND seq > sqlc.arg(after_seq)
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(since) IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until) IS NULL OR created_at < sqlc.narg(until))
+ORDER BY seq ASC, id ASC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListRoomNotificationsDesc :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = sqlc.arg(room) AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= sqlc.arg(min_priority)
+  AND id < sqlc.arg(before_id) AND id > sqlc.arg(after_id) AND seq > sqlc.arg(after_seq)
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+  AND (sqlc.narg(since) IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until) IS NULL OR created_at < sqlc.narg(until))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ExportNotifications :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE id > sqlc.arg(after_id)
+  AND (sqlc.narg(room) IS NULL OR room = sqlc.narg(room))
+  AND (sqlc.narg(since) IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until) IS NULL OR created_at < sqlc.narg(until))
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ImportNotification :execlastid
+INSERT INTO notifications (id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq) VALUES (sqlc.narg(id), sqlc.arg(room), sqlc.arg(type), sqlc.arg(title), sqlc.arg(body), sqlc.arg(severity), sqlc.arg(data), sqlc.arg(link), sqlc.arg(actions), sqlc.arg(localizations), sqlc.arg(template_key), sqlc.arg(template_params), sqlc.arg(created_at), sqlc.arg(expires_at), sqlc.arg(updated_at), sqlc.arg(deleted_at), sqlc.arg(collapse_key), sqlc.arg(replaces_id), sqlc.arg(superseded_by), sqlc.arg(priority), sqlc.arg(seq));
+
+-- name: ListNotificationRoomTypes :many
+-- Reads idx_notifications_room_type instead of scanning the table.
+SELECT DISTINCT room, type FROM notifications;
+
+-- name: GetNotificationCountCutoffID :one
+SELECT id FROM notifications
+WHERE room = sqlc.arg(room) AND type IN (sqlc.slice(types))
+ORDER BY id DESC
+LIMIT 1 OFFSET sqlc.arg(keep);
+
+-- name: ListPurgeNotificationIDs :many
+-- Locks the batch so its notifications and their pins, deliveries and
+-- escalations are purged together.
+SELECT id FROM notifications
+WHERE room = sqlc.arg(room) AND type IN (sqlc.slice(types))
+  AND ((sqlc.narg(before) IS NOT NULL AND created_at < sqlc.narg(before)) OR id <= sqlc.arg(max_id))
+ORDER BY id
+LIMIT sqlc.arg(purge_limit)
+FOR UPDATE;
+
+-- name: PurgeNotificationPins :exec
+DELETE FROM notification_pins WHERE notification_id IN (sqlc.slice(ids));
+
+-- name: PurgeNotificationDeliveries :exec
+DELETE FROM notification_deliveries WHERE notification_id IN (sqlc.slice(ids));
+
+-- name: PurgeNotificationEscalations :exec
+DELETE FROM notification_escalations WHERE notification_id IN (sqlc.slice(ids));
+
+-- name: PurgeNotifications :execrows
+DELETE FROM notifications WHERE id IN (sqlc.slice(ids));
+
+-- name: SearchNotifications :many
+SELECT sqlc.embed(notifications), CAST(MATCH(title, body) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE) AS DOUBLE) AS score
+FROM notifications
+WHERE MATCH(title, body) AGAINST (sqlc.arg(query) IN NATURAL LANGUAGE MODE)
+  AND deleted_at IS NULL AND superseded_by IS NULL
+  AND (sqlc.narg(room) IS NULL OR room = sqlc.narg(room))
+  AND (sqlc.narg(type) IS NULL OR type = sqlc.narg(type))
+ORDER BY score DESC, id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: ListNotificationTypes :many
+SELECT name, default_severity, default_ttl_seconds, allowed_rooms, updated_at
+FROM notification_types
+ORDER BY name;
+
+-- name: UpsertNotificationType :exec
+INSERT INTO notification_types (name, default_severity, default_ttl_seconds, allowed_rooms)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  default_severity = VALUES(default_severity),
+  default_ttl_seconds = VALUES(default_ttl_seconds),
+  allowed_rooms = VALUES(allowed_rooms);
+
+-- name: DeleteNotificationType :execrows
+DELETE FROM notification_types WHERE name = ?;
+
+-- name: CreateActionInvocation :execresult
+INSERT INTO notification_action_invocations (notification_id, action_id, user_id, invoked_at) VALUES (?, ?, ?, ?);
+
+-- name: DeleteExpiredIdempotencyKey :exec
+-- Unfinished claims expire after their lease so a crashed create does not
+-- block retries for the whole retention window.
+DELETE FROM idempotency_keys
+WHERE idem_key = sqlc.arg(idem_key)
+  AND (created_at < sqlc.arg(expired_before)
+    OR (notification_id IS NULL AND created_at < sqlc.arg(abandoned_before)));
+
+-- name: InsertIdempotencyKey :execrows
+INSERT IGNORE INTO idempotency_keys (idem_key, created_at) VALUES (?, ?);
+
+-- name: GetIdempotencyKey :one
+SELECT idem_key, notification_id, created_at FROM idempotency_keys WHERE idem_key = ?;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys SET notification_id = ? WHERE idem_key = ?;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE idem_key = ?;
+
+-- name: PurgeIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE created_at < ? LIMIT ?;
+
+-- name: CreateRoom :execrows
+INSERT IGNORE INTO rooms (name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetRoom :one
+SELECT name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at
+FROM rooms
+WHERE name = ?;
+
+-- name: ListRooms :many
+SELECT name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at
+FROM rooms
+ORDER BY name;
+
+-- name: UpdateRoom :execrows
+UPDATE rooms
+SET display_name = ?, description = ?, owner = ?, visibility = ?, history_limit = ?, retention_days = ?, retention_max_count = ?, updated_at = ?
+WHERE name = ?;
+
+-- name: DeleteRoom :execrows
+DELETE FROM rooms WHERE name = ?;
+
+-- name: GetUserPreferences :one
+SELECT user_id, muted_rooms, opt_out_types, quiet_hours, updated_at
+FROM user_preferences
+WHERE user_id = ?;
+
+-- name: UpsertUserPreferences :exec
+INSERT INTO user_preferences (user_id, muted_rooms, opt_out_types, quiet_hours, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  muted_rooms = VALUES(muted_rooms),
+  opt_out_types = VALUES(opt_out_types),
+  quiet_hours = VALUES(quiet_hours),
+  updated_at = VALUES(updated_at);
+
+-- name: CreateMaintenanceWindow :execlastid
+INSERT INTO maintenance_windows (room, type, mode, reason, starts_at, ends_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListMaintenanceWindows :many
+SELECT id, room, type, mode, reason, starts_at, ends_at, created_at
+FROM maintenance_windows
+WHERE ends_at > ?
+ORDER BY starts_at, id;
+
+-- name: DeleteMaintenanceWindow :execrows
+DELETE FROM maintenance_windows WHERE id = ?;
+
+-- name: PinNotification :execrows
+INSERT IGNORE INTO notification_pins (notification_id, room, pinned_by, pinned_at)
+VALUES (?, ?, ?, ?);
+
+-- name: LockRoomSequence :one
+-- Locks the sequence row of the room until the transaction ends, so pins in
+-- one room are checked against the limit one at a time.
+SELECT seq FROM room_sequences WHERE room = ? FOR UPDATE;
+
+-- name: GetNotificationPin :one
+SELECT notification_id, room, pinned_by, pinned_at
+FROM notification_pins
+WHERE notification_id = ?;
+
+-- name: CountLivePinnedNotifications :one
+SELECT COUNT(*)
+FROM notification_pins
+JOIN notifications ON notifications.id = notification_pins.notification_id
+WHERE notification_pins.room = ?
+  AND notifications.deleted_at IS NULL AND notifications.superseded_by IS NULL
+  AND (notifications.expires_at IS NULL OR notifications.expires_at > UTC_TIMESTAMP());
+
+-- name: UnpinNotification :execrows
+DELETE FROM notification_pins WHERE notification_id = ?;
+
+-- name: ListPinnedNotifications :many
+SELECT sqlc.embed(notifications), notification_pins.pinned_at
+FROM notification_pins
+JOIN notifications ON notifications.id = notification_pins.notification_id
+WHERE notification_pins.room = ?
+  AND notifications.deleted_at IS NULL AND notifications.superseded_by IS NULL
+  AND (notifications.expires_at IS NULL OR notifications.expires_at > UTC_TIMESTAMP())
+ORDER BY notification_pins.pinned_at DESC, notification_pins.notification_id DESC;
+
+-- name: UpsertRoomState :exec
+INSERT INTO room_state (room, state_key, value, updated_at)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  value = VALUES(value),
+  updated_at = VALUES(updated_at);
+
+-- name: ListRoomState :many
+SELECT room, state_key, value, updated_at
+FROM room_state
 WHERE room = ?
-ORDER BY created_at DESC
+ORDER BY state_key;
+
+-- name: DeleteRoomState :execrows
+DELETE FROM room_state WHERE room = ? AND state_key = ?;
+
+-- name: CreateAnnouncement :execlastid
+INSERT INTO announcements (title, body, severity, link, created_by, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListActiveAnnouncements :many
+SELECT id, title, body, severity, link, created_by, created_at, expires_at
+FROM announcements
+WHERE expires_at > ?
+ORDER BY id;
+
+-- name: DeleteAnnouncement :execrows
+DELETE FROM announcements WHERE id = ?;
+
+-- name: AddNotificationDelivery :exec
+INSERT INTO notification_deliveries (notification_id, user_id, enqueued, dropped, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  enqueued = enqueued + VALUES(enqueued),
+  dropped = dropped + VALUES(dropped),
+  updated_at = VALUES(updated_at);
+
+-- name: ListNotificationDeliveries :many
+SELECT notification_id, user_id, enqueued, dropped, updated_at
+FROM notification_deliveries
+WHERE notification_id = ?
+ORDER BY user_id;
+
+-- name: CreateEscalation :exec
+INSERT IGNORE INTO notification_escalations (notification_id, policy, step, status, due_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListDueEscalations :many
+SELECT notification_id, policy, step, attempts, status, due_at, acked_by, acked_at, created_at, updated_at
+FROM notification_escalations
+WHERE status = 'pending' AND due_at <= ?
+ORDER BY due_at, notification_id
 LIMIT ?;
+
+-- name: ClaimEscalation :execrows
+-- Pushing due_at of a due step past a lease lets only one instance run it.
+-- Each claim counts as an attempt at the step.
+UPDATE notification_escalations
+SET due_at = sqlc.arg(lease_until), attempts = attempts + 1, updated_at = sqlc.arg(updated_at)
+WHERE notification_id = sqlc.arg(notification_id) AND step = sqlc.arg(step)
+  AND status = 'pending' AND due_at <= sqlc.arg(now);
+
+-- name: AdvanceEscalation :execrows
+UPDATE notification_escalations
+SET step = ?, attempts = ?, status = ?, due_at = ?, updated_at = ?
+WHERE notification_id = ? AND status = 'pending';
+
+-- name: AckEscalation :execrows
+UPDATE notification_escalations
+SET status = 'acked', acked_by = ?, acked_at = ?, updated_at = ?
+WHERE notification_id = ? AND status = 'pending';
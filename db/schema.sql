@@ -4,5 +4,138 @@ CREATE TABLE notifications (
   type VARCHAR(64) NOT NULL,
   title VARCHAR(255) NOT NULL,
   body TEXT NOT NULL,
-  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+  severity VARCHAR(32) NOT NULL DEFAULT '',
+  data JSON NULL,
+  link VARCHAR(2048) NOT NULL DEFAULT '',
+  actions JSON NULL,
+  localizations JSON NULL,
+  template_key VARCHAR(128) NOT NULL DEFAULT '',
+  template_params JSON NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NULL,
+  updated_at TIMESTAMP NULL,
+  deleted_at TIMESTAMP NULL,
+  collapse_key VARCHAR(255) NOT NULL DEFAULT '',
+  replaces_id BIGINT NULL,
+  superseded_by BIGINT NULL,
+  priority TINYINT NOT NULL DEFAULT 1,
+  seq BIGINT NOT NULL DEFAULT 0,
+  INDEX idx_notifications_room_collapse_key (room, collapse_key),
+  INDEX idx_notifications_room_id (room, id),
+  INDEX idx_notifications_room_seq (room, seq),
+  INDEX idx_notifications_room_type (room, type),
+  FULLTEXT INDEX ftx_notifications_title_body (title, body)
+);
+
+CREATE TABLE notification_types (
+  name VARCHAR(64) PRIMARY KEY,
+  default_severity VARCHAR(32) NOT NULL DEFAULT '',
+  default_ttl_seconds INT NOT NULL DEFAULT 0,
+  allowed_rooms JSON NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE notification_action_invocations (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  notification_id BIGINT NOT NULL,
+  action_id VARCHAR(64) NOT NULL,
+  user_id VARCHAR(255) NOT NULL,
+  invoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_action_invocations_notification (notification_id)
+);
+
+CREATE TABLE idempotency_keys (
+  idem_key VARCHAR(255) PRIMARY KEY,
+  notification_id BIGINT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_idempotency_keys_created_at (created_at)
+);
+
+CREATE TABLE rooms (
+  name VARCHAR(255) PRIMARY KEY,
+  display_name VARCHAR(255) NOT NULL DEFAULT '',
+  description VARCHAR(1024) NOT NULL DEFAULT '',
+  owner VARCHAR(255) NOT NULL DEFAULT '',
+  visibility VARCHAR(16) NOT NULL DEFAULT 'public',
+  history_limit INT NOT NULL DEFAULT 0,
+  retention_days INT NOT NULL DEFAULT 0,
+  retention_max_count INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_preferences (
+  user_id VARCHAR(255) PRIMARY KEY,
+  muted_rooms JSON NULL,
+  opt_out_types JSON NULL,
+  quiet_hours JSON NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE maintenance_windows (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  room VARCHAR(255) NOT NULL,
+  type VARCHAR(64) NOT NULL DEFAULT '',
+  mode VARCHAR(16) NOT NULL,
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  starts_at TIMESTAMP NOT NULL,
+  ends_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_maintenance_windows_ends_at (ends_at)
+);
+
+CREATE TABLE notification_pins (
+  notification_id BIGINT PRIMARY KEY,
+  room VARCHAR(255) NOT NULL,
+  pinned_by VARCHAR(255) NOT NULL DEFAULT '',
+  pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_notification_pins_room (room, pinned_at)
+);
+
+CREATE TABLE room_state (
+  room VARCHAR(255) NOT NULL,
+  state_key VARCHAR(128) NOT NULL,
+  value JSON NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room, state_key)
+);
+
+CREATE TABLE announcements (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  title VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  severity VARCHAR(32) NOT NULL DEFAULT '',
+  link VARCHAR(2048) NOT NULL DEFAULT '',
+  created_by VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NOT NULL,
+  INDEX idx_announcements_expires_at (expires_at)
+);
+
+CREATE TABLE room_sequences (
+  room VARCHAR(255) NOT NULL PRIMARY KEY,
+  seq BIGINT NOT NULL
+);
+
+CREATE TABLE notification_deliveries (
+  notification_id BIGINT NOT NULL,
+  user_id VARCHAR(255) NOT NULL DEFAULT '',
+  enqueued INT NOT NULL DEFAULT 0,
+  dropped INT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (notification_id, user_id)
+);
+
+CREATE TABLE notification_escalations (
+  notification_id BIGINT PRIMARY KEY,
+  policy VARCHAR(64) NOT NULL,
+  step INT NOT NULL DEFAULT 0,
+  attempts INT NOT NULL DEFAULT 0,
+  status VARCHAR(16) NOT NULL,
+  due_at TIMESTAMP NOT NULL,
+  acked_by VARCHAR(255) NOT NULL DEFAULT '',
+  acked_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_notification_escalations_due (status, due_at)
 );
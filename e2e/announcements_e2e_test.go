@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	httpserver "sse_demo/internal/http"
+	"sse_demo/internal/http/controller"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestSSEAnnouncements(t *testing.T) {
+	ginTestMode()
+
+	cfg := &config.Config{
+		HTTPAddr:     ":0",
+		SSEHeartbeat: 5 * time.Second,
+		HistoryLimit: 10,
+		AdminToken:   "secret",
+	}
+	logger := zap.NewNop()
+	repo := memory.New(logger)
+	hub := sse.NewHub()
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
+	router := httpserver.NewRouter(handler, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	announce := func(token, title string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/announcements", bytes.NewBufferString(`{"title":"`+title+`","body":"b"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusUnauthorized, announce("wrong", "nope"))
+	require.Equal(t, http.StatusUnauthorized, announce("", "nope"))
+	require.Equal(t, http.StatusCreated, announce("secret", "stored"))
+
+	// Without ADMIN_TOKEN nobody can broadcast.
+	unset := *cfg
+	unset.AdminToken = ""
+	rec := httptest.NewRecorder()
+	httpserver.NewRouter(handler, logger, &unset).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/announcements", bytes.NewBufferString(`{"title":"t","body":"b"}`)))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	readAnnouncement := func(reader *bufio.Reader) model.Announcement {
+		t.Helper()
+		data, err := readSSEData(reader, 2*time.Second)
+		require.NoError(t, err)
+		var got model.Announcement
+		require.NoError(t, json.Unmarshal([]byte(data), &got))
+		return got
+	}
+
+	// Clients of different rooms both get the active announcement on
+	// connect and each later one exactly once.
+	var readers []*bufio.Reader
+	for _, room := range []string{"room-a", "room-b"} {
+		resp, err := http.Get(server.URL + "/sse/" + room)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		reader := bufio.NewReader(resp.Body)
+		require.Equal(t, "stored", readAnnouncement(reader).Title)
+		readers = append(readers, reader)
+	}
+
+	require.Equal(t, http.StatusCreated, announce("secret", "live"))
+	for _, reader := range readers {
+		require.Equal(t, "live", readAnnouncement(reader).Title)
+		_, err := readSSEData(reader, 200*time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+}
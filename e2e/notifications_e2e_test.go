@@ -39,6 +39,11 @@ func (n *noopPublisher) Publish(ctx context.Context, payload []byte, routingKey
 	return nil
 }
 
+func (n *noopPublisher) PublishBatch(ctx context.Context, messages []queue.Message) (int, error) {
+	_ = ctx
+	return len(messages), nil
+}
+
 func TestSSEFlow(t *testing.T) {
 	ginTestMode()
 
@@ -50,8 +55,11 @@ func TestSSEFlow(t *testing.T) {
 	logger := zap.NewNop()
 	repo := memory.New(logger)
 	hub := sse.NewHub()
-	svc := notify.NewService(repo, hub, logger)
-	handler := controller.NewHandler(cfg, svc, hub, logger, queue.Publisher(&noopPublisher{}))
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
 	router := httpserver.NewRouter(handler, logger, cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
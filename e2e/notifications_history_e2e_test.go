@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -33,8 +34,11 @@ func TestSSEHistoryBackfill(t *testing.T) {
 	logger := zap.NewNop()
 	repo := memory.New(logger)
 	hub := sse.NewHub()
-	svc := notify.NewService(repo, hub, logger)
-	handler := controller.NewHandler(cfg, svc, hub, logger, queue.Publisher(&noopPublisher{}))
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
 	router := httpserver.NewRouter(handler, logger, cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -73,3 +77,78 @@ func TestSSEHistoryBackfill(t *testing.T) {
 	require.Equal(t, payload["title"], got.Title)
 	require.Equal(t, payload["body"], got.Body)
 }
+
+func TestSSEResyncAfterSeq(t *testing.T) {
+	ginTestMode()
+
+	cfg := &config.Config{
+		HTTPAddr:     ":0",
+		SSEHeartbeat: 5 * time.Second,
+		HistoryLimit: 10,
+		DigestRules:  []config.DigestRule{{Room: "room-1", Type: domain.NotificationTypeSystem, Window: time.Hour}},
+	}
+	logger := zap.NewNop()
+	repo := memory.New(logger)
+	hub := sse.NewHub()
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	digests := notify.NewDigester(cfg, repo, hub, logger)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, digests, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
+	router := httpserver.NewRouter(handler, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	post := func(room, notificationType string) model.Notification {
+		body, err := json.Marshal(map[string]string{
+			"room":  room,
+			"type":  notificationType,
+			"title": "t",
+			"body":  "b",
+		})
+		require.NoError(t, err)
+		resp, err := http.Post(server.URL+"/notifications", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created model.Notification
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		return created
+	}
+	for range 4 {
+		post("room-1", domain.NotificationTypeInfo)
+	}
+	// Sequences are per room, unlike ids.
+	other := post("room-2", domain.NotificationTypeInfo)
+	require.Equal(t, int64(1), other.Seq)
+	// Held back by the digest rule, so the resync leaves it out as the live
+	// stream did.
+	held := post("room-1", domain.NotificationTypeSystem)
+	require.Equal(t, int64(5), held.Seq)
+
+	sseResp, err := http.Get(server.URL + "/sse/room-1?after_seq=2")
+	require.NoError(t, err)
+	defer func() { _ = sseResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, sseResp.StatusCode)
+	reader := bufio.NewReader(sseResp.Body)
+
+	readSeq := func() int64 {
+		t.Helper()
+		data, err := readSSEData(reader, 2*time.Second)
+		require.NoError(t, err)
+		var got model.Notification
+		require.NoError(t, json.Unmarshal([]byte(data), &got))
+		return got.Seq
+	}
+	require.Equal(t, int64(3), readSeq())
+	require.Equal(t, int64(4), readSeq())
+
+	post("room-1", domain.NotificationTypeInfo)
+	require.Equal(t, int64(6), readSeq())
+}
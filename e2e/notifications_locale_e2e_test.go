@@ -0,0 +1,84 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	httpserver "sse_demo/internal/http"
+	"sse_demo/internal/http/controller"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestSSELocalizedHistory(t *testing.T) {
+	ginTestMode()
+
+	cfg := &config.Config{
+		HTTPAddr:      ":0",
+		SSEHeartbeat:  5 * time.Second,
+		HistoryLimit:  10,
+		DefaultLocale: "en",
+	}
+	logger := zap.NewNop()
+	repo := memory.New(logger)
+	hub := sse.NewHub()
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
+	router := httpserver.NewRouter(handler, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	body, err := json.Marshal(map[string]any{
+		"room":  "room-1",
+		"type":  domain.NotificationTypeInfo,
+		"title": "hello",
+		"body":  "world",
+		"localizations": map[string]any{
+			"zh-TW": map[string]string{"title": "你好", "body": "世界"},
+		},
+	})
+	require.NoError(t, err)
+
+	postResp, err := http.Post(server.URL+"/notifications", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = postResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, postResp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/sse/room-1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Language", "zh-TW,zh;q=0.9,en;q=0.5")
+	sseResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = sseResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, sseResp.StatusCode)
+
+	data, err := readSSEData(sseResp.Body, 2*time.Second)
+	require.NoError(t, err)
+
+	var got model.Notification
+	require.NoError(t, json.Unmarshal([]byte(data), &got))
+	require.Equal(t, "zh-TW", got.Locale)
+	require.Equal(t, "你好", got.Title)
+	require.Equal(t, "世界", got.Body)
+	require.Empty(t, got.Localizations)
+}
@@ -0,0 +1,93 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	httpserver "sse_demo/internal/http"
+	"sse_demo/internal/http/controller"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestSSEPinnedFirst(t *testing.T) {
+	ginTestMode()
+
+	cfg := &config.Config{
+		HTTPAddr:       ":0",
+		SSEHeartbeat:   5 * time.Second,
+		HistoryLimit:   3,
+		PinsMaxPerRoom: 1,
+	}
+	logger := zap.NewNop()
+	repo := memory.New(logger)
+	hub := sse.NewHub()
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
+	router := httpserver.NewRouter(handler, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	var ids []int64
+	for _, title := range []string{"pinned", "second", "third"} {
+		body, err := json.Marshal(map[string]string{"room": "room-1", "type": domain.NotificationTypeInfo, "title": title, "body": "body"})
+		require.NoError(t, err)
+		postResp, err := http.Post(server.URL+"/notifications", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		var created model.Notification
+		require.NoError(t, json.NewDecoder(postResp.Body).Decode(&created))
+		_ = postResp.Body.Close()
+		require.Equal(t, http.StatusCreated, postResp.StatusCode)
+		ids = append(ids, created.ID)
+	}
+
+	pin := func(id int64) int {
+		resp, err := http.Post(fmt.Sprintf("%s/notifications/%d/pin", server.URL, id), "application/json", nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusOK, pin(ids[0]))
+	require.Equal(t, http.StatusOK, pin(ids[0]))
+	require.Equal(t, http.StatusConflict, pin(ids[1]))
+
+	sseResp, err := http.Get(server.URL + "/sse/room-1")
+	require.NoError(t, err)
+	defer func() { _ = sseResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, sseResp.StatusCode)
+
+	reader := bufio.NewReader(sseResp.Body)
+	for i, want := range []string{"pinned", "second", "third"} {
+		data, err := readSSEData(reader, 2*time.Second)
+		require.NoError(t, err)
+		var got model.Notification
+		require.NoError(t, json.Unmarshal([]byte(data), &got))
+		require.Equal(t, want, got.Title)
+		require.Equal(t, i == 0, got.Pinned)
+	}
+	// The pinned notification is not repeated in the history.
+	_, err = readSSEData(reader, 200*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -48,9 +48,11 @@ func TestPublishFlow(t *testing.T) {
 	logger := zap.NewNop()
 	repo := memory.New(logger)
 	hub := sse.NewHub()
-	svc := notify.NewService(repo, hub, logger)
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
 	publisher := rabbitmq.NewPublisher(cfg, logger)
-	consumer := rabbitmq.NewConsumer(cfg, svc, logger)
+	consumer := rabbitmq.NewConsumer(cfg, svc, notify.NewAnnouncementService(repo, hub, logger), logger)
 
 	consumeCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -61,7 +63,8 @@ func TestPublishFlow(t *testing.T) {
 
 	require.NoError(t, waitForConsumer(ctx, amqpURL, cfg.RabbitQueue, 5*time.Second))
 
-	handler := controller.NewHandler(cfg, svc, hub, logger, publisher)
+	actions := notify.NewActionService(cfg, repo, repo, publisher, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, publisher)
 	router := httpserver.NewRouter(handler, logger, cfg)
 
 	hubCtx, hubCancel := context.WithCancel(ctx)
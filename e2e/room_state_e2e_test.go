@@ -0,0 +1,108 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	httpserver "sse_demo/internal/http"
+	"sse_demo/internal/http/controller"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestSSERoomState(t *testing.T) {
+	ginTestMode()
+
+	cfg := &config.Config{
+		HTTPAddr:     ":0",
+		SSEHeartbeat: 5 * time.Second,
+		HistoryLimit: 10,
+	}
+	logger := zap.NewNop()
+	repo := memory.New(logger)
+	hub := sse.NewHub()
+	types, err := notify.NewTypeService(cfg, repo, logger)
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, repo, repo, hub, notify.NewTypeRegistry(types), nil, nil, nil, nil, nil, logger)
+	actions := notify.NewActionService(cfg, repo, repo, &noopPublisher{}, hub, logger)
+	handler := controller.NewHandler(cfg, svc, types, actions, notify.NewTransferService(cfg, repo, repo, notify.NewTypeRegistry(types), logger), notify.NewRoomService(repo, logger), notify.NewPreferenceService(repo, hub, logger), notify.NewMaintenanceService(cfg, repo, hub, logger), notify.NewPinService(cfg, repo, repo, hub, logger), notify.NewStateService(repo, hub, logger), notify.NewAnnouncementService(repo, hub, logger), notify.NewDeliveryTracker(repo, repo, hub, logger), nil, hub, logger, queue.Publisher(&noopPublisher{}))
+	router := httpserver.NewRouter(handler, logger, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	put := func(key, value string) {
+		req, err := http.NewRequest(http.MethodPut, server.URL+"/rooms/ci/state/"+key, bytes.NewBufferString(`{"value":`+value+`}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	put("build_status", `"red"`)
+	put("build_status", `"green"`)
+	put("coverage", `81.5`)
+
+	sseResp, err := http.Get(server.URL + "/sse/ci")
+	require.NoError(t, err)
+	defer func() { _ = sseResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, sseResp.StatusCode)
+	reader := bufio.NewReader(sseResp.Body)
+
+	readState := func() notify.StateEvent {
+		t.Helper()
+		var event string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			line = strings.TrimRight(line, "\n")
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				require.Equal(t, sse.EventState, event)
+				var got notify.StateEvent
+				require.NoError(t, json.Unmarshal([]byte(data), &got))
+				return got
+			}
+		}
+	}
+
+	snapshot := readState()
+	require.True(t, snapshot.Snapshot)
+	require.Len(t, snapshot.State, 2)
+	require.JSONEq(t, `"green"`, string(snapshot.State["build_status"].Value))
+	require.JSONEq(t, `81.5`, string(snapshot.State["coverage"].Value))
+
+	put("build_status", `"red"`)
+	change := readState()
+	require.False(t, change.Snapshot)
+	require.JSONEq(t, `"red"`, string(change.State["build_status"].Value))
+
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/rooms/ci/state/coverage", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	change = readState()
+	require.Contains(t, change.State, "coverage")
+	require.Nil(t, change.State["coverage"])
+}
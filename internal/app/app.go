@@ -9,23 +9,40 @@ import (
 	"go.uber.org/zap"
 	"sse_demo/internal/config"
 	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
 )
 
 type App struct {
-	cfg      *config.Config
-	hub      *sse.Hub
-	consumer queue.Consumer
-	server   *http.Server
-	logger   *zap.Logger
-	wg       sync.WaitGroup
+	cfg         *config.Config
+	hub         *sse.Hub
+	types       *notify.TypeService
+	consumer    queue.Consumer
+	purger      *notify.IdempotencyPurger
+	retention   *notify.RetentionPurger
+	digests     *notify.Digester
+	maintenance *notify.MaintenanceService
+	deliveries  *notify.DeliveryTracker
+	escalations *notify.EscalationService
+	hooks       *notify.Hooks
+	server      *http.Server
+	logger      *zap.Logger
+	wg          sync.WaitGroup
 }
 
-func NewApp(cfg *config.Config, hub *sse.Hub, consumer queue.Consumer, router *gin.Engine, logger *zap.Logger) *App {
+func NewApp(cfg *config.Config, hub *sse.Hub, types *notify.TypeService, consumer queue.Consumer, purger *notify.IdempotencyPurger, retention *notify.RetentionPurger, digests *notify.Digester, maintenance *notify.MaintenanceService, deliveries *notify.DeliveryTracker, escalations *notify.EscalationService, hooks *notify.Hooks, router *gin.Engine, logger *zap.Logger) *App {
 	return &App{
-		cfg:      cfg,
-		hub:      hub,
-		consumer: consumer,
+		cfg:         cfg,
+		hub:         hub,
+		types:       types,
+		consumer:    consumer,
+		purger:      purger,
+		retention:   retention,
+		digests:     digests,
+		maintenance: maintenance,
+		deliveries:  deliveries,
+		escalations: escalations,
+		hooks:       hooks,
 		server: &http.Server{
 			Addr:    cfg.HTTPAddr,
 			Handler: router,
@@ -41,6 +58,48 @@ func (a *App) Run(ctx context.Context) error {
 		a.hub.Run(ctx)
 	}()
 
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.types.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.purger.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.retention.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.digests.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.maintenance.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.deliveries.Run(ctx)
+	}()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.escalations.Run(ctx)
+	}()
+
 	a.wg.Add(1)
 	go func() {
 		defer a.wg.Done()
@@ -58,6 +117,9 @@ func (a *App) Shutdown(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() {
 		a.wg.Wait()
+		// Requests and messages have drained, so no new after-create hooks
+		// start; let the running ones finish.
+		a.hooks.Wait()
 		close(done)
 	}()
 
@@ -3,11 +3,31 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// DigestRule opts notifications of Type in rooms matching the Room glob into
+// a periodic digest emitted every Window.
+type DigestRule struct {
+	Room   string
+	Type   string
+	Window time.Duration
+}
+
+// RetentionRule limits how long notifications of Type in rooms matching the
+// Room glob are kept; Type "*" matches every type. MaxCount keeps only the
+// newest notifications the rule covers in each room. A zero MaxAge or
+// MaxCount leaves that limit off, so a rule with neither keeps everything.
+type RetentionRule struct {
+	Room     string
+	Type     string
+	MaxAge   time.Duration
+	MaxCount int
+}
+
 type Config struct {
 	HTTPAddr     string
 	MySQLDSN     string
@@ -17,8 +37,25 @@ type Config struct {
 	RabbitRoutingKey   string
 	RabbitConsumerTag  string
 	RabbitPublishPrefix string
+	RabbitActionPrefix  string
+	RabbitAnnouncementRoutingKey string
 	SSEHeartbeat time.Duration
 	HistoryLimit int
+	DefaultLocale string
+	NotificationTemplatesPath string
+	NotificationTypesPath string
+	EscalationPoliciesPath string
+	NotificationSanitize string
+	AdminToken string
+	RoomsStrict bool
+	PinsMaxPerRoom int
+	SuppressionDeferLimit int
+	IdempotencyRetention time.Duration
+	BatchMaxSize int
+	DigestRules []DigestRule
+	RetentionMaxAge time.Duration
+	RetentionMaxPerRoom int
+	RetentionRules []RetentionRule
 	OTELServiceName string
 	OTLPEndpoint    string
 	OTLPInsecure    bool
@@ -31,11 +68,19 @@ func New() *Config {
 		HTTPAddr:     ":8080",
 		SSEHeartbeat: 15 * time.Second,
 		HistoryLimit: 20,
+		PinsMaxPerRoom: 5,
+		SuppressionDeferLimit: 10,
+		DefaultLocale: "en",
+		NotificationSanitize: "none",
+		IdempotencyRetention: 24 * time.Hour,
+		BatchMaxSize: 500,
 		RabbitExchange:     "notifications",
 		RabbitQueue:        "notifications.sse",
 		RabbitRoutingKey:   "notification.*",
 		RabbitConsumerTag:  "sse-consumer",
 		RabbitPublishPrefix: "notification",
+		RabbitActionPrefix:  "action.invoked",
+		RabbitAnnouncementRoutingKey: "announcement.broadcast",
 		OTELServiceName: "sse-demo",
 		OTLPInsecure:    true,
 	}
@@ -64,6 +109,12 @@ func New() *Config {
 	if v := os.Getenv("RABBITMQ_PUBLISH_PREFIX"); v != "" {
 		cfg.RabbitPublishPrefix = v
 	}
+	if v := os.Getenv("RABBITMQ_ACTION_PREFIX"); v != "" {
+		cfg.RabbitActionPrefix = v
+	}
+	if v := os.Getenv("RABBITMQ_ANNOUNCEMENT_ROUTING_KEY"); v != "" {
+		cfg.RabbitAnnouncementRoutingKey = v
+	}
 
 	if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" {
 		cfg.OTELServiceName = v
@@ -89,5 +140,101 @@ func New() *Config {
 		}
 	}
 
+	if v := os.Getenv("DEFAULT_LOCALE"); v != "" {
+		cfg.DefaultLocale = v
+	}
+	cfg.NotificationTemplatesPath = os.Getenv("NOTIFICATION_TEMPLATES_PATH")
+	cfg.NotificationTypesPath = os.Getenv("NOTIFICATION_TYPES_PATH")
+	cfg.EscalationPoliciesPath = os.Getenv("ESCALATION_POLICIES_PATH")
+	if v := os.Getenv("NOTIFICATION_SANITIZE"); v != "" {
+		cfg.NotificationSanitize = v
+	}
+	cfg.AdminToken = os.Getenv("ADMIN_TOKEN")
+	if v := os.Getenv("ROOMS_STRICT"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.RoomsStrict = b
+		}
+	}
+
+	if v := os.Getenv("PINS_MAX_PER_ROOM"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.PinsMaxPerRoom = n
+		}
+	}
+	// SUPPRESSION_DEFER_LIMIT caps how many notifications a maintenance
+	// window or quiet hours in defer mode replay when they end; the summary
+	// frame reports how many were replayed and the rest stay in history.
+	if v := os.Getenv("SUPPRESSION_DEFER_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.SuppressionDeferLimit = n
+		}
+	}
+
+	if v := os.Getenv("IDEMPOTENCY_RETENTION_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.IdempotencyRetention = time.Duration(n) * time.Hour
+		}
+	}
+
+	cfg.DigestRules = parseDigestRules(os.Getenv("DIGEST_RULES"))
+
+	if v := os.Getenv("NOTIFICATION_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.RetentionMaxAge = time.Duration(n) * 24 * time.Hour
+		}
+	}
+	if v := os.Getenv("NOTIFICATION_RETENTION_MAX_PER_ROOM"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.RetentionMaxPerRoom = n
+		}
+	}
+	cfg.RetentionRules = parseRetentionRules(os.Getenv("RETENTION_RULES"))
+
+	if v := os.Getenv("NOTIFICATION_BATCH_MAX_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.BatchMaxSize = n
+		}
+	}
+
 	return cfg
 }
+
+// parseDigestRules reads comma separated "room:type:minutes" entries, e.g.
+// "ops-*:info:15". Malformed entries are skipped.
+func parseDigestRules(v string) []DigestRule {
+	var rules []DigestRule
+	for _, entry := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(parts[2])
+		if err != nil || minutes <= 0 {
+			continue
+		}
+		rules = append(rules, DigestRule{Room: parts[0], Type: parts[1], Window: time.Duration(minutes) * time.Minute})
+	}
+	return rules
+}
+
+// parseRetentionRules reads comma separated "room:type:days:count" entries,
+// e.g. "audit-*:*:365:0,ops:info:7:500". Malformed entries are skipped.
+func parseRetentionRules(v string) []RetentionRule {
+	var rules []RetentionRule
+	for _, entry := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 4 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		days, err := strconv.Atoi(parts[2])
+		if err != nil || days < 0 {
+			continue
+		}
+		count, err := strconv.Atoi(parts[3])
+		if err != nil || count < 0 {
+			continue
+		}
+		rules = append(rules, RetentionRule{Room: parts[0], Type: parts[1], MaxAge: time.Duration(days) * 24 * time.Hour, MaxCount: count})
+	}
+	return rules
+}
@@ -5,14 +5,136 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
-type Notification struct {
+type Announcement struct {
 	ID        int64     `json:"id"`
-	Room      string    `json:"room"`
-	Type      string    `json:"type"`
 	Title     string    `json:"title"`
 	Body      string    `json:"body"`
+	Severity  string    `json:"severity"`
+	Link      string    `json:"link"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type IdempotencyKey struct {
+	IdemKey        string        `json:"idem_key"`
+	NotificationID sql.NullInt64 `json:"notification_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type MaintenanceWindow struct {
+	ID        int64     `json:"id"`
+	Room      string    `json:"room"`
+	Type      string    `json:"type"`
+	Mode      string    `json:"mode"`
+	Reason    string    `json:"reason"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+type Notification struct {
+	ID             int64           `json:"id"`
+	Room           string          `json:"room"`
+	Type           string          `json:"type"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	Severity       string          `json:"severity"`
+	Data           json.RawMessage `json:"data"`
+	Link           string          `json:"link"`
+	Actions        json.RawMessage `json:"actions"`
+	Localizations  json.RawMessage `json:"localizations"`
+	TemplateKey    string          `json:"template_key"`
+	TemplateParams json.RawMessage `json:"template_params"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ExpiresAt      sql.NullTime    `json:"expires_at"`
+	UpdatedAt      sql.NullTime    `json:"updated_at"`
+	DeletedAt      sql.NullTime    `json:"deleted_at"`
+	CollapseKey    string          `json:"collapse_key"`
+	ReplacesID     sql.NullInt64   `json:"replaces_id"`
+	SupersededBy   sql.NullInt64   `json:"superseded_by"`
+	Priority       int8            `json:"priority"`
+	Seq            int64           `json:"seq"`
+}
+
+type NotificationActionInvocation struct {
+	ID             int64     `json:"id"`
+	NotificationID int64     `json:"notification_id"`
+	ActionID       string    `json:"action_id"`
+	UserID         string    `json:"user_id"`
+	InvokedAt      time.Time `json:"invoked_at"`
+}
+
+type NotificationDelivery struct {
+	NotificationID int64     `json:"notification_id"`
+	UserID         string    `json:"user_id"`
+	Enqueued       int32     `json:"enqueued"`
+	Dropped        int32     `json:"dropped"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type NotificationEscalation struct {
+	NotificationID int64        `json:"notification_id"`
+	Policy         string       `json:"policy"`
+	Step           int32        `json:"step"`
+	Attempts       int32        `json:"attempts"`
+	Status         string       `json:"status"`
+	DueAt          time.Time    `json:"due_at"`
+	AckedBy        string       `json:"acked_by"`
+	AckedAt        sql.NullTime `json:"acked_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+type NotificationPin struct {
+	NotificationID int64     `json:"notification_id"`
+	Room           string    `json:"room"`
+	PinnedBy       string    `json:"pinned_by"`
+	PinnedAt       time.Time `json:"pinned_at"`
+}
+
+type NotificationType struct {
+	Name              string          `json:"name"`
+	DefaultSeverity   string          `json:"default_severity"`
+	DefaultTtlSeconds int32           `json:"default_ttl_seconds"`
+	AllowedRooms      json.RawMessage `json:"allowed_rooms"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+type Room struct {
+	Name              string    `json:"name"`
+	DisplayName       string    `json:"display_name"`
+	Description       string    `json:"description"`
+	Owner             string    `json:"owner"`
+	Visibility        string    `json:"visibility"`
+	HistoryLimit      int32     `json:"history_limit"`
+	RetentionDays     int32     `json:"retention_days"`
+	RetentionMaxCount int32     `json:"retention_max_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type RoomSequence struct {
+	Room string `json:"room"`
+	Seq  int64  `json:"seq"`
+}
+
+type RoomState struct {
+	Room      string          `json:"room"`
+	StateKey  string          `json:"state_key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type UserPreference struct {
+	UserID      string          `json:"user_id"`
+	MutedRooms  json.RawMessage `json:"muted_rooms"`
+	OptOutTypes json.RawMessage `json:"opt_out_types"`
+	QuietHours  json.RawMessage `json:"quiet_hours"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
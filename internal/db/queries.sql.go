@@ -8,17 +8,288 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
 )
 
+const ackEscalation = `-- name: AckEscalation :execrows
+UPDATE notification_escalations
+SET status = 'acked', acked_by = ?, acked_at = ?, updated_at = ?
+WHERE notification_id = ? AND status = 'pending'
+`
+
+type AckEscalationParams struct {
+	AckedBy        string       `json:"acked_by"`
+	AckedAt        sql.NullTime `json:"acked_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	NotificationID int64        `json:"notification_id"`
+}
+
+func (q *Queries) AckEscalation(ctx context.Context, arg AckEscalationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, ackEscalation,
+		arg.AckedBy,
+		arg.AckedAt,
+		arg.UpdatedAt,
+		arg.NotificationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const addNotificationDelivery = `-- name: AddNotificationDelivery :exec
+INSERT INTO notification_deliveries (notification_id, user_id, enqueued, dropped, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  enqueued = enqueued + VALUES(enqueued),
+  dropped = dropped + VALUES(dropped),
+  updated_at = VALUES(updated_at)
+`
+
+type AddNotificationDeliveryParams struct {
+	NotificationID int64     `json:"notification_id"`
+	UserID         string    `json:"user_id"`
+	Enqueued       int32     `json:"enqueued"`
+	Dropped        int32     `json:"dropped"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (q *Queries) AddNotificationDelivery(ctx context.Context, arg AddNotificationDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, addNotificationDelivery,
+		arg.NotificationID,
+		arg.UserID,
+		arg.Enqueued,
+		arg.Dropped,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const advanceEscalation = `-- name: AdvanceEscalation :execrows
+UPDATE notification_escalations
+SET step = ?, attempts = ?, status = ?, due_at = ?, updated_at = ?
+WHERE notification_id = ? AND status = 'pending'
+`
+
+type AdvanceEscalationParams struct {
+	Step           int32     `json:"step"`
+	Attempts       int32     `json:"attempts"`
+	Status         string    `json:"status"`
+	DueAt          time.Time `json:"due_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	NotificationID int64     `json:"notification_id"`
+}
+
+func (q *Queries) AdvanceEscalation(ctx context.Context, arg AdvanceEscalationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, advanceEscalation,
+		arg.Step,
+		arg.Attempts,
+		arg.Status,
+		arg.DueAt,
+		arg.UpdatedAt,
+		arg.NotificationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const claimEscalation = `-- name: ClaimEscalation :execrows
+UPDATE notification_escalations
+SET due_at = ?, attempts = attempts + 1, updated_at = ?
+WHERE notification_id = ? AND step = ?
+  AND status = 'pending' AND due_at <= ?
+`
+
+type ClaimEscalationParams struct {
+	LeaseUntil     time.Time `json:"lease_until"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	NotificationID int64     `json:"notification_id"`
+	Step           int32     `json:"step"`
+	Now            time.Time `json:"now"`
+}
+
+// Pushing due_at of a due step past a lease lets only one instance run it.
+// Each claim counts as an attempt at the step.
+func (q *Queries) ClaimEscalation(ctx context.Context, arg ClaimEscalationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimEscalation,
+		arg.LeaseUntil,
+		arg.UpdatedAt,
+		arg.NotificationID,
+		arg.Step,
+		arg.Now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys SET notification_id = ? WHERE idem_key = ?
+`
+
+type CompleteIdempotencyKeyParams struct {
+	NotificationID sql.NullInt64 `json:"notification_id"`
+	IdemKey        string        `json:"idem_key"`
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, completeIdempotencyKey, arg.NotificationID, arg.IdemKey)
+	return err
+}
+
+const countLivePinnedNotifications = `-- name: CountLivePinnedNotifications :one
+SELECT COUNT(*)
+FROM notification_pins
+JOIN notifications ON notifications.id = notification_pins.notification_id
+WHERE notification_pins.room = ?
+  AND notifications.deleted_at IS NULL AND notifications.superseded_by IS NULL
+  AND (notifications.expires_at IS NULL OR notifications.expires_at > UTC_TIMESTAMP())
+`
+
+func (q *Queries) CountLivePinnedNotifications(ctx context.Context, room string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLivePinnedNotifications, room)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createActionInvocation = `-- name: CreateActionInvocation :execresult
+INSERT INTO notification_action_invocations (notification_id, action_id, user_id, invoked_at) VALUES (?, ?, ?, ?)
+`
+
+type CreateActionInvocationParams struct {
+	NotificationID int64     `json:"notification_id"`
+	ActionID       string    `json:"action_id"`
+	UserID         string    `json:"user_id"`
+	InvokedAt      time.Time `json:"invoked_at"`
+}
+
+func (q *Queries) CreateActionInvocation(ctx context.Context, arg CreateActionInvocationParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createActionInvocation,
+		arg.NotificationID,
+		arg.ActionID,
+		arg.UserID,
+		arg.InvokedAt,
+	)
+}
+
+const createAnnouncement = `-- name: CreateAnnouncement :execlastid
+INSERT INTO announcements (title, body, severity, link, created_by, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateAnnouncementParams struct {
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Severity  string    `json:"severity"`
+	Link      string    `json:"link"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateAnnouncement(ctx context.Context, arg CreateAnnouncementParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createAnnouncement,
+		arg.Title,
+		arg.Body,
+		arg.Severity,
+		arg.Link,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createEscalation = `-- name: CreateEscalation :exec
+INSERT IGNORE INTO notification_escalations (notification_id, policy, step, status, due_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateEscalationParams struct {
+	NotificationID int64     `json:"notification_id"`
+	Policy         string    `json:"policy"`
+	Step           int32     `json:"step"`
+	Status         string    `json:"status"`
+	DueAt          time.Time `json:"due_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateEscalation(ctx context.Context, arg CreateEscalationParams) error {
+	_, err := q.db.ExecContext(ctx, createEscalation,
+		arg.NotificationID,
+		arg.Policy,
+		arg.Step,
+		arg.Status,
+		arg.DueAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const createMaintenanceWindow = `-- name: CreateMaintenanceWindow :execlastid
+INSERT INTO maintenance_windows (room, type, mode, reason, starts_at, ends_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateMaintenanceWindowParams struct {
+	Room      string    `json:"room"`
+	Type      string    `json:"type"`
+	Mode      string    `json:"mode"`
+	Reason    string    `json:"reason"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateMaintenanceWindow(ctx context.Context, arg CreateMaintenanceWindowParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createMaintenanceWindow,
+		arg.Room,
+		arg.Type,
+		arg.Mode,
+		arg.Reason,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
 const createNotification = `-- name: CreateNotification :execresult
-INSERT INTO notifications (room, type, title, body) VALUES (?, ?, ?, ?)
+INSERT INTO notifications (room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, collapse_key, replaces_id, priority, seq) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateNotificationParams struct {
-	Room  string `json:"room"`
-	Type  string `json:"type"`
-	Title string `json:"title"`
-	Body  string `json:"body"`
+	Room           string          `json:"room"`
+	Type           string          `json:"type"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	Severity       string          `json:"severity"`
+	Data           json.RawMessage `json:"data"`
+	Link           string          `json:"link"`
+	Actions        json.RawMessage `json:"actions"`
+	Localizations  json.RawMessage `json:"localizations"`
+	TemplateKey    string          `json:"template_key"`
+	TemplateParams json.RawMessage `json:"template_params"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ExpiresAt      sql.NullTime    `json:"expires_at"`
+	CollapseKey    string          `json:"collapse_key"`
+	ReplacesID     sql.NullInt64   `json:"replaces_id"`
+	Priority       int8            `json:"priority"`
+	Seq            int64           `json:"seq"`
 }
 
 func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (sql.Result, error) {
@@ -27,24 +298,183 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		arg.Type,
 		arg.Title,
 		arg.Body,
+		arg.Severity,
+		arg.Data,
+		arg.Link,
+		arg.Actions,
+		arg.Localizations,
+		arg.TemplateKey,
+		arg.TemplateParams,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.CollapseKey,
+		arg.ReplacesID,
+		arg.Priority,
+		arg.Seq,
 	)
 }
 
-const listNotificationsByRoom = `-- name: ListNotificationsByRoom :many
-SELECT id, room, type, title, body, created_at
+const createRoom = `-- name: CreateRoom :execrows
+INSERT IGNORE INTO rooms (name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateRoomParams struct {
+	Name              string    `json:"name"`
+	DisplayName       string    `json:"display_name"`
+	Description       string    `json:"description"`
+	Owner             string    `json:"owner"`
+	Visibility        string    `json:"visibility"`
+	HistoryLimit      int32     `json:"history_limit"`
+	RetentionDays     int32     `json:"retention_days"`
+	RetentionMaxCount int32     `json:"retention_max_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createRoom,
+		arg.Name,
+		arg.DisplayName,
+		arg.Description,
+		arg.Owner,
+		arg.Visibility,
+		arg.HistoryLimit,
+		arg.RetentionDays,
+		arg.RetentionMaxCount,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteAnnouncement = `-- name: DeleteAnnouncement :execrows
+DELETE FROM announcements WHERE id = ?
+`
+
+func (q *Queries) DeleteAnnouncement(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAnnouncement, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredIdempotencyKey = `-- name: DeleteExpiredIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE idem_key = ?
+  AND (created_at < ?
+    OR (notification_id IS NULL AND created_at < ?))
+`
+
+type DeleteExpiredIdempotencyKeyParams struct {
+	IdemKey         string    `json:"idem_key"`
+	ExpiredBefore   time.Time `json:"expired_before"`
+	AbandonedBefore time.Time `json:"abandoned_before"`
+}
+
+// Unfinished claims expire after their lease so a crashed create does not
+// block retries for the whole retention window.
+func (q *Queries) DeleteExpiredIdempotencyKey(ctx context.Context, arg DeleteExpiredIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredIdempotencyKey, arg.IdemKey, arg.ExpiredBefore, arg.AbandonedBefore)
+	return err
+}
+
+const deleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE idem_key = ?
+`
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, idemKey string) error {
+	_, err := q.db.ExecContext(ctx, deleteIdempotencyKey, idemKey)
+	return err
+}
+
+const deleteMaintenanceWindow = `-- name: DeleteMaintenanceWindow :execrows
+DELETE FROM maintenance_windows WHERE id = ?
+`
+
+func (q *Queries) DeleteMaintenanceWindow(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMaintenanceWindow, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteNotificationType = `-- name: DeleteNotificationType :execrows
+DELETE FROM notification_types WHERE name = ?
+`
+
+func (q *Queries) DeleteNotificationType(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteNotificationType, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRoom = `-- name: DeleteRoom :execrows
+DELETE FROM rooms WHERE name = ?
+`
+
+func (q *Queries) DeleteRoom(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoom, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRoomState = `-- name: DeleteRoomState :execrows
+DELETE FROM room_state WHERE room = ? AND state_key = ?
+`
+
+type DeleteRoomStateParams struct {
+	Room     string `json:"room"`
+	StateKey string `json:"state_key"`
+}
+
+func (q *Queries) DeleteRoomState(ctx context.Context, arg DeleteRoomStateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoomState, arg.Room, arg.StateKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const exportNotifications = `-- name: ExportNotifications :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
 FROM notifications
-WHERE room = ?
-ORDER BY created_at DESC
+WHERE id > ?
+  AND (? IS NULL OR room = ?)
+  AND (? IS NULL OR created_at >= ?)
+  AND (? IS NULL OR created_at < ?)
+ORDER BY id
 LIMIT ?
 `
 
-type ListNotificationsByRoomParams struct {
-	Room  string `json:"room"`
-	Limit int32  `json:"limit"`
+type ExportNotificationsParams struct {
+	AfterID   int64          `json:"after_id"`
+	Room      sql.NullString `json:"room"`
+	Since     sql.NullTime   `json:"since"`
+	Until     sql.NullTime   `json:"until"`
+	PageLimit int32          `json:"page_limit"`
 }
 
-func (q *Queries) ListNotificationsByRoom(ctx context.Context, arg ListNotificationsByRoomParams) ([]Notification, error) {
-	rows, err := q.db.QueryContext(ctx, listNotificationsByRoom, arg.Room, arg.Limit)
+func (q *Queries) ExportNotifications(ctx context.Context, arg ExportNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, exportNotifications,
+		arg.AfterID,
+		arg.Room,
+		arg.Room,
+		arg.Since,
+		arg.Since,
+		arg.Until,
+		arg.Until,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +488,342 @@ func (q *Queries) ListNotificationsByRoom(ctx context.Context, arg ListNotificat
 			&i.Type,
 			&i.Title,
 			&i.Body,
+			&i.Severity,
+			&i.Data,
+			&i.Link,
+			&i.Actions,
+			&i.Localizations,
+			&i.TemplateKey,
+			&i.TemplateParams,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollapseKey,
+			&i.ReplacesID,
+			&i.SupersededBy,
+			&i.Priority,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT idem_key, notification_id, created_at FROM idempotency_keys WHERE idem_key = ?
+`
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, idemKey string) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, idemKey)
+	var i IdempotencyKey
+	err := row.Scan(&i.IdemKey, &i.NotificationID, &i.CreatedAt)
+	return i, err
+}
+
+const getLatestCollapsedNotificationID = `-- name: GetLatestCollapsedNotificationID :one
+SELECT id FROM notifications
+WHERE room = ? AND collapse_key = ? AND deleted_at IS NULL AND superseded_by IS NULL
+ORDER BY id DESC
+LIMIT 1
+FOR UPDATE
+`
+
+type GetLatestCollapsedNotificationIDParams struct {
+	Room        string `json:"room"`
+	CollapseKey string `json:"collapse_key"`
+}
+
+func (q *Queries) GetLatestCollapsedNotificationID(ctx context.Context, arg GetLatestCollapsedNotificationIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLatestCollapsedNotificationID, arg.Room, arg.CollapseKey)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getNotification = `-- name: GetNotification :one
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) GetNotification(ctx context.Context, id int64) (Notification, error) {
+	row := q.db.QueryRowContext(ctx, getNotification, id)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.Room,
+		&i.Type,
+		&i.Title,
+		&i.Body,
+		&i.Severity,
+		&i.Data,
+		&i.Link,
+		&i.Actions,
+		&i.Localizations,
+		&i.TemplateKey,
+		&i.TemplateParams,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.CollapseKey,
+		&i.ReplacesID,
+		&i.SupersededBy,
+		&i.Priority,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const getNotificationCountCutoffID = `-- name: GetNotificationCountCutoffID :one
+SELECT id FROM notifications
+WHERE room = ? AND type IN (/*SLICE:types*/?)
+ORDER BY id DESC
+LIMIT 1 OFFSET ?
+`
+
+type GetNotificationCountCutoffIDParams struct {
+	Room  string   `json:"room"`
+	Types []string `json:"types"`
+	Keep  int32    `json:"keep"`
+}
+
+func (q *Queries) GetNotificationCountCutoffID(ctx context.Context, arg GetNotificationCountCutoffIDParams) (int64, error) {
+	query := getNotificationCountCutoffID
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Room)
+	if len(arg.Types) > 0 {
+		for _, v := range arg.Types {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:types*/?", strings.Repeat(",?", len(arg.Types))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:types*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Keep)
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getNotificationPin = `-- name: GetNotificationPin :one
+SELECT notification_id, room, pinned_by, pinned_at
+FROM notification_pins
+WHERE notification_id = ?
+`
+
+func (q *Queries) GetNotificationPin(ctx context.Context, notificationID int64) (NotificationPin, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationPin, notificationID)
+	var i NotificationPin
+	err := row.Scan(
+		&i.NotificationID,
+		&i.Room,
+		&i.PinnedBy,
+		&i.PinnedAt,
+	)
+	return i, err
+}
+
+const getRoom = `-- name: GetRoom :one
+SELECT name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at
+FROM rooms
+WHERE name = ?
+`
+
+func (q *Queries) GetRoom(ctx context.Context, name string) (Room, error) {
+	row := q.db.QueryRowContext(ctx, getRoom, name)
+	var i Room
+	err := row.Scan(
+		&i.Name,
+		&i.DisplayName,
+		&i.Description,
+		&i.Owner,
+		&i.Visibility,
+		&i.HistoryLimit,
+		&i.RetentionDays,
+		&i.RetentionMaxCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserPreferences = `-- name: GetUserPreferences :one
+SELECT user_id, muted_rooms, opt_out_types, quiet_hours, updated_at
+FROM user_preferences
+WHERE user_id = ?
+`
+
+func (q *Queries) GetUserPreferences(ctx context.Context, userID string) (UserPreference, error) {
+	row := q.db.QueryRowContext(ctx, getUserPreferences, userID)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.MutedRooms,
+		&i.OptOutTypes,
+		&i.QuietHours,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const importNotification = `-- name: ImportNotification :execlastid
+INSERT INTO notifications (id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type ImportNotificationParams struct {
+	ID             sql.NullInt64   `json:"id"`
+	Room           string          `json:"room"`
+	Type           string          `json:"type"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	Severity       string          `json:"severity"`
+	Data           json.RawMessage `json:"data"`
+	Link           string          `json:"link"`
+	Actions        json.RawMessage `json:"actions"`
+	Localizations  json.RawMessage `json:"localizations"`
+	TemplateKey    string          `json:"template_key"`
+	TemplateParams json.RawMessage `json:"template_params"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ExpiresAt      sql.NullTime    `json:"expires_at"`
+	UpdatedAt      sql.NullTime    `json:"updated_at"`
+	DeletedAt      sql.NullTime    `json:"deleted_at"`
+	CollapseKey    string          `json:"collapse_key"`
+	ReplacesID     sql.NullInt64   `json:"replaces_id"`
+	SupersededBy   sql.NullInt64   `json:"superseded_by"`
+	Priority       int8            `json:"priority"`
+	Seq            int64           `json:"seq"`
+}
+
+func (q *Queries) ImportNotification(ctx context.Context, arg ImportNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importNotification,
+		arg.ID,
+		arg.Room,
+		arg.Type,
+		arg.Title,
+		arg.Body,
+		arg.Severity,
+		arg.Data,
+		arg.Link,
+		arg.Actions,
+		arg.Localizations,
+		arg.TemplateKey,
+		arg.TemplateParams,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+		arg.DeletedAt,
+		arg.CollapseKey,
+		arg.ReplacesID,
+		arg.SupersededBy,
+		arg.Priority,
+		arg.Seq,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const insertIdempotencyKey = `-- name: InsertIdempotencyKey :execrows
+INSERT IGNORE INTO idempotency_keys (idem_key, created_at) VALUES (?, ?)
+`
+
+type InsertIdempotencyKeyParams struct {
+	IdemKey   string    `json:"idem_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) InsertIdempotencyKey(ctx context.Context, arg InsertIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertIdempotencyKey, arg.IdemKey, arg.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listActiveAnnouncements = `-- name: ListActiveAnnouncements :many
+SELECT id, title, body, severity, link, created_by, created_at, expires_at
+FROM announcements
+WHERE expires_at > ?
+ORDER BY id
+`
+
+func (q *Queries) ListActiveAnnouncements(ctx context.Context, expiresAt time.Time) ([]Announcement, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAnnouncements, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Announcement
+	for rows.Next() {
+		var i Announcement
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Body,
+			&i.Severity,
+			&i.Link,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueEscalations = `-- name: ListDueEscalations :many
+SELECT notification_id, policy, step, attempts, status, due_at, acked_by, acked_at, created_at, updated_at
+FROM notification_escalations
+WHERE status = 'pending' AND due_at <= ?
+ORDER BY due_at, notification_id
+LIMIT ?
+`
+
+type ListDueEscalationsParams struct {
+	DueAt time.Time `json:"due_at"`
+	Limit int32     `json:"limit"`
+}
+
+func (q *Queries) ListDueEscalations(ctx context.Context, arg ListDueEscalationsParams) ([]NotificationEscalation, error) {
+	rows, err := q.db.QueryContext(ctx, listDueEscalations, arg.DueAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationEscalation
+	for rows.Next() {
+		var i NotificationEscalation
+		if err := rows.Scan(
+			&i.NotificationID,
+			&i.Policy,
+			&i.Step,
+			&i.Attempts,
+			&i.Status,
+			&i.DueAt,
+			&i.AckedBy,
+			&i.AckedAt,
 			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -72,3 +837,1016 @@ func (q *Queries) ListNotificationsByRoom(ctx context.Context, arg ListNotificat
 	}
 	return items, nil
 }
+
+const listMaintenanceWindows = `-- name: ListMaintenanceWindows :many
+SELECT id, room, type, mode, reason, starts_at, ends_at, created_at
+FROM maintenance_windows
+WHERE ends_at > ?
+ORDER BY starts_at, id
+`
+
+func (q *Queries) ListMaintenanceWindows(ctx context.Context, endsAt time.Time) ([]MaintenanceWindow, error) {
+	rows, err := q.db.QueryContext(ctx, listMaintenanceWindows, endsAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MaintenanceWindow
+	for rows.Next() {
+		var i MaintenanceWindow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Room,
+			&i.Type,
+			&i.Mode,
+			&i.Reason,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationDeliveries = `-- name: ListNotificationDeliveries :many
+SELECT notification_id, user_id, enqueued, dropped, updated_at
+FROM notification_deliveries
+WHERE notification_id = ?
+ORDER BY user_id
+`
+
+func (q *Queries) ListNotificationDeliveries(ctx context.Context, notificationID int64) ([]NotificationDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationDeliveries, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationDelivery
+	for rows.Next() {
+		var i NotificationDelivery
+		if err := rows.Scan(
+			&i.NotificationID,
+			&i.UserID,
+			&i.Enqueued,
+			&i.Dropped,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRoomTypes = `-- name: ListNotificationRoomTypes :many
+SELECT DISTINCT room, type FROM notifications
+`
+
+type ListNotificationRoomTypesRow struct {
+	Room string `json:"room"`
+	Type string `json:"type"`
+}
+
+// Reads idx_notifications_room_type instead of scanning the table.
+func (q *Queries) ListNotificationRoomTypes(ctx context.Context) ([]ListNotificationRoomTypesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationRoomTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationRoomTypesRow
+	for rows.Next() {
+		var i ListNotificationRoomTypesRow
+		if err := rows.Scan(&i.Room, &i.Type); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationTypes = `-- name: ListNotificationTypes :many
+SELECT name, default_severity, default_ttl_seconds, allowed_rooms, updated_at
+FROM notification_types
+ORDER BY name
+`
+
+func (q *Queries) ListNotificationTypes(ctx context.Context) ([]NotificationType, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationType
+	for rows.Next() {
+		var i NotificationType
+		if err := rows.Scan(
+			&i.Name,
+			&i.DefaultSeverity,
+			&i.DefaultTtlSeconds,
+			&i.AllowedRooms,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPinnedNotifications = `-- name: ListPinnedNotifications :many
+SELECT notifications.id, notifications.room, notifications.type, notifications.title, notifications.body, notifications.severity, notifications.data, notifications.link, notifications.actions, notifications.localizations, notifications.template_key, notifications.template_params, notifications.created_at, notifications.expires_at, notifications.updated_at, notifications.deleted_at, notifications.collapse_key, notifications.replaces_id, notifications.superseded_by, notifications.priority, notification_pins.pinned_at
+FROM notification_pins
+JOIN notifications ON notifications.id = notification_pins.notification_id
+WHERE notification_pins.room = ?
+  AND notifications.deleted_at IS NULL AND notifications.superseded_by IS NULL
+  AND (notifications.expires_at IS NULL OR notifications.expires_at > UTC_TIMESTAMP())
+ORDER BY notification_pins.pinned_at DESC, notification_pins.notification_id DESC
+`
+
+type ListPinnedNotificationsRow struct {
+	Notification Notification `json:"notification"`
+	PinnedAt     time.Time    `json:"pinned_at"`
+}
+
+func (q *Queries) ListPinnedNotifications(ctx context.Context, room string) ([]ListPinnedNotificationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPinnedNotifications, room)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPinnedNotificationsRow
+	for rows.Next() {
+		var i ListPinnedNotificationsRow
+		if err := rows.Scan(
+			&i.Notification.ID,
+			&i.Notification.Room,
+			&i.Notification.Type,
+			&i.Notification.Title,
+			&i.Notification.Body,
+			&i.Notification.Severity,
+			&i.Notification.Data,
+			&i.Notification.Link,
+			&i.Notification.Actions,
+			&i.Notification.Localizations,
+			&i.Notification.TemplateKey,
+			&i.Notification.TemplateParams,
+			&i.Notification.CreatedAt,
+			&i.Notification.ExpiresAt,
+			&i.Notification.UpdatedAt,
+			&i.Notification.DeletedAt,
+			&i.Notification.CollapseKey,
+			&i.Notification.ReplacesID,
+			&i.Notification.SupersededBy,
+			&i.Notification.Priority,
+			&i.Notification.Seq,
+			&i.PinnedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeNotificationIDs = `-- name: ListPurgeNotificationIDs :many
+SELECT id FROM notifications
+WHERE room = ? AND type IN (/*SLICE:types*/?)
+  AND ((? IS NOT NULL AND created_at < ?) OR id <= ?)
+ORDER BY id
+LIMIT ?
+FOR UPDATE
+`
+
+type ListPurgeNotificationIDsParams struct {
+	Room       string       `json:"room"`
+	Types      []string     `json:"types"`
+	Before     sql.NullTime `json:"before"`
+	MaxID      int64        `json:"max_id"`
+	PurgeLimit int32        `json:"purge_limit"`
+}
+
+// Locks the batch so its notifications and their pins, deliveries and
+// escalations are purged together.
+func (q *Queries) ListPurgeNotificationIDs(ctx context.Context, arg ListPurgeNotificationIDsParams) ([]int64, error) {
+	query := listPurgeNotificationIDs
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Room)
+	if len(arg.Types) > 0 {
+		for _, v := range arg.Types {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:types*/?", strings.Repeat(",?", len(arg.Types))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:types*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Before)
+	queryParams = append(queryParams, arg.Before)
+	queryParams = append(queryParams, arg.MaxID)
+	queryParams = append(queryParams, arg.PurgeLimit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomNotificationsAsc = `-- name: ListRoomNotificationsAsc :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = ? AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= ?
+  AND id < ? AND id > ? AND seq > ?
+  AND (? IS NULL OR type = ?)
+  AND (? IS NULL OR created_at >= ?)
+  AND (? IS NULL OR created_at < ?)
+ORDER BY id ASC
+LIMIT ?
+`
+
+type ListRoomNotificationsAscParams struct {
+	Room        string         `json:"room"`
+	MinPriority int8           `json:"min_priority"`
+	BeforeID    int64          `json:"before_id"`
+	AfterID     int64          `json:"after_id"`
+	AfterSeq    int64          `json:"after_seq"`
+	Type        sql.NullString `json:"type"`
+	Since       sql.NullTime   `json:"since"`
+	Until       sql.NullTime   `json:"until"`
+	PageLimit   int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListRoomNotificationsAsc(ctx context.Context, arg ListRoomNotificationsAscParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomNotificationsAsc,
+		arg.Room,
+		arg.MinPriority,
+		arg.BeforeID,
+		arg.AfterID,
+		arg.AfterSeq,
+		arg.Type,
+		arg.Type,
+		arg.Since,
+		arg.Since,
+		arg.Until,
+		arg.Until,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Room,
+			&i.Type,
+			&i.Title,
+			&i.Body,
+			&i.Severity,
+			&i.Data,
+			&i.Link,
+			&i.Actions,
+			&i.Localizations,
+			&i.TemplateKey,
+			&i.TemplateParams,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollapseKey,
+			&i.ReplacesID,
+			&i.SupersededBy,
+			&i.Priority,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomNotificationsBySeq = `-- name: ListRoomNotificationsBySeq :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = ? AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= ?
+  AND id < ? AND id > ? AND seq > ?
+  AND (? IS NULL OR type = ?)
+  AND (? IS NULL OR created_at >= ?)
+  AND (? IS NULL OR created_at < ?)
+ORDER BY seq ASC, id ASC
+LIMIT ?
+`
+
+type ListRoomNotificationsBySeqParams struct {
+	Room        string         `json:"room"`
+	MinPriority int8           `json:"min_priority"`
+	BeforeID    int64          `json:"before_id"`
+	AfterID     int64          `json:"after_id"`
+	AfterSeq    int64          `json:"after_seq"`
+	Type        sql.NullString `json:"type"`
+	Since       sql.NullTime   `json:"since"`
+	Until       sql.NullTime   `json:"until"`
+	PageLimit   int32          `json:"page_limit"`
+}
+
+// Resync pages walk the room sequence, which imported notifications with
+// preserved ids do not follow in id order.
+func (q *Queries) ListRoomNotificationsBySeq(ctx context.Context, arg ListRoomNotificationsBySeqParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomNotificationsBySeq,
+		arg.Room,
+		arg.MinPriority,
+		arg.BeforeID,
+		arg.AfterID,
+		arg.AfterSeq,
+		arg.Type,
+		arg.Type,
+		arg.Since,
+		arg.Since,
+		arg.Until,
+		arg.Until,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Room,
+			&i.Type,
+			&i.Title,
+			&i.Body,
+			&i.Severity,
+			&i.Data,
+			&i.Link,
+			&i.Actions,
+			&i.Localizations,
+			&i.TemplateKey,
+			&i.TemplateParams,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollapseKey,
+			&i.ReplacesID,
+			&i.SupersededBy,
+			&i.Priority,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomNotificationsDesc = `-- name: ListRoomNotificationsDesc :many
+SELECT id, room, type, title, body, severity, data, link, actions, localizations, template_key, template_params, created_at, expires_at, updated_at, deleted_at, collapse_key, replaces_id, superseded_by, priority, seq
+FROM notifications
+WHERE room = ? AND deleted_at IS NULL AND superseded_by IS NULL AND (expires_at IS NULL OR expires_at > UTC_TIMESTAMP())
+  AND priority >= ?
+  AND id < ? AND id > ? AND seq > ?
+  AND (? IS NULL OR type = ?)
+  AND (? IS NULL OR created_at >= ?)
+  AND (? IS NULL OR created_at < ?)
+ORDER BY id DESC
+LIMIT ?
+`
+
+type ListRoomNotificationsDescParams struct {
+	Room        string         `json:"room"`
+	MinPriority int8           `json:"min_priority"`
+	BeforeID    int64          `json:"before_id"`
+	AfterID     int64          `json:"after_id"`
+	AfterSeq    int64          `json:"after_seq"`
+	Type        sql.NullString `json:"type"`
+	Since       sql.NullTime   `json:"since"`
+	Until       sql.NullTime   `json:"until"`
+	PageLimit   int32          `json:"page_limit"`
+}
+
+func (q *Queries) ListRoomNotificationsDesc(ctx context.Context, arg ListRoomNotificationsDescParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomNotificationsDesc,
+		arg.Room,
+		arg.MinPriority,
+		arg.BeforeID,
+		arg.AfterID,
+		arg.AfterSeq,
+		arg.Type,
+		arg.Type,
+		arg.Since,
+		arg.Since,
+		arg.Until,
+		arg.Until,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.Room,
+			&i.Type,
+			&i.Title,
+			&i.Body,
+			&i.Severity,
+			&i.Data,
+			&i.Link,
+			&i.Actions,
+			&i.Localizations,
+			&i.TemplateKey,
+			&i.TemplateParams,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollapseKey,
+			&i.ReplacesID,
+			&i.SupersededBy,
+			&i.Priority,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomState = `-- name: ListRoomState :many
+SELECT room, state_key, value, updated_at
+FROM room_state
+WHERE room = ?
+ORDER BY state_key
+`
+
+func (q *Queries) ListRoomState(ctx context.Context, room string) ([]RoomState, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomState, room)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomState
+	for rows.Next() {
+		var i RoomState
+		if err := rows.Scan(
+			&i.Room,
+			&i.StateKey,
+			&i.Value,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRooms = `-- name: ListRooms :many
+SELECT name, display_name, description, owner, visibility, history_limit, retention_days, retention_max_count, created_at, updated_at
+FROM rooms
+ORDER BY name
+`
+
+func (q *Queries) ListRooms(ctx context.Context) ([]Room, error) {
+	rows, err := q.db.QueryContext(ctx, listRooms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Room
+	for rows.Next() {
+		var i Room
+		if err := rows.Scan(
+			&i.Name,
+			&i.DisplayName,
+			&i.Description,
+			&i.Owner,
+			&i.Visibility,
+			&i.HistoryLimit,
+			&i.RetentionDays,
+			&i.RetentionMaxCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockRoomSequence = `-- name: LockRoomSequence :one
+SELECT seq FROM room_sequences WHERE room = ? FOR UPDATE
+`
+
+// Locks the sequence row of the room until the transaction ends, so pins in
+// one room are checked against the limit one at a time.
+func (q *Queries) LockRoomSequence(ctx context.Context, room string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, lockRoomSequence, room)
+	var seq int64
+	err := row.Scan(&seq)
+	return seq, err
+}
+
+const nextRoomSequence = `-- name: NextRoomSequence :execlastid
+INSERT INTO room_sequences (room, seq) VALUES (?, LAST_INSERT_ID(1))
+ON DUPLICATE KEY UPDATE seq = LAST_INSERT_ID(seq + 1)
+`
+
+// LAST_INSERT_ID(expr) hands the new value back as the insert id. The row
+// stays locked until the transaction ends, so sequences in a room are
+// assigned in commit order.
+func (q *Queries) NextRoomSequence(ctx context.Context, room string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, nextRoomSequence, room)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const pinNotification = `-- name: PinNotification :execrows
+INSERT IGNORE INTO notification_pins (notification_id, room, pinned_by, pinned_at)
+VALUES (?, ?, ?, ?)
+`
+
+type PinNotificationParams struct {
+	NotificationID int64     `json:"notification_id"`
+	Room           string    `json:"room"`
+	PinnedBy       string    `json:"pinned_by"`
+	PinnedAt       time.Time `json:"pinned_at"`
+}
+
+func (q *Queries) PinNotification(ctx context.Context, arg PinNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pinNotification,
+		arg.NotificationID,
+		arg.Room,
+		arg.PinnedBy,
+		arg.PinnedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeIdempotencyKeys = `-- name: PurgeIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE created_at < ? LIMIT ?
+`
+
+type PurgeIdempotencyKeysParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) PurgeIdempotencyKeys(ctx context.Context, arg PurgeIdempotencyKeysParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeIdempotencyKeys, arg.CreatedAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeNotificationDeliveries = `-- name: PurgeNotificationDeliveries :exec
+DELETE FROM notification_deliveries WHERE notification_id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) PurgeNotificationDeliveries(ctx context.Context, ids []int64) error {
+	query := purgeNotificationDeliveries
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
+
+const purgeNotificationEscalations = `-- name: PurgeNotificationEscalations :exec
+DELETE FROM notification_escalations WHERE notification_id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) PurgeNotificationEscalations(ctx context.Context, ids []int64) error {
+	query := purgeNotificationEscalations
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
+
+const purgeNotificationPins = `-- name: PurgeNotificationPins :exec
+DELETE FROM notification_pins WHERE notification_id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) PurgeNotificationPins(ctx context.Context, ids []int64) error {
+	query := purgeNotificationPins
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
+
+const purgeNotifications = `-- name: PurgeNotifications :execrows
+DELETE FROM notifications WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) PurgeNotifications(ctx context.Context, ids []int64) (int64, error) {
+	query := purgeNotifications
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchNotifications = `-- name: SearchNotifications :many
+SELECT notifications.id, notifications.room, notifications.type, notifications.title, notifications.body, notifications.severity, notifications.data, notifications.link, notifications.actions, notifications.localizations, notifications.template_key, notifications.template_params, notifications.created_at, notifications.expires_at, notifications.updated_at, notifications.deleted_at, notifications.collapse_key, notifications.replaces_id, notifications.superseded_by, notifications.priority, CAST(MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE) AS DOUBLE) AS score
+FROM notifications
+WHERE MATCH(title, body) AGAINST (? IN NATURAL LANGUAGE MODE)
+  AND deleted_at IS NULL AND superseded_by IS NULL
+  AND (? IS NULL OR room = ?)
+  AND (? IS NULL OR type = ?)
+ORDER BY score DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type SearchNotificationsParams struct {
+	Query      string         `json:"query"`
+	Room       sql.NullString `json:"room"`
+	Type       sql.NullString `json:"type"`
+	PageLimit  int32          `json:"page_limit"`
+	PageOffset int32          `json:"page_offset"`
+}
+
+type SearchNotificationsRow struct {
+	Notification Notification `json:"notification"`
+	Score        float64      `json:"score"`
+}
+
+func (q *Queries) SearchNotifications(ctx context.Context, arg SearchNotificationsParams) ([]SearchNotificationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchNotifications,
+		arg.Query,
+		arg.Query,
+		arg.Room,
+		arg.Room,
+		arg.Type,
+		arg.Type,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchNotificationsRow
+	for rows.Next() {
+		var i SearchNotificationsRow
+		if err := rows.Scan(
+			&i.Notification.ID,
+			&i.Notification.Room,
+			&i.Notification.Type,
+			&i.Notification.Title,
+			&i.Notification.Body,
+			&i.Notification.Severity,
+			&i.Notification.Data,
+			&i.Notification.Link,
+			&i.Notification.Actions,
+			&i.Notification.Localizations,
+			&i.Notification.TemplateKey,
+			&i.Notification.TemplateParams,
+			&i.Notification.CreatedAt,
+			&i.Notification.ExpiresAt,
+			&i.Notification.UpdatedAt,
+			&i.Notification.DeletedAt,
+			&i.Notification.CollapseKey,
+			&i.Notification.ReplacesID,
+			&i.Notification.SupersededBy,
+			&i.Notification.Priority,
+			&i.Notification.Seq,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteNotification = `-- name: SoftDeleteNotification :execrows
+UPDATE notifications SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL
+`
+
+type SoftDeleteNotificationParams struct {
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID        int64        `json:"id"`
+}
+
+func (q *Queries) SoftDeleteNotification(ctx context.Context, arg SoftDeleteNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteNotification, arg.DeletedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const supersedeNotification = `-- name: SupersedeNotification :exec
+UPDATE notifications SET superseded_by = ? WHERE id = ?
+`
+
+type SupersedeNotificationParams struct {
+	SupersededBy sql.NullInt64 `json:"superseded_by"`
+	ID           int64         `json:"id"`
+}
+
+func (q *Queries) SupersedeNotification(ctx context.Context, arg SupersedeNotificationParams) error {
+	_, err := q.db.ExecContext(ctx, supersedeNotification, arg.SupersededBy, arg.ID)
+	return err
+}
+
+const unpinNotification = `-- name: UnpinNotification :execrows
+DELETE FROM notification_pins WHERE notification_id = ?
+`
+
+func (q *Queries) UnpinNotification(ctx context.Context, notificationID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unpinNotification, notificationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateNotification = `-- name: UpdateNotification :execrows
+UPDATE notifications
+SET title = ?, body = ?, severity = ?, priority = ?, data = ?, link = ?, actions = ?, localizations = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateNotificationParams struct {
+	Title         string          `json:"title"`
+	Body          string          `json:"body"`
+	Severity      string          `json:"severity"`
+	Priority      int8            `json:"priority"`
+	Data          json.RawMessage `json:"data"`
+	Link          string          `json:"link"`
+	Actions       json.RawMessage `json:"actions"`
+	Localizations json.RawMessage `json:"localizations"`
+	UpdatedAt     sql.NullTime    `json:"updated_at"`
+	ID            int64           `json:"id"`
+}
+
+func (q *Queries) UpdateNotification(ctx context.Context, arg UpdateNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateNotification,
+		arg.Title,
+		arg.Body,
+		arg.Severity,
+		arg.Priority,
+		arg.Data,
+		arg.Link,
+		arg.Actions,
+		arg.Localizations,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateRoom = `-- name: UpdateRoom :execrows
+UPDATE rooms
+SET display_name = ?, description = ?, owner = ?, visibility = ?, history_limit = ?, retention_days = ?, retention_max_count = ?, updated_at = ?
+WHERE name = ?
+`
+
+type UpdateRoomParams struct {
+	DisplayName       string    `json:"display_name"`
+	Description       string    `json:"description"`
+	Owner             string    `json:"owner"`
+	Visibility        string    `json:"visibility"`
+	HistoryLimit      int32     `json:"history_limit"`
+	RetentionDays     int32     `json:"retention_days"`
+	RetentionMaxCount int32     `json:"retention_max_count"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Name              string    `json:"name"`
+}
+
+func (q *Queries) UpdateRoom(ctx context.Context, arg UpdateRoomParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateRoom,
+		arg.DisplayName,
+		arg.Description,
+		arg.Owner,
+		arg.Visibility,
+		arg.HistoryLimit,
+		arg.RetentionDays,
+		arg.RetentionMaxCount,
+		arg.UpdatedAt,
+		arg.Name,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertNotificationType = `-- name: UpsertNotificationType :exec
+INSERT INTO notification_types (name, default_severity, default_ttl_seconds, allowed_rooms)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  default_severity = VALUES(default_severity),
+  default_ttl_seconds = VALUES(default_ttl_seconds),
+  allowed_rooms = VALUES(allowed_rooms)
+`
+
+type UpsertNotificationTypeParams struct {
+	Name              string          `json:"name"`
+	DefaultSeverity   string          `json:"default_severity"`
+	DefaultTtlSeconds int32           `json:"default_ttl_seconds"`
+	AllowedRooms      json.RawMessage `json:"allowed_rooms"`
+}
+
+func (q *Queries) UpsertNotificationType(ctx context.Context, arg UpsertNotificationTypeParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNotificationType,
+		arg.Name,
+		arg.DefaultSeverity,
+		arg.DefaultTtlSeconds,
+		arg.AllowedRooms,
+	)
+	return err
+}
+
+const upsertRoomState = `-- name: UpsertRoomState :exec
+INSERT INTO room_state (room, state_key, value, updated_at)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  value = VALUES(value),
+  updated_at = VALUES(updated_at)
+`
+
+type UpsertRoomStateParams struct {
+	Room      string          `json:"room"`
+	StateKey  string          `json:"state_key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (q *Queries) UpsertRoomState(ctx context.Context, arg UpsertRoomStateParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRoomState,
+		arg.Room,
+		arg.StateKey,
+		arg.Value,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertUserPreferences = `-- name: UpsertUserPreferences :exec
+INSERT INTO user_preferences (user_id, muted_rooms, opt_out_types, quiet_hours, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+  muted_rooms = VALUES(muted_rooms),
+  opt_out_types = VALUES(opt_out_types),
+  quiet_hours = VALUES(quiet_hours),
+  updated_at = VALUES(updated_at)
+`
+
+type UpsertUserPreferencesParams struct {
+	UserID      string          `json:"user_id"`
+	MutedRooms  json.RawMessage `json:"muted_rooms"`
+	OptOutTypes json.RawMessage `json:"opt_out_types"`
+	QuietHours  json.RawMessage `json:"quiet_hours"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserPreferences,
+		arg.UserID,
+		arg.MutedRooms,
+		arg.OptOutTypes,
+		arg.QuietHours,
+		arg.UpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"sse_demo/internal/model"
+)
+
+const (
+	MaxAnnouncementTitleChars = 255
+	MaxAnnouncementBodyChars  = 4096
+	// DefaultAnnouncementTTL applies when no expiry is given.
+	DefaultAnnouncementTTL = time.Hour
+	MaxAnnouncementTTL     = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidAnnouncement  = errors.New("invalid announcement")
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+)
+
+// ValidateAnnouncement checks a new announcement. It must expire after now
+// and within MaxAnnouncementTTL.
+func ValidateAnnouncement(announcement model.Announcement, now time.Time) error {
+	switch {
+	case announcement.Title == "" || utf8.RuneCountInString(announcement.Title) > MaxAnnouncementTitleChars:
+		return fmt.Errorf("%w: title must be 1-%d characters", ErrInvalidAnnouncement, MaxAnnouncementTitleChars)
+	case announcement.Body == "" || utf8.RuneCountInString(announcement.Body) > MaxAnnouncementBodyChars:
+		return fmt.Errorf("%w: body must be 1-%d characters", ErrInvalidAnnouncement, MaxAnnouncementBodyChars)
+	case announcement.Severity != "" && !IsValidSeverity(announcement.Severity):
+		return fmt.Errorf("%w: severity must be one of: low, normal, high, critical", ErrInvalidAnnouncement)
+	case !announcement.ExpiresAt.After(now) || announcement.ExpiresAt.Sub(now) > MaxAnnouncementTTL:
+		return fmt.Errorf("%w: expires_at must be in the future and within %s", ErrInvalidAnnouncement, MaxAnnouncementTTL)
+	}
+	if err := ValidateLink(announcement.Link); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAnnouncement, err)
+	}
+	return nil
+}
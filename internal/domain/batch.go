@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidBatchMode      = errors.New("invalid batch mode")
+	ErrBatchRejected         = errors.New("batch rejected")
+	ErrBatchDedupUnsupported = errors.New("dedup_id is not supported in batch create")
+)
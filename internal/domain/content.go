@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"sse_demo/internal/model"
+)
+
+const (
+	MaxDataBytes        = 8 * 1024
+	MaxLinkLength       = 2048
+	MaxActions          = 5
+	MaxActionLabelChars = 64
+	MaxCollapseKeyBytes = 255
+)
+
+var (
+	ErrInvalidData        = errors.New("invalid data")
+	ErrInvalidLink        = errors.New("invalid link")
+	ErrInvalidActions     = errors.New("invalid actions")
+	ErrInvalidCollapseKey = errors.New("invalid collapse key")
+)
+
+// Action ids end up as the last segment of the action.invoked.<type>.<id>
+// routing key, so they must not contain dots.
+var actionIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidateData checks that the optional structured payload is a JSON object
+// within MaxDataBytes.
+func ValidateData(data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if len(data) > MaxDataBytes {
+		return fmt.Errorf("%w: must not exceed %d bytes", ErrInvalidData, MaxDataBytes)
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	var object map[string]json.RawMessage
+	if len(trimmed) == 0 || trimmed[0] != '{' || json.Unmarshal(trimmed, &object) != nil {
+		return fmt.Errorf("%w: must be a JSON object", ErrInvalidData)
+	}
+	return nil
+}
+
+// ValidateLink accepts absolute http(s) URLs up to MaxLinkLength.
+func ValidateLink(link string) error {
+	if link == "" {
+		return nil
+	}
+	if len(link) > MaxLinkLength {
+		return fmt.Errorf("%w: must not exceed %d characters", ErrInvalidLink, MaxLinkLength)
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: must be an absolute http or https URL", ErrInvalidLink)
+	}
+	return nil
+}
+
+// ValidateActions checks action count, ids and labels. Action ids must be
+// unique within a notification.
+func ValidateActions(actions []model.Action) error {
+	if len(actions) > MaxActions {
+		return fmt.Errorf("%w: at most %d actions allowed", ErrInvalidActions, MaxActions)
+	}
+	seen := make(map[string]struct{}, len(actions))
+	for _, action := range actions {
+		if !actionIDPattern.MatchString(action.ID) {
+			return fmt.Errorf("%w: id %q must match %s", ErrInvalidActions, action.ID, actionIDPattern.String())
+		}
+		if _, ok := seen[action.ID]; ok {
+			return fmt.Errorf("%w: duplicate id %q", ErrInvalidActions, action.ID)
+		}
+		seen[action.ID] = struct{}{}
+		if action.Label == "" || utf8.RuneCountInString(action.Label) > MaxActionLabelChars {
+			return fmt.Errorf("%w: label for %q must be 1-%d characters", ErrInvalidActions, action.ID, MaxActionLabelChars)
+		}
+	}
+	return nil
+}
+
+// ValidateCollapseKey accepts up to MaxCollapseKeyBytes of printable UTF-8.
+func ValidateCollapseKey(key string) error {
+	if len(key) > MaxCollapseKeyBytes || !utf8.ValidString(key) {
+		return fmt.Errorf("%w: must be valid UTF-8 of at most %d bytes", ErrInvalidCollapseKey, MaxCollapseKeyBytes)
+	}
+	for _, r := range key {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: must not contain control characters", ErrInvalidCollapseKey)
+		}
+	}
+	return nil
+}
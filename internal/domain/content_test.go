@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func TestValidateData(t *testing.T) {
+	require.NoError(t, ValidateData(nil))
+	require.NoError(t, ValidateData(json.RawMessage(`null`)))
+	require.NoError(t, ValidateData(json.RawMessage(`{"invoice_id":1234}`)))
+	require.ErrorIs(t, ValidateData(json.RawMessage(`[1,2]`)), ErrInvalidData)
+	require.ErrorIs(t, ValidateData(json.RawMessage(`"text"`)), ErrInvalidData)
+	require.ErrorIs(t, ValidateData(json.RawMessage(`{"a":"`+strings.Repeat("x", MaxDataBytes)+`"}`)), ErrInvalidData)
+}
+
+func TestValidateLink(t *testing.T) {
+	require.NoError(t, ValidateLink(""))
+	require.NoError(t, ValidateLink("https://example.com/invoices/1234"))
+	require.ErrorIs(t, ValidateLink("javascript:alert(1)"), ErrInvalidLink)
+	require.ErrorIs(t, ValidateLink("/relative"), ErrInvalidLink)
+	require.ErrorIs(t, ValidateLink("https://example.com/"+strings.Repeat("a", MaxLinkLength)), ErrInvalidLink)
+}
+
+func TestValidateActions(t *testing.T) {
+	require.NoError(t, ValidateActions([]model.Action{{ID: "approve", Label: "Approve"}, {ID: "reject", Label: "Reject"}}))
+	require.ErrorIs(t, ValidateActions([]model.Action{{ID: "approve", Label: "A"}, {ID: "approve", Label: "B"}}), ErrInvalidActions)
+	require.ErrorIs(t, ValidateActions([]model.Action{{ID: "Bad ID", Label: "A"}}), ErrInvalidActions)
+	require.ErrorIs(t, ValidateActions([]model.Action{{ID: "deploy.prod", Label: "A"}}), ErrInvalidActions)
+	require.ErrorIs(t, ValidateActions([]model.Action{{ID: "approve"}}), ErrInvalidActions)
+
+	tooMany := make([]model.Action, MaxActions+1)
+	for i := range tooMany {
+		tooMany[i] = model.Action{ID: string(rune('a' + i)), Label: "label"}
+	}
+	require.ErrorIs(t, ValidateActions(tooMany), ErrInvalidActions)
+}
+
+func TestValidateCollapseKey(t *testing.T) {
+	require.NoError(t, ValidateCollapseKey(""))
+	require.NoError(t, ValidateCollapseKey("build:1234"))
+	require.ErrorIs(t, ValidateCollapseKey("build\n1234"), ErrInvalidCollapseKey)
+	require.ErrorIs(t, ValidateCollapseKey(strings.Repeat("k", MaxCollapseKeyBytes+1)), ErrInvalidCollapseKey)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	"sse_demo/internal/model"
+)
+
+// Escalation step actions: re-send the notification as a system notification
+// in its room, copy it to another room, or POST it to a webhook.
+const (
+	EscalationActionSystem  = "system"
+	EscalationActionCopy    = "copy"
+	EscalationActionWebhook = "webhook"
+)
+
+// Escalation statuses. Only pending escalations run further steps; failed
+// ones gave up on a step that kept failing.
+const (
+	EscalationPending   = "pending"
+	EscalationAcked     = "acked"
+	EscalationCompleted = "completed"
+	EscalationFailed    = "failed"
+)
+
+const (
+	MaxEscalationSteps        = 10
+	MaxEscalationPolicyLength = 64
+)
+
+var ErrInvalidEscalationPolicy = errors.New("invalid escalation policy")
+
+// ValidateEscalationPolicy checks a configured escalation policy.
+func ValidateEscalationPolicy(policy model.EscalationPolicy) error {
+	switch {
+	case policy.Name == "" || len(policy.Name) > MaxEscalationPolicyLength:
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidEscalationPolicy, MaxEscalationPolicyLength)
+	case policy.Room == "" || len(policy.Room) > MaxRoomPatternLength:
+		return fmt.Errorf("%w: room must be 1-%d characters", ErrInvalidEscalationPolicy, MaxRoomPatternLength)
+	case !IsValidTypeName(policy.Type):
+		return fmt.Errorf("%w: type is not a valid type name", ErrInvalidEscalationPolicy)
+	case len(policy.Steps) == 0 || len(policy.Steps) > MaxEscalationSteps:
+		return fmt.Errorf("%w: 1-%d steps required", ErrInvalidEscalationPolicy, MaxEscalationSteps)
+	}
+	if _, err := path.Match(policy.Room, ""); err != nil {
+		return fmt.Errorf("%w: room is not a valid pattern", ErrInvalidEscalationPolicy)
+	}
+	for i, step := range policy.Steps {
+		if step.AfterSeconds <= 0 {
+			return fmt.Errorf("%w: step %d: after_seconds must be positive", ErrInvalidEscalationPolicy, i)
+		}
+		switch step.Action {
+		case EscalationActionSystem:
+		case EscalationActionCopy:
+			if !IsValidRoomName(step.Room) {
+				return fmt.Errorf("%w: step %d: copy needs a valid room", ErrInvalidEscalationPolicy, i)
+			}
+		case EscalationActionWebhook:
+			u, err := url.Parse(step.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("%w: step %d: webhook needs an absolute http or https URL", ErrInvalidEscalationPolicy, i)
+			}
+		default:
+			return fmt.Errorf("%w: step %d: action must be one of: system, copy, webhook", ErrInvalidEscalationPolicy, i)
+		}
+	}
+	return nil
+}
+
+// MatchEscalationPolicy returns the first policy covering the notification.
+func MatchEscalationPolicy(policies []model.EscalationPolicy, notification model.Notification) (model.EscalationPolicy, bool) {
+	for _, policy := range policies {
+		if policy.Type != notification.Type {
+			continue
+		}
+		if ok, _ := path.Match(policy.Room, notification.Room); ok {
+			return policy, true
+		}
+	}
+	return model.EscalationPolicy{}, false
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func TestValidateEscalationPolicy(t *testing.T) {
+	valid := model.EscalationPolicy{
+		Name: "ops",
+		Room: "ops-*",
+		Type: NotificationTypeWarning,
+		Steps: []model.EscalationStep{
+			{AfterSeconds: 300, Action: EscalationActionSystem},
+			{AfterSeconds: 600, Action: EscalationActionCopy, Room: "escalations"},
+			{AfterSeconds: 900, Action: EscalationActionWebhook, URL: "https://pager.example.com/hook"},
+		},
+	}
+	require.NoError(t, ValidateEscalationPolicy(valid))
+
+	for _, step := range []model.EscalationStep{
+		{AfterSeconds: 0, Action: EscalationActionSystem},
+		{AfterSeconds: 60, Action: "page"},
+		{AfterSeconds: 60, Action: EscalationActionCopy},
+		{AfterSeconds: 60, Action: EscalationActionWebhook, URL: "ftp://example.com"},
+	} {
+		policy := valid
+		policy.Steps = []model.EscalationStep{step}
+		require.ErrorIs(t, ValidateEscalationPolicy(policy), ErrInvalidEscalationPolicy, "%+v", step)
+	}
+	policy := valid
+	policy.Steps = nil
+	require.ErrorIs(t, ValidateEscalationPolicy(policy), ErrInvalidEscalationPolicy)
+
+	matched, ok := MatchEscalationPolicy([]model.EscalationPolicy{valid}, model.Notification{Room: "ops-eu", Type: NotificationTypeWarning})
+	require.True(t, ok)
+	require.Equal(t, "ops", matched.Name)
+	_, ok = MatchEscalationPolicy([]model.EscalationPolicy{valid}, model.Notification{Room: "ops-eu", Type: NotificationTypeInfo})
+	require.False(t, ok)
+	_, ok = MatchEscalationPolicy([]model.EscalationPolicy{valid}, model.Notification{Room: "dev", Type: NotificationTypeWarning})
+	require.False(t, ok)
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+const MaxIdempotencyKeyLength = 255
+
+var (
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+	// ErrIdempotentNotificationGone is returned for a replay whose original
+	// notification has since been deleted or purged.
+	ErrIdempotentNotificationGone = errors.New("notification created for this idempotency key no longer exists")
+)
+
+// IsValidIdempotencyKey accepts 1-255 printable ASCII characters.
+func IsValidIdempotencyKey(key string) bool {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -8,11 +8,32 @@ const (
 	NotificationTypeSystem  = "system"
 )
 
-var ErrInvalidNotificationType = errors.New("invalid notification type")
+const (
+	SeverityLow      = "low"
+	SeverityNormal   = "normal"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+var (
+	ErrInvalidNotificationType  = errors.New("invalid notification type")
+	ErrTypeNotAllowedInRoom     = errors.New("notification type not allowed in room")
+	ErrNotificationTypeNotFound = errors.New("notification type not found")
+	ErrConfiguredTypeReadOnly   = errors.New("configured notification type cannot be deleted")
+	ErrInvalidSeverity          = errors.New("invalid severity")
+	ErrNotificationNotFound     = errors.New("notification not found")
+	ErrNotificationNotPinned    = errors.New("notification is not pinned")
+	ErrPinLimitReached          = errors.New("room has reached its pin limit")
+	ErrNotificationNotLive      = errors.New("notification is superseded or expired")
+	ErrActionNotFound           = errors.New("action not found")
+	ErrInvalidLocale            = errors.New("invalid locale")
+	ErrUnknownTemplate          = errors.New("unknown notification template")
+	ErrNotificationRejected     = errors.New("notification rejected")
+)
 
-func IsValidNotificationType(value string) bool {
+func IsValidSeverity(value string) bool {
 	switch value {
-	case NotificationTypeInfo, NotificationTypeWarning, NotificationTypeSystem:
+	case SeverityLow, SeverityNormal, SeverityHigh, SeverityCritical:
 		return true
 	default:
 		return false
@@ -6,7 +6,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestIsValidNotificationType(t *testing.T) {
+func TestDefaultNotificationTypes(t *testing.T) {
+	registry := NewTypeRegistry(DefaultNotificationTypes()...)
+
 	t.Run("valid types", func(t *testing.T) {
 		valid := []string{
 			NotificationTypeInfo,
@@ -14,14 +16,16 @@ func TestIsValidNotificationType(t *testing.T) {
 			NotificationTypeSystem,
 		}
 		for _, v := range valid {
-			require.True(t, IsValidNotificationType(v), "expected valid type: %s", v)
+			_, err := registry.Validate(v, "room-1")
+			require.NoError(t, err, "expected valid type: %s", v)
 		}
 	})
 
 	t.Run("invalid types", func(t *testing.T) {
 		invalid := []string{"", "infoo", "systemx", "warning1"}
 		for _, v := range invalid {
-			require.False(t, IsValidNotificationType(v), "expected invalid type: %s", v)
+			_, err := registry.Validate(v, "room-1")
+			require.ErrorIs(t, err, ErrInvalidNotificationType, "expected invalid type: %s", v)
 		}
 	})
 }
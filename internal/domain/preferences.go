@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+const (
+	MaxUserIDLength      = 255
+	MaxMutedRooms        = 100
+	MaxOptOutTypes       = 100
+	MaxRoomPatternLength = 255
+)
+
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+// ValidatePreferences checks a preferences document before it is stored.
+func ValidatePreferences(prefs model.UserPreferences) error {
+	if prefs.UserID == "" || len(prefs.UserID) > MaxUserIDLength {
+		return fmt.Errorf("%w: user id must be 1-%d characters", ErrInvalidPreferences, MaxUserIDLength)
+	}
+	if len(prefs.MutedRooms) > MaxMutedRooms {
+		return fmt.Errorf("%w: at most %d muted rooms allowed", ErrInvalidPreferences, MaxMutedRooms)
+	}
+	for _, mute := range prefs.MutedRooms {
+		if mute.Room == "" || len(mute.Room) > MaxRoomPatternLength {
+			return fmt.Errorf("%w: muted room must be 1-%d characters", ErrInvalidPreferences, MaxRoomPatternLength)
+		}
+		if _, err := path.Match(mute.Room, ""); err != nil {
+			return fmt.Errorf("%w: muted room %q is not a valid pattern", ErrInvalidPreferences, mute.Room)
+		}
+	}
+	if len(prefs.OptOutTypes) > MaxOptOutTypes {
+		return fmt.Errorf("%w: at most %d opt-out types allowed", ErrInvalidPreferences, MaxOptOutTypes)
+	}
+	for _, notificationType := range prefs.OptOutTypes {
+		if !IsValidTypeName(notificationType) {
+			return fmt.Errorf("%w: opt-out type %q is not a valid type name", ErrInvalidPreferences, notificationType)
+		}
+	}
+	if prefs.QuietHours != nil {
+		return ValidateQuietHours(*prefs.QuietHours)
+	}
+	return nil
+}
+
+// PreferencesAllow reports whether a notification should reach the user at
+// now: its type must not be opted out and no active mute may cover its room.
+func PreferencesAllow(prefs model.UserPreferences, notification model.Notification, now time.Time) bool {
+	if slices.Contains(prefs.OptOutTypes, notification.Type) {
+		return false
+	}
+	for _, mute := range prefs.MutedRooms {
+		if mute.Until != nil && !now.Before(*mute.Until) {
+			continue
+		}
+		if ok, _ := path.Match(mute.Room, notification.Room); ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "errors"
+
+// Priorities order delivery: high-priority frames overtake queued lower ones
+// and slow clients lose low-priority frames first.
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+var ErrInvalidPriority = errors.New("invalid priority")
+
+func IsValidPriority(value string) bool {
+	switch value {
+	case PriorityLow, PriorityNormal, PriorityHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// PriorityRank returns the sort order of a priority. Empty and unknown values
+// rank as normal.
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityLow:
+		return 0
+	case PriorityHigh:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// PriorityFromRank is the inverse of PriorityRank.
+func PriorityFromRank(rank int) string {
+	switch {
+	case rank <= 0:
+		return PriorityLow
+	case rank >= 2:
+		return PriorityHigh
+	default:
+		return PriorityNormal
+	}
+}
+
+// DefaultPriority derives a priority from severity for producers that only
+// set the latter.
+func DefaultPriority(severity string) string {
+	switch severity {
+	case SeverityHigh, SeverityCritical:
+		return PriorityHigh
+	case SeverityLow:
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"sse_demo/internal/model"
+)
+
+// Room visibility only controls the room listings: a private room is listed
+// to its owner alone. Its stream, history, search and export are served to
+// anyone who knows the room name, like those of any other room.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+const (
+	MaxRoomDisplayNameLength = 255
+	MaxRoomDescriptionLength = 1024
+	MaxRoomOwnerLength       = 255
+)
+
+var (
+	ErrInvalidRoom  = errors.New("invalid room")
+	ErrRoomNotFound = errors.New("room not found")
+	ErrRoomExists   = errors.New("room already exists")
+)
+
+// Room names are used in URL paths and matched by the room globs of type and
+// retention rules, so they may not contain '/' or glob characters.
+var roomNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:@-]{0,254}$`)
+
+const roomNameRule = "must be 1-255 letters, digits or . _ : @ - and start with a letter or digit"
+
+func IsValidRoomName(value string) bool {
+	return roomNamePattern.MatchString(value)
+}
+
+func IsValidVisibility(value string) bool {
+	return value == VisibilityPublic || value == VisibilityPrivate
+}
+
+// ValidateRoom checks the metadata of a room before it is stored.
+func ValidateRoom(room model.Room) error {
+	switch {
+	case !IsValidRoomName(room.Name):
+		return fmt.Errorf("%w: name "+roomNameRule, ErrInvalidRoom)
+	case utf8.RuneCountInString(room.DisplayName) > MaxRoomDisplayNameLength:
+		return fmt.Errorf("%w: display_name must not exceed %d characters", ErrInvalidRoom, MaxRoomDisplayNameLength)
+	case utf8.RuneCountInString(room.Description) > MaxRoomDescriptionLength:
+		return fmt.Errorf("%w: description must not exceed %d characters", ErrInvalidRoom, MaxRoomDescriptionLength)
+	case utf8.RuneCountInString(room.Owner) > MaxRoomOwnerLength:
+		return fmt.Errorf("%w: owner must not exceed %d characters", ErrInvalidRoom, MaxRoomOwnerLength)
+	case !IsValidVisibility(room.Visibility):
+		return fmt.Errorf("%w: visibility must be one of: public, private", ErrInvalidRoom)
+	case room.HistoryLimit < 0:
+		return fmt.Errorf("%w: history_limit must not be negative", ErrInvalidRoom)
+	case room.RetentionDays < 0:
+		return fmt.Errorf("%w: retention_days must not be negative", ErrInvalidRoom)
+	case room.RetentionMaxCount < 0:
+		return fmt.Errorf("%w: retention_max_count must not be negative", ErrInvalidRoom)
+	}
+	return nil
+}
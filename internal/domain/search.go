@@ -0,0 +1,5 @@
+package domain
+
+import "errors"
+
+var ErrInvalidSearchQuery = errors.New("search query must contain a word")
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	MaxStateKeyLength  = 128
+	MaxStateValueBytes = 4 * 1024
+)
+
+var (
+	ErrInvalidRoomState  = errors.New("invalid room state")
+	ErrRoomStateNotFound = errors.New("room state key not found")
+)
+
+var stateKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,127}$`)
+
+// ValidateRoomState checks a state key and its value. The value may be any
+// JSON except null, which is reserved for deleted keys in state frames.
+func ValidateRoomState(key string, value json.RawMessage) error {
+	if !stateKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: key must be 1-%d letters, digits or . _ : -", ErrInvalidRoomState, MaxStateKeyLength)
+	}
+	if len(value) > MaxStateValueBytes {
+		return fmt.Errorf("%w: value must not exceed %d bytes", ErrInvalidRoomState, MaxStateValueBytes)
+	}
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) || !json.Valid(trimmed) {
+		return fmt.Errorf("%w: value must be JSON other than null", ErrInvalidRoomState)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+// Suppression modes: suppressed notifications are only summarized when the
+// window ends, deferred ones are delivered then.
+const (
+	SuppressionSuppress = "suppress"
+	SuppressionDefer    = "defer"
+)
+
+// Sources of a suppression summary.
+const (
+	SuppressionSourceMaintenance = "maintenance"
+	SuppressionSourceQuietHours  = "quiet_hours"
+)
+
+const (
+	MaxMaintenanceReasonLength = 255
+	MaxMaintenanceWindowLength = 7 * 24 * time.Hour
+	quietHoursLayout           = "15:04"
+)
+
+var (
+	ErrInvalidMaintenanceWindow  = errors.New("invalid maintenance window")
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+)
+
+func IsValidSuppressionMode(value string) bool {
+	return value == SuppressionSuppress || value == SuppressionDefer
+}
+
+// ValidateMaintenanceWindow checks a new maintenance window. Windows may start
+// in the past but must not have ended yet.
+func ValidateMaintenanceWindow(window model.MaintenanceWindow, now time.Time) error {
+	switch {
+	case window.Room == "" || len(window.Room) > MaxRoomPatternLength:
+		return fmt.Errorf("%w: room must be 1-%d characters", ErrInvalidMaintenanceWindow, MaxRoomPatternLength)
+	case window.Type != "" && !IsValidTypeName(window.Type):
+		return fmt.Errorf("%w: type is not a valid type name", ErrInvalidMaintenanceWindow)
+	case !IsValidSuppressionMode(window.Mode):
+		return fmt.Errorf("%w: mode must be one of: suppress, defer", ErrInvalidMaintenanceWindow)
+	case len(window.Reason) > MaxMaintenanceReasonLength:
+		return fmt.Errorf("%w: reason must not exceed %d characters", ErrInvalidMaintenanceWindow, MaxMaintenanceReasonLength)
+	case !window.EndsAt.After(window.StartsAt) || !window.EndsAt.After(now):
+		return fmt.Errorf("%w: ends_at must be in the future and after starts_at", ErrInvalidMaintenanceWindow)
+	case window.EndsAt.Sub(window.StartsAt) > MaxMaintenanceWindowLength:
+		return fmt.Errorf("%w: window must not be longer than %s", ErrInvalidMaintenanceWindow, MaxMaintenanceWindowLength)
+	}
+	if _, err := path.Match(window.Room, ""); err != nil {
+		return fmt.Errorf("%w: room is not a valid pattern", ErrInvalidMaintenanceWindow)
+	}
+	return nil
+}
+
+// MaintenanceWindowCovers reports whether window holds back notification at
+// now.
+func MaintenanceWindowCovers(window model.MaintenanceWindow, notification model.Notification, now time.Time) bool {
+	if now.Before(window.StartsAt) || !now.Before(window.EndsAt) {
+		return false
+	}
+	if window.Type != "" && window.Type != notification.Type {
+		return false
+	}
+	ok, _ := path.Match(window.Room, notification.Room)
+	return ok
+}
+
+// ValidateQuietHours checks clock times, time zone and mode of quiet hours.
+func ValidateQuietHours(quiet model.QuietHours) error {
+	start, startErr := time.Parse(quietHoursLayout, quiet.Start)
+	end, endErr := time.Parse(quietHoursLayout, quiet.End)
+	if startErr != nil || endErr != nil || start.Equal(end) {
+		return fmt.Errorf("%w: quiet hours need distinct HH:MM start and end times", ErrInvalidPreferences)
+	}
+	if _, err := loadLocation(quiet.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrInvalidPreferences, quiet.Timezone)
+	}
+	if quiet.Mode != "" && !IsValidSuppressionMode(quiet.Mode) {
+		return fmt.Errorf("%w: quiet hours mode must be one of: suppress, defer", ErrInvalidPreferences)
+	}
+	return nil
+}
+
+// InQuietHours reports whether now falls into the user's quiet hours. Invalid
+// quiet hours never match.
+func InQuietHours(quiet *model.QuietHours, now time.Time) bool {
+	if quiet == nil {
+		return false
+	}
+	location, err := loadLocation(quiet.Timezone)
+	if err != nil {
+		return false
+	}
+	start, startErr := time.Parse(quietHoursLayout, quiet.Start)
+	end, endErr := time.Parse(quietHoursLayout, quiet.End)
+	if startErr != nil || endErr != nil {
+		return false
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// QuietHoursMode returns the effective mode of quiet hours, suppress unless
+// set otherwise.
+func QuietHoursMode(quiet *model.QuietHours) string {
+	if quiet == nil || quiet.Mode == "" {
+		return SuppressionSuppress
+	}
+	return quiet.Mode
+}
+
+// locations caches loaded time zones; InQuietHours runs for every delivery.
+var locations sync.Map
+
+func loadLocation(name string) (*time.Location, error) {
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+	return location, nil
+}
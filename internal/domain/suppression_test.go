@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func TestInQuietHours(t *testing.T) {
+	overnight := &model.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin"}
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	require.True(t, InQuietHours(overnight, at("2026-01-10T21:30:00Z")))  // 22:30 in Berlin
+	require.True(t, InQuietHours(overnight, at("2026-01-10T05:59:00Z")))  // 06:59
+	require.False(t, InQuietHours(overnight, at("2026-01-10T06:00:00Z"))) // 07:00
+	require.False(t, InQuietHours(overnight, at("2026-01-10T12:00:00Z")))
+	require.True(t, InQuietHours(&model.QuietHours{Start: "12:00", End: "13:00"}, at("2026-01-10T12:30:00Z")))
+	require.False(t, InQuietHours(nil, at("2026-01-10T12:30:00Z")))
+
+	require.NoError(t, ValidateQuietHours(*overnight))
+	require.ErrorIs(t, ValidateQuietHours(model.QuietHours{Start: "22:00", End: "22:00"}), ErrInvalidPreferences)
+	require.ErrorIs(t, ValidateQuietHours(model.QuietHours{Start: "22:00", End: "07:00", Mode: "drop"}), ErrInvalidPreferences)
+}
+
+func TestMaintenanceWindowCovers(t *testing.T) {
+	now := time.Now()
+	window := model.MaintenanceWindow{Room: "ops-*", Type: NotificationTypeWarning, Mode: SuppressionSuppress, StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)}
+	require.NoError(t, ValidateMaintenanceWindow(window, now))
+
+	require.True(t, MaintenanceWindowCovers(window, model.Notification{Room: "ops-eu", Type: NotificationTypeWarning}, now))
+	require.False(t, MaintenanceWindowCovers(window, model.Notification{Room: "ops-eu", Type: NotificationTypeInfo}, now))
+	require.False(t, MaintenanceWindowCovers(window, model.Notification{Room: "dev", Type: NotificationTypeWarning}, now))
+	require.False(t, MaintenanceWindowCovers(window, model.Notification{Room: "ops-eu", Type: NotificationTypeWarning}, now.Add(time.Hour)))
+
+	window.EndsAt = window.StartsAt.Add(8 * 24 * time.Hour)
+	require.ErrorIs(t, ValidateMaintenanceWindow(window, now), ErrInvalidMaintenanceWindow)
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidIDMode       = errors.New("invalid id mode")
+	ErrInvalidImportRecord = errors.New("notification requires room, type, title and body")
+)
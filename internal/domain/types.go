@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"sse_demo/internal/model"
+)
+
+var typeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// IsValidTypeName reports whether value can be registered as a notification
+// type. Type names end up in RabbitMQ routing keys, so they are restricted to
+// lowercase letters, digits, '-' and '_'.
+func IsValidTypeName(value string) bool {
+	return typeNamePattern.MatchString(value)
+}
+
+// DefaultNotificationTypes returns the built-in types used when no type
+// configuration is provided.
+func DefaultNotificationTypes() []model.NotificationType {
+	return []model.NotificationType{
+		{Name: NotificationTypeInfo, DefaultSeverity: SeverityNormal},
+		{Name: NotificationTypeWarning, DefaultSeverity: SeverityHigh},
+		{Name: NotificationTypeSystem, DefaultSeverity: SeverityNormal},
+	}
+}
+
+// TypeRegistry holds the currently registered notification types. It is safe
+// for concurrent use.
+type TypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]model.NotificationType
+}
+
+func NewTypeRegistry(types ...model.NotificationType) *TypeRegistry {
+	r := &TypeRegistry{types: make(map[string]model.NotificationType, len(types))}
+	for _, t := range types {
+		r.types[t.Name] = t
+	}
+	return r
+}
+
+func (r *TypeRegistry) Lookup(name string) (model.NotificationType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.types[name]
+	return t, ok
+}
+
+// Names returns the registered type names in sorted order.
+func (r *TypeRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns the registered types sorted by name.
+func (r *TypeRegistry) List() []model.NotificationType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]model.NotificationType, 0, len(r.types))
+	for _, t := range r.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+func (r *TypeRegistry) Set(t model.NotificationType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[t.Name] = t
+}
+
+func (r *TypeRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.types, name)
+}
+
+// Replace swaps the whole set of registered types at once.
+func (r *TypeRegistry) Replace(types ...model.NotificationType) {
+	replaced := make(map[string]model.NotificationType, len(types))
+	for _, t := range types {
+		replaced[t.Name] = t
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = replaced
+}
+
+// Validate checks that typeName is registered and allowed in room. The
+// returned errors wrap ErrInvalidNotificationType or ErrTypeNotAllowedInRoom
+// and describe the registered types.
+func (r *TypeRegistry) Validate(typeName, room string) (model.NotificationType, error) {
+	t, ok := r.Lookup(typeName)
+	if !ok {
+		return model.NotificationType{}, fmt.Errorf("%w: type must be one of: %s", ErrInvalidNotificationType, strings.Join(r.Names(), ", "))
+	}
+	if !roomAllowed(t.AllowedRooms, room) {
+		return model.NotificationType{}, fmt.Errorf("%w: type %s is not allowed in room %s", ErrTypeNotAllowedInRoom, typeName, room)
+	}
+	return t, nil
+}
+
+// ValidateNotificationType checks the metadata of a type definition.
+func ValidateNotificationType(t model.NotificationType) error {
+	if !IsValidTypeName(t.Name) {
+		return fmt.Errorf("%w: name must match %s", ErrInvalidNotificationType, typeNamePattern.String())
+	}
+	if t.DefaultSeverity != "" && !IsValidSeverity(t.DefaultSeverity) {
+		return fmt.Errorf("%w: %s", ErrInvalidSeverity, t.DefaultSeverity)
+	}
+	if t.DefaultTTLSeconds < 0 {
+		return fmt.Errorf("%w: default_ttl_seconds must not be negative", ErrInvalidNotificationType)
+	}
+	for _, pattern := range t.AllowedRooms {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: invalid allowed room pattern %q", ErrInvalidNotificationType, pattern)
+		}
+	}
+	return nil
+}
+
+// roomAllowed matches room against glob patterns such as "ops-*". An empty
+// list allows every room.
+func roomAllowed(patterns []string, room string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, room); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
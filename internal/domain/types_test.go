@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func TestTypeRegistryValidate(t *testing.T) {
+	registry := NewTypeRegistry(append(DefaultNotificationTypes(),
+		model.NotificationType{Name: "security", AllowedRooms: []string{"ops-*", "security"}},
+	)...)
+
+	t.Run("registered type", func(t *testing.T) {
+		got, err := registry.Validate(NotificationTypeWarning, "room-1")
+		require.NoError(t, err)
+		require.Equal(t, SeverityHigh, got.DefaultSeverity)
+	})
+
+	t.Run("unknown type lists registered types", func(t *testing.T) {
+		_, err := registry.Validate("success", "room-1")
+		require.ErrorIs(t, err, ErrInvalidNotificationType)
+		require.Contains(t, err.Error(), "info, security, system, warning")
+	})
+
+	t.Run("allowed rooms", func(t *testing.T) {
+		_, err := registry.Validate("security", "ops-eu")
+		require.NoError(t, err)
+		_, err = registry.Validate("security", "security")
+		require.NoError(t, err)
+		_, err = registry.Validate("security", "room-1")
+		require.ErrorIs(t, err, ErrTypeNotAllowedInRoom)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		registry.Remove("security")
+		_, err := registry.Validate("security", "security")
+		require.ErrorIs(t, err, ErrInvalidNotificationType)
+	})
+}
+
+func TestValidateNotificationType(t *testing.T) {
+	require.NoError(t, ValidateNotificationType(model.NotificationType{Name: "success", DefaultSeverity: SeverityLow, DefaultTTLSeconds: 60}))
+	require.ErrorIs(t, ValidateNotificationType(model.NotificationType{Name: "Bad Name"}), ErrInvalidNotificationType)
+	require.ErrorIs(t, ValidateNotificationType(model.NotificationType{Name: "success", DefaultSeverity: "urgent"}), ErrInvalidSeverity)
+	require.ErrorIs(t, ValidateNotificationType(model.NotificationType{Name: "success", DefaultTTLSeconds: -1}), ErrInvalidNotificationType)
+	require.ErrorIs(t, ValidateNotificationType(model.NotificationType{Name: "success", AllowedRooms: []string{"["}}), ErrInvalidNotificationType)
+}
@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"sse_demo/internal/model"
+)
+
+// Length limits in characters, matching the notifications columns.
+const (
+	MaxTitleLength = 255
+	MaxBodyLength  = 10000
+)
+
+// Limits on template params, which are stored with the notification.
+const (
+	MaxTemplateParams         = 32
+	MaxTemplateParamKeyLength = 64
+	MaxTemplateParamLength    = 255
+)
+
+// Sanitization modes for NOTIFICATION_SANITIZE. html strips tags from titles
+// and bodies and escapes any < or > left over; markdown additionally turns
+// links and images whose target is not http, https or mailto into their text.
+const (
+	SanitizeNone     = "none"
+	SanitizeHTML     = "html"
+	SanitizeMarkdown = "markdown"
+)
+
+var ErrInvalidNotification = errors.New("invalid notification")
+
+var (
+	htmlTagPattern      = regexp.MustCompile(`<!--[\s\S]*?-->|</?[A-Za-z][^<>]*>`)
+	markdownLinkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\(((?:[^()]|\([^()]*\))*)\)`)
+	markdownLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+	angleEscaper        = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+)
+
+// FieldError reports why one field was rejected. Err is the domain error
+// behind it, so callers can still match it with errors.Is.
+type FieldError struct {
+	Field   string
+	Message string
+	Err     error
+}
+
+// ValidationError collects every rejected field of a notification. It
+// matches ErrInvalidNotification and the Err of each field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Add records err for field. The message is err without the text of the
+// domain error it wraps, e.g. "must not exceed 8192 bytes".
+func (e *ValidationError) Add(field string, err error) {
+	message := err.Error()
+	if inner := errors.Unwrap(err); inner != nil {
+		message = strings.TrimPrefix(message, inner.Error()+": ")
+	}
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message, Err: err})
+}
+
+func (e *ValidationError) addf(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...), Err: ErrInvalidNotification})
+}
+
+// Err returns e if any field was rejected and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Summary joins the field messages, e.g. "title must not exceed 255
+// characters; body is required".
+func (e *ValidationError) Summary() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Error() string {
+	return ErrInvalidNotification.Error() + ": " + e.Summary()
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrInvalidNotification}
+	for _, field := range e.Fields {
+		if field.Err != ErrInvalidNotification {
+			errs = append(errs, field.Err)
+		}
+	}
+	return errs
+}
+
+// IsValidSanitizeMode reports whether value is one of the sanitization modes.
+func IsValidSanitizeMode(value string) bool {
+	switch value {
+	case SanitizeNone, SanitizeHTML, SanitizeMarkdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateNotificationFields checks the required fields, text lengths, room
+// name syntax and UTF-8 validity of a notification and collects every
+// rejected field; Err on the result is nil when there are none. Type
+// specific rules are checked against the type registry by the service.
+func ValidateNotificationFields(notification model.Notification) *ValidationError {
+	invalid := &ValidationError{}
+	switch {
+	case notification.Room == "":
+		invalid.addf("room", "is required")
+	case !IsValidRoomName(notification.Room):
+		invalid.addf("room", roomNameRule)
+	}
+	if notification.Type == "" {
+		invalid.addf("type", "is required")
+	}
+	checkText(invalid, "title", notification.Title, MaxTitleLength, true)
+	checkText(invalid, "body", notification.Body, MaxBodyLength, true)
+
+	locales := make([]string, 0, len(notification.Localizations))
+	for locale := range notification.Localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		content := notification.Localizations[locale]
+		checkText(invalid, "localizations."+locale+".title", content.Title, MaxTitleLength, false)
+		checkText(invalid, "localizations."+locale+".body", content.Body, MaxBodyLength, false)
+	}
+
+	names := make([]string, 0, len(notification.TemplateParams))
+	for name := range notification.TemplateParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > MaxTemplateParams {
+		invalid.addf("template_params", "must not have more than %d entries", MaxTemplateParams)
+		return invalid
+	}
+	badKey := false
+	for _, name := range names {
+		if name == "" || !utf8.ValidString(name) || utf8.RuneCountInString(name) > MaxTemplateParamKeyLength {
+			if !badKey {
+				invalid.addf("template_params", "keys must be 1-%d characters of valid UTF-8", MaxTemplateParamKeyLength)
+				badKey = true
+			}
+			continue
+		}
+		checkText(invalid, "template_params."+name, notification.TemplateParams[name], MaxTemplateParamLength, false)
+	}
+	return invalid
+}
+
+func checkText(invalid *ValidationError, field, value string, maxLength int, required bool) {
+	switch {
+	case value == "":
+		if required {
+			invalid.addf(field, "is required")
+		}
+	case !utf8.ValidString(value):
+		invalid.addf(field, "must be valid UTF-8")
+	case utf8.RuneCountInString(value) > maxLength:
+		invalid.addf(field, "must not exceed %d characters", maxLength)
+	}
+}
+
+// CleanNotification strips control characters from the title and body of a
+// notification and its localizations, keeping line breaks and tabs in
+// bodies, and applies the sanitization mode. Template params are substituted
+// into titles as well as bodies, so their values are cleaned like titles.
+// Localizations and template params are replaced by a cleaned copy, so a map
+// shared with other copies of the notification is left alone. Text that is
+// not valid UTF-8 is left for ValidateNotificationFields to reject. Cleaning
+// is idempotent.
+func CleanNotification(notification *model.Notification, mode string) {
+	notification.Title = cleanText(notification.Title, mode, false)
+	notification.Body = cleanText(notification.Body, mode, true)
+	if notification.TemplateParams != nil {
+		params := make(map[string]string, len(notification.TemplateParams))
+		for name, value := range notification.TemplateParams {
+			params[name] = cleanText(value, mode, false)
+		}
+		notification.TemplateParams = params
+	}
+	if notification.Localizations == nil {
+		return
+	}
+	localizations := make(map[string]model.LocalizedContent, len(notification.Localizations))
+	for locale, content := range notification.Localizations {
+		content.Title = cleanText(content.Title, mode, false)
+		content.Body = cleanText(content.Body, mode, true)
+		localizations[locale] = content
+	}
+	notification.Localizations = localizations
+}
+
+func cleanText(value, mode string, multiline bool) string {
+	if value == "" || !utf8.ValidString(value) {
+		return value
+	}
+	value = strings.Map(func(r rune) rune {
+		if multiline && (r == '\n' || r == '\t') {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+	switch mode {
+	case SanitizeMarkdown:
+		value = markdownLinkPattern.ReplaceAllStringFunc(value, safeMarkdownLink)
+		value = stripTags(value)
+	case SanitizeHTML:
+		value = stripTags(value)
+	}
+	if !multiline {
+		value = strings.TrimSpace(value)
+	}
+	return value
+}
+
+// stripTags removes tags until none are left, so tags split by other tags
+// ("<scr<b>ipt>") cannot reassemble, and escapes any remaining < and >.
+func stripTags(value string) string {
+	for {
+		stripped := htmlTagPattern.ReplaceAllString(value, "")
+		if stripped == value {
+			break
+		}
+		value = stripped
+	}
+	return angleEscaper.Replace(value)
+}
+
+// safeMarkdownLink keeps a link or image whose target is an absolute http,
+// https or mailto URL and replaces any other with its text. Tabs and line
+// breaks are dropped from the target first, as browsers do, so they cannot
+// hide a scheme.
+func safeMarkdownLink(link string) string {
+	match := markdownLinkPattern.FindStringSubmatch(link)
+	target := strings.Fields(strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, match[2]))
+	if len(target) > 0 {
+		if u, err := url.Parse(target[0]); err == nil && markdownLinkSchemes[strings.ToLower(u.Scheme)] {
+			return link
+		}
+	}
+	return match[1]
+}
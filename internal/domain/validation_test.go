@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func validNotification() model.Notification {
+	return model.Notification{Room: "ops", Type: NotificationTypeInfo, Title: "Deploy", Body: "Deploy finished"}
+}
+
+func TestValidateNotificationFields(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, ValidateNotificationFields(validNotification()).Err())
+	})
+
+	t.Run("collects every rejected field", func(t *testing.T) {
+		err := ValidateNotificationFields(model.Notification{Room: "bad room", Title: strings.Repeat("a", MaxTitleLength+1)}).Err()
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrInvalidNotification)
+
+		var invalid *ValidationError
+		require.True(t, errors.As(err, &invalid))
+		fields := make([]string, len(invalid.Fields))
+		for i, field := range invalid.Fields {
+			fields[i] = field.Field
+		}
+		require.Equal(t, []string{"room", "type", "title", "body"}, fields)
+		require.Equal(t, "must not exceed 255 characters", invalid.Fields[2].Message)
+		require.Equal(t, "body is required", invalid.Fields[3].Field+" "+invalid.Fields[3].Message)
+	})
+
+	t.Run("lengths count characters", func(t *testing.T) {
+		n := validNotification()
+		n.Title = strings.Repeat("é", MaxTitleLength)
+		n.Body = strings.Repeat("b", MaxBodyLength)
+		require.NoError(t, ValidateNotificationFields(n).Err())
+
+		n.Body += "b"
+		require.ErrorContains(t, ValidateNotificationFields(n).Err(), "body must not exceed 10000 characters")
+	})
+
+	t.Run("invalid utf-8", func(t *testing.T) {
+		n := validNotification()
+		n.Body = "caf\xe9"
+		require.ErrorContains(t, ValidateNotificationFields(n).Err(), "body must be valid UTF-8")
+	})
+
+	t.Run("localizations", func(t *testing.T) {
+		n := validNotification()
+		n.Localizations = map[string]model.LocalizedContent{"de": {Title: strings.Repeat("t", MaxTitleLength+1)}}
+		require.ErrorContains(t, ValidateNotificationFields(n).Err(), "localizations.de.title must not exceed")
+	})
+
+	t.Run("template params", func(t *testing.T) {
+		n := validNotification()
+		n.TemplateParams = map[string]string{"service": "caf\xe9", "region": strings.Repeat("r", MaxTitleLength+1)}
+		err := ValidateNotificationFields(n).Err()
+		require.ErrorContains(t, err, "template_params.region must not exceed 255 characters")
+		require.ErrorContains(t, err, "template_params.service must be valid UTF-8")
+
+		n.TemplateParams = map[string]string{strings.Repeat("k", MaxTemplateParamKeyLength+1): "v"}
+		require.ErrorContains(t, ValidateNotificationFields(n).Err(), "template_params keys must be 1-64 characters")
+
+		n.TemplateParams = map[string]string{}
+		for i := range MaxTemplateParams + 1 {
+			n.TemplateParams[strings.Repeat("k", i+1)] = "v"
+		}
+		require.ErrorContains(t, ValidateNotificationFields(n).Err(), "template_params must not have more than 32 entries")
+	})
+}
+
+func TestCleanNotification(t *testing.T) {
+	t.Run("strips control characters", func(t *testing.T) {
+		n := validNotification()
+		n.Title = " Deploy\x00 done\x1b "
+		n.Body = "line one\nline\ttwo\x07"
+		CleanNotification(&n, SanitizeNone)
+		require.Equal(t, "Deploy done", n.Title)
+		require.Equal(t, "line one\nline\ttwo", n.Body)
+	})
+
+	t.Run("none keeps markup", func(t *testing.T) {
+		n := validNotification()
+		n.Body = "<b>bold</b>"
+		CleanNotification(&n, SanitizeNone)
+		require.Equal(t, "<b>bold</b>", n.Body)
+	})
+
+	t.Run("html strips tags", func(t *testing.T) {
+		n := validNotification()
+		n.Title = "<b>Deploy</b>"
+		n.Body = "a < b <script>alert(1)</script><!-- note -->done"
+		CleanNotification(&n, SanitizeHTML)
+		require.Equal(t, "Deploy", n.Title)
+		require.Equal(t, "a &lt; b alert(1)done", n.Body)
+	})
+
+	t.Run("nested tags cannot reassemble", func(t *testing.T) {
+		for _, mode := range []string{SanitizeHTML, SanitizeMarkdown} {
+			for input, want := range map[string]string{
+				"<scr<b>ipt>alert(1)</script>":     "alert(1)",
+				"<img src=x onerror=alert(1) <b>>": "",
+				"<<b>img src=x onerror=alert(1)>":  "",
+				"<img src=x onerror=alert(1)":      "&lt;img src=x onerror=alert(1)",
+			} {
+				n := validNotification()
+				n.Body = input
+				CleanNotification(&n, mode)
+				require.Equal(t, want, n.Body, "mode %s, input %q", mode, input)
+				require.NotContains(t, n.Body, "<")
+			}
+		}
+	})
+
+	t.Run("markdown drops unsafe links", func(t *testing.T) {
+		n := validNotification()
+		n.Body = "[docs](https://example.com) [click](javascript:alert(1)) ![img](data:image/png)"
+		CleanNotification(&n, SanitizeMarkdown)
+		require.Equal(t, "[docs](https://example.com) click img", n.Body)
+
+		n.Body = "[x](java\tscript:alert(1)) [y](\njavascript:alert(1)) [z](https://example.com \"title\")"
+		CleanNotification(&n, SanitizeMarkdown)
+		require.Equal(t, "x y [z](https://example.com \"title\")", n.Body)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		n := validNotification()
+		n.Body = "<scr<b>ipt>a < b</script> [x](javascript:alert(1))"
+		CleanNotification(&n, SanitizeMarkdown)
+		once := n.Body
+		CleanNotification(&n, SanitizeMarkdown)
+		require.Equal(t, once, n.Body)
+	})
+
+	t.Run("does not modify shared localizations", func(t *testing.T) {
+		localizations := map[string]model.LocalizedContent{"de": {Title: " <b>Titel</b> ", Body: "Text"}}
+		n := validNotification()
+		n.Localizations = localizations
+		CleanNotification(&n, SanitizeHTML)
+		require.Equal(t, "Titel", n.Localizations["de"].Title)
+		require.Equal(t, " <b>Titel</b> ", localizations["de"].Title)
+	})
+
+	t.Run("template params are cleaned like titles", func(t *testing.T) {
+		params := map[string]string{"service": " <script>api</script>\n"}
+		n := validNotification()
+		n.TemplateParams = params
+		CleanNotification(&n, SanitizeHTML)
+		require.Equal(t, "api", n.TemplateParams["service"])
+		require.Equal(t, " <script>api</script>\n", params["service"])
+	})
+
+	t.Run("leaves invalid utf-8 for validation", func(t *testing.T) {
+		n := validNotification()
+		n.Body = "caf\xe9\x00"
+		CleanNotification(&n, SanitizeHTML)
+		require.Equal(t, "caf\xe9\x00", n.Body)
+	})
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+func (h *Handler) InvokeAction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	userID := userIDFromRequest(c)
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "user id required"})
+		return
+	}
+	actionID := c.Param("action")
+
+	invocation, err := h.actions.Invoke(c.Request.Context(), id, actionID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotificationNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+		case errors.Is(err, domain.ErrActionNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "action not found"})
+		default:
+			h.log.Error("invoke action failed",
+				zap.Int64("notification_id", id),
+				zap.String("action_id", actionID),
+				zap.Error(err),
+			)
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to invoke action"})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, invocation)
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+)
+
+// CreateAnnouncement stores an announcement and broadcasts it to every
+// connected client, whatever room it is subscribed to.
+func (h *Handler) CreateAnnouncement(c *gin.Context) {
+	var req dto.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	announcement := model.Announcement{
+		Title:    req.Title,
+		Body:     req.Body,
+		Severity: req.Severity,
+		Link:     req.Link,
+	}
+	if req.ExpiresAt != nil {
+		announcement.ExpiresAt = *req.ExpiresAt
+	}
+	created, err := h.announce.Create(c.Request.Context(), announcement, userIDFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAnnouncement) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+			return
+		}
+		h.log.Error("create announcement failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to create announcement"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListAnnouncements lists the announcements that have not expired.
+func (h *Handler) ListAnnouncements(c *gin.Context) {
+	announcements, err := h.announce.ListActive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to list announcements"})
+		return
+	}
+	if announcements == nil {
+		announcements = []model.Announcement{}
+	}
+	c.JSON(http.StatusOK, announcements)
+}
+
+// DeleteAnnouncement withdraws an announcement; connected clients receive an
+// announcement.deleted frame.
+func (h *Handler) DeleteAnnouncement(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid announcement id"})
+		return
+	}
+	if err := h.announce.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrAnnouncementNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "announcement not found"})
+			return
+		}
+		h.log.Error("delete announcement failed", zap.Int64("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete announcement"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
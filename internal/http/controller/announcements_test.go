@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestAnnouncementsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	for _, body := range []map[string]any{
+		{"body": "b"},
+		{"title": "t"},
+		{"title": "t", "body": "b", "severity": "loud"},
+		{"title": "t", "body": "b", "expires_at": time.Now().Add(-time.Minute)},
+		{"title": "t", "body": "b", "expires_at": time.Now().Add(8 * 24 * time.Hour)},
+	} {
+		rec := performJSONRequest(t, router, http.MethodPost, "/announcements", body)
+		require.Equal(t, http.StatusBadRequest, rec.Code, "%v", body)
+	}
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/announcements", map[string]any{
+		"title": "Restart", "body": "The system restarts in 5 minutes", "severity": "high",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created model.Announcement
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.NotZero(t, created.ID)
+	require.WithinDuration(t, time.Now().Add(time.Hour), created.ExpiresAt, time.Minute)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/announcements", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var active []model.Announcement
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &active))
+	require.Len(t, active, 1)
+
+	path := "/announcements/" + strconv.FormatInt(created.ID, 10)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/announcements", nil))
+	require.JSONEq(t, `[]`, rec.Body.String())
+}
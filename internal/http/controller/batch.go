@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/service/notify"
+)
+
+const (
+	batchStatusCreated = "created"
+	batchStatusQueued  = "queued"
+	batchStatusInvalid = "invalid"
+	batchStatusFailed  = "failed"
+	batchStatusSkipped = "skipped"
+)
+
+// CreateNotificationBatch stores an array of notifications. The ?mode= query
+// parameter selects atomic (default) or best_effort handling of invalid items.
+func (h *Handler) CreateNotificationBatch(c *gin.Context) {
+	items, mode, ok := h.bindBatch(c)
+	if !ok {
+		return
+	}
+
+	response := dto.BatchResponse{Mode: string(mode), Items: make([]dto.BatchItemResponse, len(items))}
+	notifications := make([]model.Notification, len(items))
+	for i, item := range items {
+		response.Items[i] = dto.BatchItemResponse{Index: i}
+		notifications[i] = notificationFromRequest(item)
+	}
+
+	results, err := h.svc.CreateBatch(c.Request.Context(), notifications, mode)
+	if err != nil && !errors.Is(err, domain.ErrBatchRejected) {
+		h.log.Error("create notification batch failed", zap.Int("batch_size", len(items)), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to create notifications"})
+		return
+	}
+	for i, result := range results {
+		item := &response.Items[i]
+		if result.Err != nil {
+			h.markInvalid(item, result.Err)
+			continue
+		}
+		if err == nil {
+			created := result.Notification
+			item.Status = batchStatusCreated
+			item.Notification = &created
+		}
+	}
+	if err != nil {
+		h.writeBatch(c, http.StatusBadRequest, response, batchStatusSkipped)
+		return
+	}
+	h.writeBatch(c, http.StatusCreated, response, "")
+}
+
+// PublishNotificationBatch validates an array of notifications and publishes
+// them to RabbitMQ in order. In atomic mode nothing is published when an item
+// is invalid, and publishing stops at the first broker error; messages that
+// were already published stay queued.
+func (h *Handler) PublishNotificationBatch(c *gin.Context) {
+	items, mode, ok := h.bindBatch(c)
+	if !ok {
+		return
+	}
+
+	response := dto.BatchResponse{Mode: string(mode), Items: make([]dto.BatchItemResponse, len(items))}
+	invalid := 0
+	for i, item := range items {
+		response.Items[i] = dto.BatchItemResponse{Index: i}
+		err := h.svc.Validate(notificationFromRequest(item))
+		if err == nil {
+			err = h.svc.CheckRoom(c.Request.Context(), item.Room)
+			if err != nil && !errors.Is(err, domain.ErrRoomNotFound) {
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to publish notifications"})
+				return
+			}
+		}
+		if err != nil {
+			h.markInvalid(&response.Items[i], err)
+			invalid++
+		}
+	}
+	if invalid > 0 && mode == notify.BatchAtomic {
+		h.writeBatch(c, http.StatusBadRequest, response, batchStatusSkipped)
+		return
+	}
+
+	messages := make([]queue.Message, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		if response.Items[i].Status != "" {
+			continue
+		}
+		message, err := h.queueMessage(item)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to publish notifications"})
+			return
+		}
+		messages = append(messages, message)
+		positions = append(positions, i)
+	}
+
+	// The whole batch goes over one channel, which stops at the first
+	// failure; the items after it were not sent either.
+	var sent int
+	var err error
+	if len(messages) > 0 {
+		sent, err = h.pub.PublishBatch(c.Request.Context(), messages)
+	}
+	for _, i := range positions[:sent] {
+		response.Items[i].Status = batchStatusQueued
+	}
+	if err != nil {
+		h.log.Error("publish notification batch failed",
+			zap.Int("published", sent),
+			zap.Int("batch_size", len(messages)),
+			zap.Error(err),
+		)
+		unsent := positions[sent:]
+		if mode == notify.BatchAtomic {
+			unsent = unsent[:1]
+		}
+		for _, i := range unsent {
+			response.Items[i].Status = batchStatusFailed
+			response.Items[i].Error = "failed to publish notification"
+		}
+		if mode == notify.BatchAtomic {
+			h.writeBatch(c, http.StatusInternalServerError, response, batchStatusSkipped)
+			return
+		}
+	}
+	h.writeBatch(c, http.StatusAccepted, response, "")
+}
+
+// bindBatch decodes the request array and the batch mode, writing the error
+// response itself when the request is unusable.
+func (h *Handler) bindBatch(c *gin.Context) ([]dto.CreateNotificationRequest, notify.BatchMode, bool) {
+	mode, err := notify.ParseBatchMode(c.Query("mode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "mode must be one of: atomic, best_effort"})
+		return nil, "", false
+	}
+	var items []dto.CreateNotificationRequest
+	if err := c.ShouldBindJSON(&items); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return nil, "", false
+	}
+	if len(items) == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "batch must contain at least one notification"})
+		return nil, "", false
+	}
+	if h.cfg.BatchMaxSize > 0 && len(items) > h.cfg.BatchMaxSize {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: fmt.Sprintf("batch must contain at most %d notifications", h.cfg.BatchMaxSize)})
+		return nil, "", false
+	}
+	return items, mode, true
+}
+
+// writeBatch fills in the counters and writes the response. Items without a
+// status are marked with pending, which callers use for items that were not
+// processed because the batch was rejected.
+func (h *Handler) writeBatch(c *gin.Context, status int, response dto.BatchResponse, pending string) {
+	for i := range response.Items {
+		item := &response.Items[i]
+		if item.Status == "" {
+			item.Status = pending
+		}
+		switch item.Status {
+		case batchStatusCreated, batchStatusQueued:
+			response.Succeeded++
+		default:
+			response.Failed++
+		}
+	}
+	if status < http.StatusBadRequest && response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, response)
+}
+
+// markInvalid records a validation error on a batch item, including the
+// rejected fields when the error lists them.
+func (h *Handler) markInvalid(item *dto.BatchItemResponse, err error) {
+	item.Status = batchStatusInvalid
+	item.Error = "invalid notification"
+	if message, ok := h.validationMessage(err); ok {
+		item.Error = message
+	}
+	item.Fields = fieldErrors(err)
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/store/memory"
+)
+
+func TestCreateNotificationBatchController(t *testing.T) {
+	batch := []map[string]string{
+		{"room": "room-1", "type": domain.NotificationTypeInfo, "title": "first", "body": "body"},
+		{"room": "room-1", "type": "bad", "title": "second", "body": "body"},
+		{"room": "room-1", "title": "third"},
+	}
+
+	t.Run("atomic rejects with per-item errors", func(t *testing.T) {
+		repo := &repoMock{}
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/batch", batch)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var respBody dto.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, "atomic", respBody.Mode)
+		require.Equal(t, 0, respBody.Succeeded)
+		require.Equal(t, 3, respBody.Failed)
+		require.Equal(t, "skipped", respBody.Items[0].Status)
+		require.Equal(t, "invalid", respBody.Items[1].Status)
+		require.Contains(t, respBody.Items[1].Error, "type must be one of")
+		require.Equal(t, "invalid", respBody.Items[2].Status)
+		require.Equal(t, []dto.FieldError{
+			{Field: "type", Message: "is required"},
+			{Field: "body", Message: "is required"},
+		}, respBody.Items[2].Fields)
+		repo.AssertNotCalled(t, "CreateNotifications", mock.Anything, mock.Anything)
+	})
+
+	t.Run("best effort stores valid items", func(t *testing.T) {
+		router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/batch?mode=best_effort", batch)
+
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		var respBody dto.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, 1, respBody.Succeeded)
+		require.Equal(t, 2, respBody.Failed)
+		require.Equal(t, "created", respBody.Items[0].Status)
+		require.NotNil(t, respBody.Items[0].Notification)
+		require.NotZero(t, respBody.Items[0].Notification.ID)
+	})
+
+	t.Run("all valid", func(t *testing.T) {
+		router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/batch", batch[:1])
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		router := setupRouter(t, &repoMock{}, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/batch", append(batch, batch[0]))
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		router := setupRouter(t, &repoMock{}, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/batch?mode=some", batch)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestPublishNotificationBatchController(t *testing.T) {
+	batch := []map[string]string{
+		{"room": "room-1", "type": domain.NotificationTypeInfo, "title": "first", "body": "body"},
+		{"room": "room-1", "type": domain.NotificationTypeWarning, "title": "second", "body": "body"},
+	}
+
+	t.Run("publishes in order as one batch", func(t *testing.T) {
+		pub := &publisherMock{}
+		pub.On("PublishBatch", mock.Anything, mock.Anything).Return(2, nil).Once()
+		router := setupRouter(t, &repoMock{}, pub)
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/publish/batch", batch)
+
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		pub.AssertExpectations(t)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+		messages := pub.Calls[0].Arguments.Get(1).([]queue.Message)
+		require.Len(t, messages, 2)
+		require.Equal(t, "notification."+domain.NotificationTypeInfo, messages[0].RoutingKey)
+		require.Equal(t, "notification."+domain.NotificationTypeWarning, messages[1].RoutingKey)
+	})
+
+	t.Run("atomic stops at publish error", func(t *testing.T) {
+		pub := &publisherMock{}
+		pub.On("PublishBatch", mock.Anything, mock.Anything).Return(0, errors.New("publish failed")).Once()
+		router := setupRouter(t, &repoMock{}, pub)
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/publish/batch", batch)
+
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		var respBody dto.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, "failed", respBody.Items[0].Status)
+		require.Equal(t, "skipped", respBody.Items[1].Status)
+		pub.AssertExpectations(t)
+	})
+
+	t.Run("best effort fails the unsent rest", func(t *testing.T) {
+		pub := &publisherMock{}
+		pub.On("PublishBatch", mock.Anything, mock.Anything).Return(1, errors.New("publish failed")).Once()
+		router := setupRouter(t, &repoMock{}, pub)
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/publish/batch?mode=best_effort", batch)
+
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		var respBody dto.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, "queued", respBody.Items[0].Status)
+		require.Equal(t, "failed", respBody.Items[1].Status)
+	})
+
+	t.Run("best effort skips invalid items", func(t *testing.T) {
+		pub := &publisherMock{}
+		pub.On("PublishBatch", mock.Anything, mock.MatchedBy(func(messages []queue.Message) bool { return len(messages) == 1 })).Return(1, nil).Once()
+		router := setupRouter(t, &repoMock{}, pub)
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/publish/batch?mode=best_effort", []map[string]string{
+			batch[0],
+			{"room": "room-1", "type": "bad", "title": "t", "body": "b"},
+		})
+
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		var respBody dto.BatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, "queued", respBody.Items[0].Status)
+		require.Equal(t, "invalid", respBody.Items[1].Status)
+		pub.AssertExpectations(t)
+	})
+}
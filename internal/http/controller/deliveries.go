@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+// GetNotificationDelivery reports how many clients a notification was queued
+// to and dropped for, in total and per identified user.
+func (h *Handler) GetNotificationDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	stats, err := h.deliveries.Get(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+			return
+		}
+		h.log.Error("get notification delivery failed", zap.Int64("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to get notification delivery"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
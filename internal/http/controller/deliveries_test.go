@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestGetNotificationDelivery(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]any{
+		"room": "room-1", "type": "info", "title": "t", "body": "b",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created model.Notification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications/"+strconv.FormatInt(created.ID, 10)+"/delivery", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"notification_id":`+strconv.FormatInt(created.ID, 10)+`,"enqueued":0,"dropped":0,"users":[]}`, rec.Body.String())
+
+	for path, code := range map[string]int{
+		"/notifications/abc/delivery":  http.StatusBadRequest,
+		"/notifications/9999/delivery": http.StatusNotFound,
+	} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, code, rec.Code, path)
+	}
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+// AckNotification acknowledges a notification for the calling user, which
+// cancels its pending escalation.
+func (h *Handler) AckNotification(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	userID := userIDFromRequest(c)
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "user id required"})
+		return
+	}
+
+	ack, err := h.escalations.Ack(c.Request.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+			return
+		}
+		h.log.Error("ack notification failed", zap.Int64("notification_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to ack notification"})
+		return
+	}
+	c.JSON(http.StatusOK, ack)
+}
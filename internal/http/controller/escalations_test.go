@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestAckNotificationController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]any{
+		"room": "ops", "type": "warning", "title": "t", "body": "b",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created model.Notification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/notifications/" + strconv.FormatInt(created.ID, 10) + "/ack"
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("X-User-ID", "alice")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var ack model.NotificationAck
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ack))
+	require.Equal(t, created.ID, ack.NotificationID)
+	require.Equal(t, "alice", ack.UserID)
+	require.False(t, ack.EscalationCancelled)
+
+	req = httptest.NewRequest(http.MethodPost, "/notifications/9999/ack", nil)
+	req.Header.Set("X-User-ID", "alice")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
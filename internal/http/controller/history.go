@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/i18n"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/service/notify"
+)
+
+const maxHistoryPageSize = 100
+
+// ListRoomNotifications returns a page of room history. Pages are newest
+// first; ?before= and ?after= take the cursors from the previous page.
+// ?after_seq= starts a forward page after a room sequence number, for clients
+// that detected a gap. The page is the unfiltered room history; to replay
+// only what the live stream would deliver, reconnect with ?after_seq=.
+func (h *Handler) ListRoomNotifications(c *gin.Context) {
+	room := c.Param("room")
+	opts, err := h.historyOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+		return
+	}
+
+	page, err := h.svc.ListHistoryPage(c.Request.Context(), room, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPriority) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "min_priority must be one of: low, normal, high"})
+			return
+		}
+		h.log.Error("list room notifications failed", zap.String("room", room), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to list notifications"})
+		return
+	}
+
+	// Muted notifications are dropped after paging so the cursors still
+	// step over them; a page may come back shorter than the limit.
+	prefs, err := h.prefs.Get(c.Request.Context(), userIDFromRequest(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to list notifications"})
+		return
+	}
+	allowed := notify.FilterAllowed(prefs, page.Notifications)
+
+	locales := i18n.Chain(requestedLocales(c), h.cfg.DefaultLocale)
+	notifications := make([]model.Notification, len(allowed))
+	for i, notification := range allowed {
+		notifications[i] = h.svc.Localize(notification, locales)
+	}
+	c.JSON(http.StatusOK, dto.HistoryResponse{
+		Notifications: notifications,
+		NextBefore:    page.NextBefore,
+		NextAfter:     page.NextAfter,
+		NextAfterSeq:  page.NextAfterSeq,
+	})
+}
+
+// historyOptions parses the paging and filter query parameters. The returned
+// error message is safe to show to the client.
+func (h *Handler) historyOptions(c *gin.Context) (repository.ListOptions, error) {
+	opts := repository.ListOptions{
+		Limit:       h.cfg.HistoryLimit,
+		MinPriority: c.Query("min_priority"),
+		Type:        c.Query("type"),
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryPageSize {
+			return opts, fmt.Errorf("limit must be between 1 and %d", maxHistoryPageSize)
+		}
+		opts.Limit = n
+	}
+	opts.Limit = min(max(opts.Limit, 1), maxHistoryPageSize)
+
+	for _, cursor := range []struct {
+		name   string
+		target *int64
+	}{{"before", &opts.BeforeID}, {"after", &opts.AfterID}} {
+		if v := c.Query(cursor.name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id <= 0 {
+				return opts, fmt.Errorf("%s must be a notification id", cursor.name)
+			}
+			*cursor.target = id
+		}
+	}
+	if v := c.Query("after_seq"); v != "" {
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seq < 0 {
+			return opts, fmt.Errorf("after_seq must be a room sequence number")
+		}
+		opts.AfterSeq = seq
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"since", &opts.Since}, {"until", &opts.Until}} {
+		if v := c.Query(bound.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return opts, fmt.Errorf("%s must be an RFC 3339 timestamp", bound.name)
+			}
+			t = t.UTC()
+			*bound.target = &t
+		}
+	}
+	return opts, nil
+}
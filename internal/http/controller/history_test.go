@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestListRoomNotificationsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+	var ids []int64
+	for i := range 5 {
+		notificationType := domain.NotificationTypeInfo
+		if i == 4 {
+			notificationType = domain.NotificationTypeWarning
+		}
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+			"room":  "room-1",
+			"type":  notificationType,
+			"title": fmt.Sprintf("title %d", i),
+			"body":  "body",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var created model.Notification
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		ids = append(ids, created.ID)
+	}
+
+	list := func(query string) dto.HistoryResponse {
+		t.Helper()
+		rec := performJSONRequest(t, router, http.MethodGet, "/rooms/room-1/notifications"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page dto.HistoryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+	pageIDs := func(page dto.HistoryResponse) []int64 {
+		var got []int64
+		for _, n := range page.Notifications {
+			got = append(got, n.ID)
+		}
+		return got
+	}
+
+	t.Run("pages backwards", func(t *testing.T) {
+		page := list("?limit=2")
+		require.Equal(t, []int64{ids[4], ids[3]}, pageIDs(page))
+		require.Equal(t, ids[3], page.NextBefore)
+
+		page = list("?limit=2&before=" + strconv.FormatInt(page.NextBefore, 10))
+		require.Equal(t, []int64{ids[2], ids[1]}, pageIDs(page))
+
+		page = list("?limit=2&before=" + strconv.FormatInt(page.NextBefore, 10))
+		require.Equal(t, []int64{ids[0]}, pageIDs(page))
+		require.Zero(t, page.NextBefore)
+	})
+
+	t.Run("pages forwards", func(t *testing.T) {
+		page := list("?limit=2&after=" + strconv.FormatInt(ids[0], 10))
+		require.Equal(t, []int64{ids[2], ids[1]}, pageIDs(page))
+		require.Equal(t, ids[2], page.NextAfter)
+		require.Zero(t, page.NextBefore)
+
+		page = list("?limit=2&after=" + strconv.FormatInt(page.NextAfter, 10))
+		require.Equal(t, []int64{ids[4], ids[3]}, pageIDs(page))
+		require.Zero(t, page.NextAfter)
+	})
+
+	t.Run("resyncs from a room sequence", func(t *testing.T) {
+		page := list("?limit=2&after_seq=2")
+		require.Equal(t, []int64{ids[3], ids[2]}, pageIDs(page))
+		require.Equal(t, []int64{4, 3}, []int64{page.Notifications[0].Seq, page.Notifications[1].Seq})
+		require.Equal(t, int64(4), page.NextAfterSeq)
+		require.Zero(t, page.NextAfter)
+	})
+
+	t.Run("type filter", func(t *testing.T) {
+		page := list("?type=" + domain.NotificationTypeWarning)
+		require.Equal(t, []int64{ids[4]}, pageIDs(page))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"?limit=0", "?limit=101", "?before=abc", "?since=yesterday", "?min_priority=urgent", "?after_seq=-1"} {
+			rec := performJSONRequest(t, router, http.MethodGet, "/rooms/room-1/notifications"+query, nil)
+			require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+// userIDFromRequest identifies the calling user. The X-User-ID header is set
+// by the authenticating proxy; EventSource cannot send custom headers, so SSE
+// connections may pass ?user_id= instead.
+func userIDFromRequest(c *gin.Context) string {
+	if v := strings.TrimSpace(c.GetHeader("X-User-ID")); v != "" {
+		return v
+	}
+	return strings.TrimSpace(c.Query("user_id"))
+}
+
+// authenticatedUserID returns the user named by the X-User-ID header only.
+// Use it wherever the identity grants access, since anyone can set the
+// ?user_id= fallback.
+func authenticatedUserID(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader("X-User-ID"))
+}
+
+// requireUser rejects the request with 403 unless the X-User-ID header names
+// userID, and reports whether it may proceed. The ?user_id= fallback is not
+// accepted here since anyone can set it.
+func requireUser(c *gin.Context, userID string) bool {
+	if authenticatedUserID(c) != userID {
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Code: resp.CodeForbidden, Message: "X-User-ID does not match the requested user"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+)
+
+func (h *Handler) CreateMaintenanceWindow(c *gin.Context) {
+	var req dto.CreateMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	window := model.MaintenanceWindow{
+		Room:   req.Room,
+		Type:   req.Type,
+		Mode:   req.Mode,
+		Reason: req.Reason,
+		EndsAt: req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		window.StartsAt = *req.StartsAt
+	}
+	created, err := h.maintenance.Create(c.Request.Context(), window)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMaintenanceWindow) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+			return
+		}
+		h.log.Error("create maintenance window failed", zap.String("room", req.Room), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to create maintenance window"})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListMaintenanceWindows lists the windows that have not ended yet.
+func (h *Handler) ListMaintenanceWindows(c *gin.Context) {
+	windows, err := h.maintenance.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to list maintenance windows"})
+		return
+	}
+	if windows == nil {
+		windows = []model.MaintenanceWindow{}
+	}
+	c.JSON(http.StatusOK, windows)
+}
+
+// DeleteMaintenanceWindow ends a window early; what it held back is released
+// immediately.
+func (h *Handler) DeleteMaintenanceWindow(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid maintenance window id"})
+		return
+	}
+	if err := h.maintenance.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrMaintenanceWindowNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "maintenance window not found"})
+			return
+		}
+		h.log.Error("delete maintenance window failed", zap.Int64("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete maintenance window"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
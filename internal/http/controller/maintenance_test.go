@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestMaintenanceWindowsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/maintenance-windows", map[string]any{
+		"room":    "ops-*",
+		"mode":    "mute",
+		"ends_at": time.Now().Add(time.Hour),
+	})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performJSONRequest(t, router, http.MethodPost, "/maintenance-windows", map[string]any{
+		"room":    "ops-*",
+		"type":    domain.NotificationTypeWarning,
+		"reason":  "db upgrade",
+		"ends_at": time.Now().Add(time.Hour),
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var window model.MaintenanceWindow
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &window))
+	require.Equal(t, domain.SuppressionSuppress, window.Mode)
+	require.False(t, window.StartsAt.IsZero())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/maintenance-windows", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var windows []model.MaintenanceWindow
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &windows))
+	require.Len(t, windows, 1)
+
+	// Notifications covered by the window are still stored.
+	rec = performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+		"room": "ops-eu", "type": domain.NotificationTypeWarning, "title": "held", "body": "body",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms/ops-eu/notifications", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"held"`)
+
+	path := "/maintenance-windows/" + strconv.FormatInt(window.ID, 10)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/maintenance-windows/abc", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/service/notify"
+)
+
+func (h *Handler) UpdateNotification(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	var req dto.UpdateNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	if (req.Title != nil && *req.Title == "") || (req.Body != nil && *req.Body == "") {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "title and body cannot be empty"})
+		return
+	}
+
+	updated, err := h.svc.Update(c.Request.Context(), id, notify.NotificationPatch{
+		Title:         req.Title,
+		Body:          req.Body,
+		Severity:      req.Severity,
+		Priority:      req.Priority,
+		Data:          req.Data,
+		Link:          req.Link,
+		Actions:       req.Actions,
+		Localizations: req.Localizations,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+			return
+		}
+		if message, ok := h.validationMessage(err); ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: message})
+			return
+		}
+		h.log.Error("update notification failed", zap.Int64("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to update notification"})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) DeleteNotification(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+			return
+		}
+		h.log.Error("delete notification failed", zap.Int64("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete notification"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
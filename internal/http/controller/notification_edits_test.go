@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestNotificationEditsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+	rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+		"room":  "room-1",
+		"type":  domain.NotificationTypeInfo,
+		"title": "title",
+		"body":  "body",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created model.Notification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/notifications/" + strconv.FormatInt(created.ID, 10)
+
+	t.Run("empty title", func(t *testing.T) {
+		rec := performJSONRequest(t, router, http.MethodPatch, path, map[string]string{"title": ""})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		rec := performJSONRequest(t, router, http.MethodPatch, path, map[string]any{
+			"body": "fixed body",
+			"data": map[string]int{"build": 7},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var updated model.Notification
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		require.Equal(t, "title", updated.Title)
+		require.Equal(t, "fixed body", updated.Body)
+		require.JSONEq(t, `{"build":7}`, string(updated.Data))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec := performJSONRequest(t, router, http.MethodDelete, path, nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodDelete, path, nil)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodPatch, path, map[string]string{"title": "again"})
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := performJSONRequest(t, router, http.MethodDelete, "/notifications/abc", nil)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
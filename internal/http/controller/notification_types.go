@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+)
+
+func (h *Handler) ListNotificationTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.types.List())
+}
+
+func (h *Handler) UpsertNotificationType(c *gin.Context) {
+	var req dto.UpsertNotificationTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	notificationType, err := h.types.Upsert(c.Request.Context(), model.NotificationType{
+		Name:              c.Param("name"),
+		DefaultSeverity:   req.DefaultSeverity,
+		DefaultTTLSeconds: req.DefaultTTLSeconds,
+		AllowedRooms:      req.AllowedRooms,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidNotificationType) || errors.Is(err, domain.ErrInvalidSeverity) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+			return
+		}
+		h.log.Error("upsert notification type failed", zap.String("name", c.Param("name")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to save notification type"})
+		return
+	}
+	c.JSON(http.StatusOK, notificationType)
+}
+
+func (h *Handler) DeleteNotificationType(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.types.Delete(c.Request.Context(), name); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotificationTypeNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification type not found"})
+		case errors.Is(err, domain.ErrConfiguredTypeReadOnly):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: resp.CodeConflict, Message: "notification type is defined in configuration"})
+		default:
+			h.log.Error("delete notification type failed", zap.String("name", name), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete notification type"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
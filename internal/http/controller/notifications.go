@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,22 +15,37 @@ import (
 	"sse_demo/internal/domain"
 	"sse_demo/internal/http/dto"
 	"sse_demo/internal/http/resp"
+	"sse_demo/internal/i18n"
 	"sse_demo/internal/model"
 	"sse_demo/internal/queue"
+	"sse_demo/internal/repository"
 	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type Handler struct {
-	cfg *config.Config
-	svc *notify.Service
-	hub *sse.Hub
-	log *zap.Logger
-	pub queue.Publisher
+	cfg         *config.Config
+	svc         *notify.Service
+	types       *notify.TypeService
+	actions     *notify.ActionService
+	transfer    *notify.TransferService
+	rooms       *notify.RoomService
+	prefs       *notify.PreferenceService
+	maintenance *notify.MaintenanceService
+	pins        *notify.PinService
+	state       *notify.StateService
+	announce    *notify.AnnouncementService
+	deliveries  *notify.DeliveryTracker
+	escalations *notify.EscalationService
+	hub         *sse.Hub
+	log         *zap.Logger
+	pub         queue.Publisher
 }
 
-func NewHandler(cfg *config.Config, svc *notify.Service, hub *sse.Hub, logger *zap.Logger, publisher queue.Publisher) *Handler {
-	return &Handler{cfg: cfg, svc: svc, hub: hub, log: logger, pub: publisher}
+func NewHandler(cfg *config.Config, svc *notify.Service, types *notify.TypeService, actions *notify.ActionService, transfer *notify.TransferService, rooms *notify.RoomService, prefs *notify.PreferenceService, maintenance *notify.MaintenanceService, pins *notify.PinService, state *notify.StateService, announce *notify.AnnouncementService, deliveries *notify.DeliveryTracker, escalations *notify.EscalationService, hub *sse.Hub, logger *zap.Logger, publisher queue.Publisher) *Handler {
+	return &Handler{cfg: cfg, svc: svc, types: types, actions: actions, transfer: transfer, rooms: rooms, prefs: prefs, maintenance: maintenance, pins: pins, state: state, announce: announce, deliveries: deliveries, escalations: escalations, hub: hub, log: logger, pub: publisher}
 }
 
 func (h *Handler) CreateNotification(c *gin.Context) {
@@ -38,19 +54,20 @@ func (h *Handler) CreateNotification(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
 		return
 	}
-	if req.Room == "" || req.Type == "" || req.Title == "" || req.Body == "" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "room, type, title, body are required"})
-		return
+	if key := c.GetHeader(idempotencyKeyHeader); key != "" {
+		req.DedupID = key
 	}
-	created, err := h.svc.Create(c.Request.Context(), model.Notification{
-		Room:  req.Room,
-		Type:  req.Type,
-		Title: req.Title,
-		Body:  req.Body,
-	})
+	created, replayed, err := h.svc.CreateIdempotent(c.Request.Context(), notificationFromRequest(req))
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidNotificationType) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "type must be one of: info, warning, system"})
+		if errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: resp.CodeConflict, Message: "a request with this idempotency key is in progress"})
+			return
+		}
+		if errors.Is(err, domain.ErrIdempotentNotificationGone) {
+			c.JSON(http.StatusGone, dto.ErrorResponse{Code: resp.CodeGone, Message: "the notification created for this idempotency key no longer exists"})
+			return
+		}
+		if h.writeValidationError(c, err) {
 			return
 		}
 		h.log.Error("create notification failed",
@@ -62,6 +79,11 @@ func (h *Handler) CreateNotification(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to create notification"})
 		return
 	}
+	if replayed {
+		c.Header("Idempotent-Replayed", "true")
+		c.JSON(http.StatusOK, created)
+		return
+	}
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -71,39 +93,23 @@ func (h *Handler) PublishNotification(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
 		return
 	}
-	if req.Room == "" || req.Type == "" || req.Title == "" || req.Body == "" {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "room, type, title, body are required"})
-		return
+	if key := c.GetHeader(idempotencyKeyHeader); key != "" {
+		req.DedupID = key
 	}
-	if !domain.IsValidNotificationType(req.Type) {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "type must be one of: info, warning, system"})
+	if err := h.svc.Validate(notificationFromRequest(req)); err != nil {
+		h.writeValidationError(c, err)
 		return
 	}
-
-	payload, err := json.Marshal(map[string]string{
-		"room":  req.Room,
-		"type":  req.Type,
-		"title": req.Title,
-		"body":  req.Body,
-	})
-	if err != nil {
-		h.log.Error("publish payload marshal failed", zap.Error(err))
+	if err := h.svc.CheckRoom(c.Request.Context(), req.Room); err != nil {
+		if message, ok := h.validationMessage(err); ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: message})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to publish notification"})
 		return
 	}
 
-	prefix := h.cfg.RabbitPublishPrefix
-	if prefix == "" {
-		prefix = "notification"
-	}
-	routingKey := prefix + "." + req.Type
-	if err := h.pub.Publish(c.Request.Context(), payload, routingKey); err != nil {
-		h.log.Error("publish notification failed",
-			zap.String("room", req.Room),
-			zap.String("type", req.Type),
-			zap.String("title", req.Title),
-			zap.Error(err),
-		)
+	if err := h.publish(c, req); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to publish notification"})
 		return
 	}
@@ -118,6 +124,53 @@ func (h *Handler) SSE(c *gin.Context) {
 		return
 	}
 
+	minPriority := c.Query("min_priority")
+	if minPriority != "" && !domain.IsValidPriority(minPriority) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "min_priority must be one of: low, normal, high"})
+		return
+	}
+
+	// A registered room's history limit replaces the server default and caps
+	// the ?limit= a client asks for. Strict mode refuses unregistered rooms.
+	limit, maxLimit := h.cfg.HistoryLimit, 0
+	registered, err := h.rooms.Get(c.Request.Context(), room)
+	switch {
+	case err == nil:
+		if registered.HistoryLimit > 0 {
+			limit, maxLimit = registered.HistoryLimit, registered.HistoryLimit
+		}
+	case errors.Is(err, domain.ErrRoomNotFound):
+		if h.cfg.RoomsStrict {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "room not found"})
+			return
+		}
+	case h.cfg.RoomsStrict:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to look up room"})
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	if maxLimit > 0 {
+		limit = min(limit, maxLimit)
+	}
+	// ?after_seq= resyncs a client that detected a gap: history then starts
+	// right after the last sequence number it saw instead of being the
+	// latest notifications. Gaps are expected when preferences, digests or
+	// maintenance windows filter the stream; the replay applies the same
+	// filters, so a resync never brings back what the stream left out.
+	var afterSeq int64
+	if v := c.Query("after_seq"); v != "" {
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seq < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "after_seq must be a room sequence number"})
+			return
+		}
+		afterSeq = seq
+	}
+
 	flusher, ok := c.Writer.(http.Flusher)
 	if !ok {
 		h.log.Error("streaming unsupported", zap.String("room", room))
@@ -131,19 +184,50 @@ func (h *Handler) SSE(c *gin.Context) {
 	c.Writer.Header().Set("X-Accel-Buffering", "no")
 	c.Status(http.StatusOK)
 
-	limit := h.cfg.HistoryLimit
-	if v := c.Query("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
-			limit = n
+	// History and live events are rendered with the same locale chain so a
+	// subscriber never sees a mix of languages.
+	locales := i18n.Chain(requestedLocales(c), h.cfg.DefaultLocale)
+
+	// Without stored preferences the stream falls back to delivering
+	// everything rather than failing the connection.
+	userID := userIDFromRequest(c)
+	prefs, err := h.prefs.Get(c.Request.Context(), userID)
+	if err != nil {
+		prefs = model.UserPreferences{UserID: userID}
+	}
+
+	// Pinned notifications come first, oldest pin first, and are left out of
+	// the regular history that follows.
+	pinnedIDs := make(map[int64]bool)
+	pinned, err := h.pins.List(c.Request.Context(), room)
+	if err != nil {
+		h.log.Error("list pinned notifications failed", zap.String("room", room), zap.Error(err))
+	}
+	for _, notification := range pinned {
+		pinnedIDs[notification.ID] = true
+	}
+	pinned = notify.FilterAllowed(prefs, pinned)
+	for i := len(pinned) - 1; i >= 0; i-- {
+		if minPriority != "" && domain.PriorityRank(pinned[i].Priority) < domain.PriorityRank(minPriority) {
+			continue
+		}
+		if err := writeNotification(c.Writer, h.svc.Localize(pinned[i], locales)); err != nil {
+			h.log.Error("write pinned notification failed", zap.String("room", room), zap.Error(err))
+			return
 		}
 	}
+	flusher.Flush()
 
-	history, err := h.svc.ListHistory(c.Request.Context(), room, limit)
+	history, err := h.svc.ListHistory(c.Request.Context(), room, repository.ListOptions{Limit: limit, MinPriority: minPriority, AfterSeq: afterSeq})
 	if err != nil {
 		h.log.Error("list history failed", zap.String("room", room), zap.Int("limit", limit), zap.Error(err))
 	} else {
+		history = notify.FilterAllowed(prefs, history)
 		for i := len(history) - 1; i >= 0; i-- {
-			if err := writeNotification(c.Writer, history[i]); err != nil {
+			if pinnedIDs[history[i].ID] || h.svc.Held(history[i]) {
+				continue
+			}
+			if err := writeNotification(c.Writer, h.svc.Localize(history[i], locales)); err != nil {
 				h.log.Error("write history notification failed", zap.String("room", room), zap.Error(err))
 				return
 			}
@@ -152,16 +236,71 @@ func (h *Handler) SSE(c *gin.Context) {
 	}
 
 	client := &sse.Client{
-		Room: room,
-		Ch:   make(chan model.Notification, 16),
+		Room:        room,
+		UserID:      userID,
+		Preferences: prefs,
+		DeferLimit:  h.cfg.SuppressionDeferLimit,
+		// Room for a full quiet-hours replay and its summary.
+		Ch:   make(chan sse.Event, max(16, h.cfg.SuppressionDeferLimit+1)),
+		High: make(chan sse.Event, 8),
 	}
 	h.hub.Register(client)
 	defer h.hub.Unregister(client)
 
+	// The state snapshot is read after registering so no change can slip in
+	// between; a change queued meanwhile only repeats a value or a newer one.
+	// Rooms without state get no snapshot; clients clear their copy on
+	// connect and apply the snapshot if one arrives.
+	snapshot, err := h.state.Snapshot(c.Request.Context(), room)
+	if err != nil {
+		h.log.Error("load room state failed", zap.String("room", room), zap.Error(err))
+	} else if len(snapshot.State) > 0 {
+		if err := writeEvent(c.Writer, sse.EventState, snapshot); err != nil {
+			h.log.Error("write room state failed", zap.String("room", room), zap.Error(err))
+			return
+		}
+		flusher.Flush()
+	}
+
+	// Active announcements are likewise read after registering. One created
+	// meanwhile may also be queued on the client; it is written only once.
+	announced := make(map[int64]bool)
+	announcements, err := h.announce.ListActive(c.Request.Context())
+	if err != nil {
+		h.log.Error("load announcements failed", zap.String("room", room), zap.Error(err))
+	}
+	for _, announcement := range announcements {
+		if err := writeEvent(c.Writer, sse.EventAnnouncement, announcement); err != nil {
+			h.log.Error("write announcement failed", zap.String("room", room), zap.Error(err))
+			return
+		}
+		announced[announcement.ID] = true
+	}
+	if len(announcements) > 0 {
+		flusher.Flush()
+	}
+	write := func(event sse.Event) bool {
+		if announcement, ok := event.Payload.(model.Announcement); ok && announced[announcement.ID] {
+			return true
+		}
+		return h.writeHubEvent(c.Writer, event, locales)
+	}
+
 	heartbeat := time.NewTicker(h.cfg.SSEHeartbeat)
 	defer heartbeat.Stop()
 
 	for {
+		// High-priority frames overtake whatever is buffered in client.Ch.
+		select {
+		case event := <-client.High:
+			if !write(event) {
+				return
+			}
+			flusher.Flush()
+			continue
+		default:
+		}
+
 		select {
 		case <-c.Request.Context().Done():
 			return
@@ -171,12 +310,13 @@ func (h *Handler) SSE(c *gin.Context) {
 				return
 			}
 			flusher.Flush()
-		case notification, ok := <-client.Ch:
-			if !ok {
+		case event := <-client.High:
+			if !write(event) {
 				return
 			}
-			if err := writeNotification(c.Writer, notification); err != nil {
-				h.log.Error("write notification failed", zap.String("room", room), zap.Error(err))
+			flusher.Flush()
+		case event, ok := <-client.Ch:
+			if !ok || !write(event) {
 				return
 			}
 			flusher.Flush()
@@ -184,6 +324,148 @@ func (h *Handler) SSE(c *gin.Context) {
 	}
 }
 
+// writeHubEvent renders a hub event for the subscriber's locales and reports
+// whether the stream is still writable.
+func (h *Handler) writeHubEvent(w http.ResponseWriter, event sse.Event, locales []string) bool {
+	var err error
+	switch event.Type {
+	case sse.EventNotification:
+		err = writeNotification(w, h.svc.Localize(event.Notification, locales))
+	case sse.EventUpdated:
+		err = writeEvent(w, event.Type, h.svc.Localize(event.Notification, locales))
+	default:
+		err = writeEvent(w, event.Type, event.Payload)
+	}
+	if err != nil {
+		h.log.Error("write event failed", zap.String("room", event.Room), zap.String("event", event.Type), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// publish sends a validated request to RabbitMQ, routed by its type.
+func (h *Handler) publish(c *gin.Context, req dto.CreateNotificationRequest) error {
+	message, err := h.queueMessage(req)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request.Context()
+	if message.MessageID != "" {
+		ctx = queue.WithMessageID(ctx, message.MessageID)
+	}
+	if err := h.pub.Publish(ctx, message.Payload, message.RoutingKey); err != nil {
+		h.log.Error("publish notification failed",
+			zap.String("room", req.Room),
+			zap.String("type", req.Type),
+			zap.String("title", req.Title),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// queueMessage builds the queue message of a validated request. The payload
+// shares the JSON shape of the create request and the dedup id becomes the
+// message id.
+func (h *Handler) queueMessage(req dto.CreateNotificationRequest) (queue.Message, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		h.log.Error("publish payload marshal failed", zap.Error(err))
+		return queue.Message{}, err
+	}
+
+	prefix := h.cfg.RabbitPublishPrefix
+	if prefix == "" {
+		prefix = "notification"
+	}
+	return queue.Message{Payload: payload, RoutingKey: prefix + "." + req.Type, MessageID: req.DedupID}, nil
+}
+
+func notificationFromRequest(req dto.CreateNotificationRequest) model.Notification {
+	return model.Notification{
+		Room:           req.Room,
+		Type:           req.Type,
+		Title:          req.Title,
+		Body:           req.Body,
+		Severity:       req.Severity,
+		Priority:       req.Priority,
+		Data:           req.Data,
+		Link:           req.Link,
+		Actions:        req.Actions,
+		Localizations:  req.Localizations,
+		TemplateKey:    req.TemplateKey,
+		TemplateParams: req.TemplateParams,
+		CollapseKey:    req.CollapseKey,
+		DedupID:        req.DedupID,
+	}
+}
+
+// validationMessage maps domain validation errors to client-facing messages.
+func (h *Handler) validationMessage(err error) (string, bool) {
+	var invalid *domain.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return invalid.Summary(), true
+	case errors.Is(err, domain.ErrInvalidNotificationType):
+		return "type must be one of: " + strings.Join(h.svc.TypeNames(), ", "), true
+	case errors.Is(err, domain.ErrTypeNotAllowedInRoom):
+		return "type is not allowed in this room", true
+	case errors.Is(err, domain.ErrRoomNotFound):
+		return "room is not registered", true
+	case errors.Is(err, domain.ErrInvalidSeverity):
+		return "severity must be one of: low, normal, high, critical", true
+	case errors.Is(err, domain.ErrInvalidPriority):
+		return "priority must be one of: low, normal, high", true
+	case errors.Is(err, domain.ErrInvalidData), errors.Is(err, domain.ErrInvalidLink), errors.Is(err, domain.ErrInvalidActions), errors.Is(err, domain.ErrInvalidCollapseKey):
+		return err.Error(), true
+	case errors.Is(err, domain.ErrBatchDedupUnsupported), errors.Is(err, domain.ErrNotificationRejected):
+		return err.Error(), true
+	case errors.Is(err, domain.ErrInvalidIdempotencyKey):
+		return "idempotency key must be 1-255 printable ASCII characters", true
+	case errors.Is(err, domain.ErrInvalidLocale):
+		return "localizations contain an invalid locale", true
+	case errors.Is(err, domain.ErrUnknownTemplate):
+		return "unknown template_key", true
+	default:
+		return "", false
+	}
+}
+
+// writeValidationError writes a 400 for a validation error, listing the
+// rejected fields when the error carries them, and reports whether it did.
+func (h *Handler) writeValidationError(c *gin.Context, err error) bool {
+	message, ok := h.validationMessage(err)
+	if !ok {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: message, Fields: fieldErrors(err)})
+	return true
+}
+
+// fieldErrors returns the per-field details of a *domain.ValidationError, or
+// nil for any other error.
+func fieldErrors(err error) []dto.FieldError {
+	var invalid *domain.ValidationError
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	fields := make([]dto.FieldError, len(invalid.Fields))
+	for i, field := range invalid.Fields {
+		fields[i] = dto.FieldError{Field: field.Field, Message: field.Message}
+	}
+	return fields
+}
+
+// requestedLocales returns the subscriber's preferred locales: an explicit
+// ?locale= query parameter wins over the Accept-Language header.
+func requestedLocales(c *gin.Context) []string {
+	if locale := i18n.Normalize(c.Query("locale")); locale != "" {
+		return []string{locale}
+	}
+	return i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
 func writeNotification(w http.ResponseWriter, notification model.Notification) error {
 	payload, err := json.Marshal(notification)
 	if err != nil {
@@ -196,3 +478,14 @@ func writeNotification(w http.ResponseWriter, notification model.Notification) e
 	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, payload)
 	return err
 }
+
+// writeEvent writes a non-notification frame. These frames carry no id so
+// they do not move the client's Last-Event-ID.
+func writeEvent(w http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
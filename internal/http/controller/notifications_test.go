@@ -7,7 +7,9 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
@@ -22,6 +24,7 @@ import (
 	"sse_demo/internal/repository"
 	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
 )
 
 type repoMock struct {
@@ -33,11 +36,36 @@ func (m *repoMock) CreateNotification(ctx context.Context, n model.Notification)
 	return args.Get(0).(model.Notification), args.Error(1)
 }
 
-func (m *repoMock) ListNotifications(ctx context.Context, room string, limit int) ([]model.Notification, error) {
-	args := m.Called(ctx, room, limit)
+func (m *repoMock) CreateNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	args := m.Called(ctx, notifications)
 	return args.Get(0).([]model.Notification), args.Error(1)
 }
 
+func (m *repoMock) GetNotification(ctx context.Context, id int64) (model.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) UpdateNotification(ctx context.Context, n model.Notification) (model.Notification, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) DeleteNotification(ctx context.Context, id int64, deletedAt time.Time) (model.Notification, error) {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) ListNotifications(ctx context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
+	args := m.Called(ctx, room, opts)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *repoMock) SearchNotifications(ctx context.Context, query repository.SearchQuery) ([]model.SearchHit, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.SearchHit), args.Error(1)
+}
+
 type publisherMock struct {
 	mock.Mock
 }
@@ -47,21 +75,82 @@ func (m *publisherMock) Publish(ctx context.Context, payload []byte, routingKey
 	return args.Error(0)
 }
 
+func (m *publisherMock) PublishBatch(ctx context.Context, messages []queue.Message) (int, error) {
+	args := m.Called(ctx, messages)
+	return args.Int(0), args.Error(1)
+}
+
 func setupRouter(t *testing.T, repo repository.NotificationRepository, publisher queue.Publisher) *gin.Engine {
 	t.Helper()
-	gin.SetMode(gin.TestMode)
-
-	cfg := &config.Config{
+	return setupRouterWithConfig(t, &config.Config{
 		RabbitPublishPrefix: "notification",
 		HistoryLimit:        10,
-	}
+		BatchMaxSize:        3,
+	}, repo, publisher)
+}
+
+func setupRouterWithConfig(t *testing.T, cfg *config.Config, repo repository.NotificationRepository, publisher queue.Publisher) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
 	hub := sse.NewHub()
-	svc := notify.NewService(repo, hub, zap.NewNop())
-	handler := NewHandler(cfg, svc, hub, zap.NewNop(), publisher)
+	types, err := notify.NewTypeService(cfg, memory.New(zap.NewNop()), zap.NewNop())
+	require.NoError(t, err)
+	rooms := memory.New(zap.NewNop())
+	maintenance := notify.NewMaintenanceService(cfg, rooms, hub, zap.NewNop())
+	escalations, err := notify.NewEscalationService(cfg, repo, rooms, hub, zap.NewNop())
+	require.NoError(t, err)
+	svc := notify.NewService(cfg, repo, memory.New(zap.NewNop()), rooms, hub, notify.NewTypeRegistry(types), nil, nil, maintenance, escalations, nil, zap.NewNop())
+	actions := notify.NewActionService(cfg, repo, memory.New(zap.NewNop()), publisher, hub, zap.NewNop())
+	// Mocked repositories do not support export; give those a separate store.
+	transferStore, ok := repo.(repository.TransferRepository)
+	if !ok {
+		transferStore = memory.New(zap.NewNop())
+	}
+	transfer := notify.NewTransferService(cfg, transferStore, rooms, notify.NewTypeRegistry(types), zap.NewNop())
+	// Pins are listed together with the notifications they point to.
+	pinStore, ok := repo.(repository.PinRepository)
+	if !ok {
+		pinStore = memory.New(zap.NewNop())
+	}
+	handler := NewHandler(cfg, svc, types, actions, transfer, notify.NewRoomService(rooms, zap.NewNop()), notify.NewPreferenceService(rooms, hub, zap.NewNop()), maintenance, notify.NewPinService(cfg, repo, pinStore, hub, zap.NewNop()), notify.NewStateService(rooms, hub, zap.NewNop()), notify.NewAnnouncementService(rooms, hub, zap.NewNop()), notify.NewDeliveryTracker(repo, rooms, hub, zap.NewNop()), escalations, hub, zap.NewNop(), publisher)
 
 	router := gin.New()
 	router.POST("/notifications", handler.CreateNotification)
+	router.GET("/notifications/search", handler.SearchNotifications)
 	router.POST("/notifications/publish", handler.PublishNotification)
+	router.POST("/notifications/batch", handler.CreateNotificationBatch)
+	router.POST("/notifications/publish/batch", handler.PublishNotificationBatch)
+	router.PATCH("/notifications/:id", handler.UpdateNotification)
+	router.DELETE("/notifications/:id", handler.DeleteNotification)
+	router.POST("/notifications/:id/pin", handler.PinNotification)
+	router.DELETE("/notifications/:id/pin", handler.UnpinNotification)
+	router.POST("/notifications/:id/actions/:action", handler.InvokeAction)
+	router.GET("/sse/:room", handler.SSE)
+	router.GET("/rooms", handler.ListRooms)
+	router.POST("/rooms", handler.CreateRoom)
+	router.GET("/rooms/:room", handler.GetRoom)
+	router.PATCH("/rooms/:room", handler.UpdateRoom)
+	router.DELETE("/rooms/:room", handler.DeleteRoom)
+	router.GET("/rooms/:room/notifications", handler.ListRoomNotifications)
+	router.GET("/rooms/:room/state", handler.GetRoomState)
+	router.PUT("/rooms/:room/state/:key", handler.PutRoomState)
+	router.DELETE("/rooms/:room/state/:key", handler.DeleteRoomState)
+	router.GET("/notifications/export", handler.ExportNotifications)
+	router.POST("/notifications/import", handler.ImportNotifications)
+	router.GET("/notification-types", handler.ListNotificationTypes)
+	router.GET("/users/:id/preferences", handler.GetUserPreferences)
+	router.PUT("/users/:id/preferences", handler.PutUserPreferences)
+	router.POST("/maintenance-windows", handler.CreateMaintenanceWindow)
+	router.GET("/maintenance-windows", handler.ListMaintenanceWindows)
+	router.DELETE("/maintenance-windows/:id", handler.DeleteMaintenanceWindow)
+	router.GET("/notifications/:id/delivery", handler.GetNotificationDelivery)
+	router.POST("/notifications/:id/ack", handler.AckNotification)
+	router.POST("/announcements", handler.CreateAnnouncement)
+	router.GET("/announcements", handler.ListAnnouncements)
+	router.DELETE("/announcements/:id", handler.DeleteAnnouncement)
+	router.PUT("/notification-types/:name", handler.UpsertNotificationType)
+	router.DELETE("/notification-types/:name", handler.DeleteNotificationType)
 	return router
 }
 
@@ -111,6 +200,69 @@ func TestCreateNotificationController(t *testing.T) {
 		repo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
 	})
 
+	t.Run("field errors", func(t *testing.T) {
+		repo := &repoMock{}
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+			"room":     "room 1",
+			"type":     domain.NotificationTypeInfo,
+			"title":    strings.Repeat("t", domain.MaxTitleLength+1),
+			"body":     "body",
+			"severity": "urgent",
+		})
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var respBody dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, []dto.FieldError{
+			{Field: "room", Message: "must be 1-255 letters, digits or . _ : @ - and start with a letter or digit"},
+			{Field: "title", Message: "must not exceed 255 characters"},
+			{Field: "severity", Message: "must be one of: low, normal, high, critical"},
+		}, respBody.Fields)
+		require.Contains(t, respBody.Message, "title must not exceed 255 characters")
+		repo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid link", func(t *testing.T) {
+		repo := &repoMock{}
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]any{
+			"room":  "room-1",
+			"type":  domain.NotificationTypeInfo,
+			"title": "title",
+			"body":  "body",
+			"link":  "javascript:alert(1)",
+		})
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		repo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("data, link and actions", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n model.Notification) bool {
+			return string(n.Data) == `{"invoice_id":1234}` &&
+				n.Link == "https://example.com/invoices/1234" &&
+				len(n.Actions) == 1 && n.Actions[0].ID == "pay"
+		})).Return(model.Notification{ID: 100, Room: "room-1", Type: domain.NotificationTypeInfo}, nil).Once()
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]any{
+			"room":    "room-1",
+			"type":    domain.NotificationTypeInfo,
+			"title":   "title",
+			"body":    "body",
+			"data":    map[string]int{"invoice_id": 1234},
+			"link":    "https://example.com/invoices/1234",
+			"actions": []map[string]string{{"id": "pay", "label": "Pay now"}},
+		})
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		repo.AssertExpectations(t)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		repo := &repoMock{}
 		repo.On("CreateNotification", mock.Anything, mock.Anything).Return(model.Notification{
@@ -136,6 +288,37 @@ func TestCreateNotificationController(t *testing.T) {
 		require.Equal(t, domain.NotificationTypeInfo, respBody.Type)
 		repo.AssertExpectations(t)
 	})
+
+	t.Run("idempotency key replays original", func(t *testing.T) {
+		router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+		payload, err := json.Marshal(map[string]string{
+			"room":  "room-1",
+			"type":  domain.NotificationTypeInfo,
+			"title": "title",
+			"body":  "body",
+		})
+		require.NoError(t, err)
+
+		send := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/notifications", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Idempotency-Key", "req-1")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			return rec
+		}
+
+		first := send()
+		require.Equal(t, http.StatusCreated, first.Code)
+		second := send()
+		require.Equal(t, http.StatusOK, second.Code)
+		require.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+
+		var created, replayed model.Notification
+		require.NoError(t, json.Unmarshal(first.Body.Bytes(), &created))
+		require.NoError(t, json.Unmarshal(second.Body.Bytes(), &replayed))
+		require.Equal(t, created.ID, replayed.ID)
+	})
 }
 
 func TestPublishNotificationController(t *testing.T) {
@@ -186,3 +369,98 @@ func TestPublishNotificationController(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 }
+
+func TestNotificationTypesController(t *testing.T) {
+	t.Run("register type then create", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n model.Notification) bool {
+			return n.Type == "success" && n.Severity == domain.SeverityLow && n.ExpiresAt != nil
+		})).Return(model.Notification{ID: 7, Room: "room-1", Type: "success"}, nil).Once()
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPut, "/notification-types/success", map[string]any{
+			"default_severity":    domain.SeverityLow,
+			"default_ttl_seconds": 3600,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+			"room":  "room-1",
+			"type":  "success",
+			"title": "title",
+			"body":  "body",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid type lists registered types", func(t *testing.T) {
+		router := setupRouter(t, &repoMock{}, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPut, "/notification-types/security", map[string]any{})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+			"room":  "room-1",
+			"type":  "bad",
+			"title": "title",
+			"body":  "body",
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		var respBody dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &respBody))
+		require.Equal(t, "type must be one of: info, security, system, warning", respBody.Message)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		router := setupRouter(t, &repoMock{}, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodDelete, "/notification-types/info", nil)
+		require.Equal(t, http.StatusConflict, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodDelete, "/notification-types/missing", nil)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodPut, "/notification-types/success", map[string]any{})
+		require.Equal(t, http.StatusOK, rec.Code)
+		rec = performJSONRequest(t, router, http.MethodDelete, "/notification-types/success", nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = performJSONRequest(t, router, http.MethodGet, "/notification-types", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var types []model.NotificationType
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &types))
+		require.Len(t, types, 3)
+	})
+}
+
+func TestInvokeActionController(t *testing.T) {
+	t.Run("user required", func(t *testing.T) {
+		repo := &repoMock{}
+		router := setupRouter(t, repo, &publisherMock{})
+
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications/1/actions/ack", nil)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		repo.AssertNotCalled(t, "GetNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("GetNotification", mock.Anything, int64(1)).Return(model.Notification{
+			ID:      1,
+			Room:    "room-1",
+			Type:    domain.NotificationTypeInfo,
+			Actions: []model.Action{{ID: "ack", Label: "Acknowledge"}},
+		}, nil).Once()
+		router := setupRouter(t, repo, &publisherMock{})
+
+		req := httptest.NewRequest(http.MethodPost, "/notifications/1/actions/delete", nil)
+		req.Header.Set("X-User-ID", "user-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		repo.AssertExpectations(t)
+	})
+}
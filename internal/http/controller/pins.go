@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+// PinNotification pins a notification so SSE sends it ahead of the regular
+// history on every connect.
+func (h *Handler) PinNotification(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	pinned, err := h.pins.Pin(c.Request.Context(), id, userIDFromRequest(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotificationNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+		case errors.Is(err, domain.ErrPinLimitReached):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: resp.CodeConflict, Message: "room has reached its pin limit"})
+		case errors.Is(err, domain.ErrNotificationNotLive):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: resp.CodeConflict, Message: "superseded or expired notifications cannot be pinned"})
+		default:
+			h.log.Error("pin notification failed", zap.Int64("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to pin notification"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, pinned)
+}
+
+func (h *Handler) UnpinNotification(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid notification id"})
+		return
+	}
+	if err := h.pins.Unpin(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotificationNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification not found"})
+		case errors.Is(err, domain.ErrNotificationNotPinned):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "notification is not pinned"})
+		default:
+			h.log.Error("unpin notification failed", zap.Int64("id", id), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to unpin notification"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestPinsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/notifications", map[string]string{
+		"room": "room-1", "type": domain.NotificationTypeInfo, "title": "title", "body": "body",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created model.Notification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/notifications/" + strconv.FormatInt(created.ID, 10) + "/pin"
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var pinned model.Notification
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pinned))
+	require.True(t, pinned.Pinned)
+	require.NotNil(t, pinned.PinnedAt)
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodPost, "/notifications/abc/pin", http.StatusBadRequest},
+		{http.MethodPost, "/notifications/999/pin", http.StatusNotFound},
+		{http.MethodDelete, path, http.StatusNoContent},
+		{http.MethodDelete, path, http.StatusNotFound},
+	} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		require.Equal(t, tc.code, rec.Code, "%s %s", tc.method, tc.path)
+	}
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+)
+
+func (h *Handler) GetUserPreferences(c *gin.Context) {
+	userID := c.Param("id")
+	if !requireUser(c, userID) {
+		return
+	}
+	prefs, err := h.prefs.Get(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("get preferences failed", zap.String("user_id", userID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to get preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+func (h *Handler) PutUserPreferences(c *gin.Context) {
+	userID := c.Param("id")
+	if !requireUser(c, userID) {
+		return
+	}
+	var req dto.PutPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	prefs, err := h.prefs.Put(c.Request.Context(), model.UserPreferences{
+		UserID:      userID,
+		MutedRooms:  req.MutedRooms,
+		OptOutTypes: req.OptOutTypes,
+		QuietHours:  req.QuietHours,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPreferences) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+			return
+		}
+		h.log.Error("put preferences failed", zap.String("user_id", userID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to save preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
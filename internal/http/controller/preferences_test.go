@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func performUserRequest(t *testing.T, router *gin.Engine, method, path, userID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPreferencesController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	// Only the user named in the path may read or change the preferences.
+	rec := performUserRequest(t, router, http.MethodGet, "/users/alice/preferences", "mallory", nil)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	rec = performUserRequest(t, router, http.MethodGet, "/users/alice/preferences?user_id=alice", "", nil)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	rec = performUserRequest(t, router, http.MethodPut, "/users/alice/preferences", "mallory", map[string]any{"opt_out_types": []string{domain.NotificationTypeInfo}})
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = performUserRequest(t, router, http.MethodGet, "/users/alice/preferences", "alice", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"user_id":"alice","muted_rooms":[],"opt_out_types":[],"updated_at":"0001-01-01T00:00:00Z"}`, rec.Body.String())
+
+	rec = performUserRequest(t, router, http.MethodPut, "/users/alice/preferences", "alice", map[string]any{
+		"muted_rooms":   []map[string]any{{"room": "room-2"}, {"room": "room-3", "until": "2000-01-01T00:00:00Z"}},
+		"opt_out_types": []string{domain.NotificationTypeWarning},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var prefs model.UserPreferences
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &prefs))
+	require.Equal(t, []model.RoomMute{{Room: "room-2"}}, prefs.MutedRooms)
+
+	rec = performUserRequest(t, router, http.MethodPut, "/users/alice/preferences", "alice", map[string]any{"opt_out_types": []string{"Bad Type"}})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performUserRequest(t, router, http.MethodPut, "/users/carol/preferences", "carol", map[string]any{
+		"quiet_hours": map[string]string{"start": "22:00", "end": "07:00", "timezone": "Mars/Olympus"},
+	})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performUserRequest(t, router, http.MethodPut, "/users/carol/preferences", "carol", map[string]any{
+		"quiet_hours": map[string]string{"start": "22:00", "end": "07:00", "timezone": "Europe/Berlin", "mode": domain.SuppressionDefer},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	prefs = model.UserPreferences{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &prefs))
+	require.Equal(t, &model.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Berlin", Mode: domain.SuppressionDefer}, prefs.QuietHours)
+
+	for _, n := range []map[string]string{
+		{"room": "room-1", "type": domain.NotificationTypeInfo, "title": "kept", "body": "body"},
+		{"room": "room-1", "type": domain.NotificationTypeWarning, "title": "opted out", "body": "body"},
+		{"room": "room-2", "type": domain.NotificationTypeInfo, "title": "muted", "body": "body"},
+	} {
+		rec = performJSONRequest(t, router, http.MethodPost, "/notifications", n)
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+	history := func(room, userID string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/rooms/"+room+"/notifications", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page dto.HistoryResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		var titles []string
+		for _, n := range page.Notifications {
+			titles = append(titles, n.Title)
+		}
+		return titles
+	}
+	require.Equal(t, []string{"kept"}, history("room-1", "alice"))
+	require.Empty(t, history("room-2", "alice"))
+	require.Equal(t, []string{"opted out", "kept"}, history("room-1", "bob"))
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/model"
+	"sse_demo/internal/service/notify"
+)
+
+// CreateRoom registers a room. The owner defaults to the calling user.
+func (h *Handler) CreateRoom(c *gin.Context) {
+	var req dto.CreateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	if req.Owner == "" {
+		req.Owner = authenticatedUserID(c)
+	}
+	room, err := h.rooms.Create(c.Request.Context(), model.Room{
+		Name:              req.Name,
+		DisplayName:       req.DisplayName,
+		Description:       req.Description,
+		Owner:             req.Owner,
+		Visibility:        req.Visibility,
+		HistoryLimit:      req.HistoryLimit,
+		RetentionDays:     req.RetentionDays,
+		RetentionMaxCount: req.RetentionMaxCount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRoom):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+		case errors.Is(err, domain.ErrRoomExists):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: resp.CodeConflict, Message: "room already exists"})
+		default:
+			h.log.Error("create room failed", zap.String("name", req.Name), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to create room"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, room)
+}
+
+// ListRooms lists the public rooms and the private rooms owned by the caller.
+func (h *Handler) ListRooms(c *gin.Context) {
+	rooms, err := h.rooms.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to list rooms"})
+		return
+	}
+	userID := authenticatedUserID(c)
+	visible := make([]model.Room, 0, len(rooms))
+	for _, room := range rooms {
+		if roomVisibleTo(room, userID) {
+			visible = append(visible, room)
+		}
+	}
+	c.JSON(http.StatusOK, visible)
+}
+
+// GetRoom returns a room. Private rooms are reported as missing to everyone
+// but their owner.
+func (h *Handler) GetRoom(c *gin.Context) {
+	room, err := h.rooms.Get(c.Request.Context(), c.Param("room"))
+	if err == nil && !roomVisibleTo(room, authenticatedUserID(c)) {
+		err = domain.ErrRoomNotFound
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrRoomNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "room not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to get room"})
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+func (h *Handler) UpdateRoom(c *gin.Context) {
+	var req dto.UpdateRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	name := c.Param("room")
+	room, err := h.rooms.Update(c.Request.Context(), name, notify.RoomPatch{
+		DisplayName:       req.DisplayName,
+		Description:       req.Description,
+		Owner:             req.Owner,
+		Visibility:        req.Visibility,
+		HistoryLimit:      req.HistoryLimit,
+		RetentionDays:     req.RetentionDays,
+		RetentionMaxCount: req.RetentionMaxCount,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRoomNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "room not found"})
+		case errors.Is(err, domain.ErrInvalidRoom):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+		default:
+			h.log.Error("update room failed", zap.String("name", name), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to update room"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, room)
+}
+
+func (h *Handler) DeleteRoom(c *gin.Context) {
+	name := c.Param("room")
+	if err := h.rooms.Delete(c.Request.Context(), name); err != nil {
+		if errors.Is(err, domain.ErrRoomNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "room not found"})
+			return
+		}
+		h.log.Error("delete room failed", zap.String("name", name), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete room"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// roomVisibleTo reports whether userID, taken from X-User-ID, may see room in
+// the room listings. Visibility does not restrict the notifications of a
+// room; see domain.VisibilityPrivate.
+func roomVisibleTo(room model.Room, userID string) bool {
+	return room.Visibility != domain.VisibilityPrivate || (userID != "" && room.Owner == userID)
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestRoomsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/rooms", map[string]any{
+		"name":          "ops",
+		"display_name":  "Operations",
+		"history_limit": 5,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var room model.Room
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &room))
+	require.Equal(t, domain.VisibilityPublic, room.Visibility)
+	require.Equal(t, 5, room.HistoryLimit)
+
+	rec = performJSONRequest(t, router, http.MethodPost, "/rooms", map[string]any{"name": "ops"})
+	require.Equal(t, http.StatusConflict, rec.Code)
+	rec = performJSONRequest(t, router, http.MethodPost, "/rooms", map[string]any{"name": "ops/*"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performJSONRequest(t, router, http.MethodPost, "/rooms", map[string]any{"name": "secret", "owner": "alice", "visibility": domain.VisibilityPrivate})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	listRooms := func(userID string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/rooms", nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var rooms []model.Room
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rooms))
+		var names []string
+		for _, room := range rooms {
+			names = append(names, room.Name)
+		}
+		return names
+	}
+	require.Equal(t, []string{"ops"}, listRooms("bob"))
+	require.Equal(t, []string{"ops", "secret"}, listRooms("alice"))
+
+	// ?user_id= can be set by anyone, so it does not reveal private rooms.
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms?user_id=alice", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "secret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms/secret?user_id=alice", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = performJSONRequest(t, router, http.MethodPatch, "/rooms/ops", map[string]any{"description": "On-call alerts", "history_limit": 0})
+	require.Equal(t, http.StatusOK, rec.Code)
+	room = model.Room{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &room))
+	require.Equal(t, "Operations", room.DisplayName)
+	require.Equal(t, "On-call alerts", room.Description)
+	require.Zero(t, room.HistoryLimit)
+
+	rec = performJSONRequest(t, router, http.MethodPatch, "/rooms/ops", map[string]any{"visibility": "hidden"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/rooms/ops", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms/ops", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStrictRooms(t *testing.T) {
+	router := setupRouterWithConfig(t, &config.Config{RoomsStrict: true}, memory.New(zap.NewNop()), &publisherMock{})
+	notification := map[string]string{
+		"room":  "ops",
+		"type":  domain.NotificationTypeInfo,
+		"title": "title",
+		"body":  "body",
+	}
+
+	rec := performJSONRequest(t, router, http.MethodPost, "/notifications", notification)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sse/ops", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = performJSONRequest(t, router, http.MethodPost, "/rooms", map[string]any{"name": "ops"})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = performJSONRequest(t, router, http.MethodPost, "/notifications", notification)
+	require.Equal(t, http.StatusCreated, rec.Code)
+}
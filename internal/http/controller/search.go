@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/repository"
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// SearchNotifications runs a full-text search over notification titles and
+// bodies across rooms. Pages are requested with ?offset=.
+func (h *Handler) SearchNotifications(c *gin.Context) {
+	query := repository.SearchQuery{
+		Text:  c.Query("q"),
+		Room:  c.Query("room"),
+		Type:  c.Query("type"),
+		Limit: defaultSearchPageSize,
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchPageSize {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: fmt.Sprintf("limit must be between 1 and %d", maxSearchPageSize)})
+			return
+		}
+		query.Limit = n
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "offset must be a non-negative integer"})
+			return
+		}
+		query.Offset = n
+	}
+
+	page, err := h.svc.Search(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSearchQuery) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "q must contain at least one word"})
+			return
+		}
+		h.log.Error("search notifications failed", zap.String("room", query.Room), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to search notifications"})
+		return
+	}
+
+	response := dto.SearchResponse{
+		Results:    make([]dto.SearchResultResponse, len(page.Results)),
+		NextOffset: page.NextOffset,
+	}
+	for i, result := range page.Results {
+		response.Results[i] = dto.SearchResultResponse{
+			Notification: result.Notification,
+			Score:        result.Score,
+			Highlights:   dto.SearchHighlights{Title: result.TitleHighlight, Body: result.BodyHighlight},
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/store/memory"
+)
+
+func TestSearchNotificationsController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+	for _, n := range []map[string]string{
+		{"room": "billing", "type": domain.NotificationTypeWarning, "title": "Invoice 1234 overdue", "body": "Invoice 1234 for ACME is overdue."},
+		{"room": "billing", "type": domain.NotificationTypeInfo, "title": "Invoice 1235 paid", "body": "Thanks."},
+		{"room": "ops", "type": domain.NotificationTypeInfo, "title": "Deploy finished", "body": "Mentions invoice 1234 in passing."},
+		{"room": "ops", "type": domain.NotificationTypeInfo, "title": "Unrelated", "body": "Nothing to see."},
+	} {
+		rec := performJSONRequest(t, router, http.MethodPost, "/notifications", n)
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	search := func(query string) dto.SearchResponse {
+		t.Helper()
+		rec := performJSONRequest(t, router, http.MethodGet, "/notifications/search"+query, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page dto.SearchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("ranks and highlights", func(t *testing.T) {
+		page := search("?q=invoice+1234")
+		require.Len(t, page.Results, 3)
+		require.Equal(t, "Invoice 1234 overdue", page.Results[0].Notification.Title)
+		require.Equal(t, "<mark>Invoice</mark> <mark>1234</mark> overdue", page.Results[0].Highlights.Title)
+		require.Greater(t, page.Results[0].Score, page.Results[2].Score)
+		require.Zero(t, page.NextOffset)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		page := search("?q=invoice&room=ops")
+		require.Len(t, page.Results, 1)
+		require.Equal(t, "Deploy finished", page.Results[0].Notification.Title)
+
+		page = search("?q=invoice&type=" + domain.NotificationTypeInfo + "&room=billing")
+		require.Len(t, page.Results, 1)
+		require.Equal(t, "Invoice 1235 paid", page.Results[0].Notification.Title)
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		page := search("?q=invoice&limit=2")
+		require.Len(t, page.Results, 2)
+		require.Equal(t, 2, page.NextOffset)
+
+		page = search("?q=invoice&limit=2&offset=2")
+		require.Len(t, page.Results, 1)
+		require.Zero(t, page.NextOffset)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"", "?q=%21%21", "?q=invoice&limit=0", "?q=invoice&offset=-1"} {
+			rec := performJSONRequest(t, router, http.MethodGet, "/notifications/search"+query, nil)
+			require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+}
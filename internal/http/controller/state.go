@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+)
+
+// GetRoomState returns the current state of a room in the shape of an SSE
+// state snapshot.
+func (h *Handler) GetRoomState(c *gin.Context) {
+	room := c.Param("room")
+	snapshot, err := h.state.Snapshot(c.Request.Context(), room)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to get room state"})
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+func (h *Handler) PutRoomState(c *gin.Context) {
+	var req dto.PutRoomStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "invalid json"})
+		return
+	}
+	room, key := c.Param("room"), c.Param("key")
+	if err := h.svc.CheckRoom(c.Request.Context(), room); err != nil {
+		if message, ok := h.validationMessage(err); ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to set room state"})
+		return
+	}
+	state, err := h.state.Put(c.Request.Context(), room, key, req.Value)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRoomState) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: err.Error()})
+			return
+		}
+		h.log.Error("put room state failed", zap.String("room", room), zap.String("key", key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to set room state"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+func (h *Handler) DeleteRoomState(c *gin.Context) {
+	room, key := c.Param("room"), c.Param("key")
+	if err := h.state.Delete(c.Request.Context(), room, key); err != nil {
+		if errors.Is(err, domain.ErrRoomStateNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: resp.CodeNotFound, Message: "room state key not found"})
+			return
+		}
+		h.log.Error("delete room state failed", zap.String("room", room), zap.String("key", key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Code: resp.CodeInternalError, Message: "failed to delete room state"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/service/notify"
+	"sse_demo/internal/store/memory"
+)
+
+func TestRoomStateController(t *testing.T) {
+	router := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+
+	for _, tc := range []struct {
+		path string
+		body any
+		code int
+	}{
+		{"/rooms/ci/state/build_status", map[string]any{"value": "green"}, http.StatusOK},
+		{"/rooms/ci/state/build_status", map[string]any{"value": nil}, http.StatusBadRequest},
+		{"/rooms/ci/state/build_status", map[string]any{}, http.StatusBadRequest},
+		{"/rooms/ci/state/-bad", map[string]any{"value": 1}, http.StatusBadRequest},
+	} {
+		rec := performJSONRequest(t, router, http.MethodPut, tc.path, tc.body)
+		require.Equal(t, tc.code, rec.Code, "%s %v", tc.path, tc.body)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rooms/ci/state", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var snapshot notify.StateEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	require.JSONEq(t, `"green"`, string(snapshot.State["build_status"].Value))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/rooms/ci/state/build_status", nil))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/rooms/ci/state/build_status", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	strict := setupRouterWithConfig(t, &config.Config{RoomsStrict: true}, memory.New(zap.NewNop()), &publisherMock{})
+	rec = performJSONRequest(t, strict, http.MethodPut, "/rooms/ci/state/build_status", map[string]any{"value": "green"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/http/resp"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/service/notify"
+)
+
+// ExportNotifications streams stored notifications as JSON Lines, optionally
+// limited to ?room= and a created_at range given by ?since= and ?until=.
+func (h *Handler) ExportNotifications(c *gin.Context) {
+	filter := repository.ExportFilter{Room: c.Query("room")}
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if v := c.Query(bound.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: bound.name + " must be an RFC 3339 timestamp"})
+				return
+			}
+			t = t.UTC()
+			*bound.target = &t
+		}
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="notifications.jsonl"`)
+	c.Status(http.StatusOK)
+	written, err := h.transfer.Export(c.Request.Context(), c.Writer, filter)
+	if err != nil {
+		// The status line is already sent; the client sees a short dump.
+		h.log.Error("export notifications failed", zap.Int("written", written), zap.Error(err))
+	}
+}
+
+// ImportNotifications stores a JSON Lines dump from the request body. The
+// ?ids= query parameter selects remap (default) or preserve.
+func (h *Handler) ImportNotifications(c *gin.Context) {
+	mode, err := notify.ParseIDMode(c.Query("ids"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: resp.CodeBadRequest, Message: "ids must be one of: remap, preserve"})
+		return
+	}
+
+	imported, err := h.transfer.Import(c.Request.Context(), c.Request.Body, mode)
+	if err != nil {
+		var importErr *notify.ImportError
+		if errors.As(err, &importErr) {
+			c.JSON(http.StatusBadRequest, dto.ImportResponse{Imported: imported, Line: importErr.Line, Error: importErr.Err.Error()})
+			return
+		}
+		h.log.Error("import notifications failed", zap.Int("imported", imported), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.ImportResponse{Imported: imported, Error: "failed to import notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.ImportResponse{Imported: imported})
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/http/dto"
+	"sse_demo/internal/store/memory"
+)
+
+func TestTransferController(t *testing.T) {
+	source := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+	for _, room := range []string{"room-1", "room-2", "room-1"} {
+		rec := performJSONRequest(t, source, http.MethodPost, "/notifications", map[string]string{
+			"room":  room,
+			"type":  domain.NotificationTypeInfo,
+			"title": "title",
+			"body":  "body",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications/export?room=room-1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	dump := rec.Body.Bytes()
+	require.Equal(t, 2, strings.Count(string(dump), "\n"))
+
+	target := setupRouter(t, memory.New(zap.NewNop()), &publisherMock{})
+	rec = httptest.NewRecorder()
+	target.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/import?ids=preserve", bytes.NewReader(dump)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result dto.ImportResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Equal(t, 2, result.Imported)
+
+	rec = httptest.NewRecorder()
+	target.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/import", strings.NewReader("not json\n")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Equal(t, 1, result.Line)
+
+	rec = httptest.NewRecorder()
+	target.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications/export?since=yesterday", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
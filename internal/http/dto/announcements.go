@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// CreateAnnouncementRequest creates an announcement for every connected
+// client. An omitted expires_at keeps it active for an hour.
+type CreateAnnouncementRequest struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Severity  string     `json:"severity"`
+	Link      string     `json:"link"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "sse_demo/internal/model"
+
+// BatchItemResponse reports the outcome of one item of a batch request.
+// Status is one of created, queued, invalid, failed or skipped.
+type BatchItemResponse struct {
+	Index        int                 `json:"index"`
+	Status       string              `json:"status"`
+	Notification *model.Notification `json:"notification,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	Fields       []FieldError        `json:"fields,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode      string              `json:"mode"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []BatchItemResponse `json:"items"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "sse_demo/internal/model"
+
+// HistoryResponse is one page of room history, newest first. NextBefore,
+// NextAfter or NextAfterSeq holds the cursor for the following page in the
+// direction being paged; all are omitted on the last page.
+type HistoryResponse struct {
+	Notifications []model.Notification `json:"notifications"`
+	NextBefore    int64                `json:"next_before,omitempty"`
+	NextAfter     int64                `json:"next_after,omitempty"`
+	NextAfterSeq  int64                `json:"next_after_seq,omitempty"`
+}
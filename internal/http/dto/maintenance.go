@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// CreateMaintenanceWindowRequest creates a maintenance window. Room is a glob;
+// an empty type covers all types and an omitted starts_at starts the window
+// now.
+type CreateMaintenanceWindowRequest struct {
+	Room     string     `json:"room"`
+	Type     string     `json:"type"`
+	Mode     string     `json:"mode"`
+	Reason   string     `json:"reason"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type UpsertNotificationTypeRequest struct {
+	DefaultSeverity   string   `json:"default_severity"`
+	DefaultTTLSeconds int      `json:"default_ttl_seconds"`
+	AllowedRooms      []string `json:"allowed_rooms"`
+}
@@ -1,8 +1,36 @@
 package dto
 
+import (
+	"encoding/json"
+
+	"sse_demo/internal/model"
+)
+
 type CreateNotificationRequest struct {
-	Room  string `json:"room"`
-	Type  string `json:"type"`
-	Title string `json:"title"`
-	Body  string `json:"body"`
+	Room           string                            `json:"room"`
+	Type           string                            `json:"type"`
+	Title          string                            `json:"title"`
+	Body           string                            `json:"body"`
+	Severity       string                            `json:"severity,omitempty"`
+	Priority       string                            `json:"priority,omitempty"`
+	Data           json.RawMessage                   `json:"data,omitempty"`
+	Link           string                            `json:"link,omitempty"`
+	Actions        []model.Action                    `json:"actions,omitempty"`
+	Localizations  map[string]model.LocalizedContent `json:"localizations,omitempty"`
+	TemplateKey    string                            `json:"template_key,omitempty"`
+	TemplateParams map[string]string                 `json:"template_params,omitempty"`
+	CollapseKey    string                            `json:"collapse_key,omitempty"`
+	DedupID        string                            `json:"dedup_id,omitempty"`
+}
+
+// UpdateNotificationRequest is a partial update; omitted fields are unchanged.
+type UpdateNotificationRequest struct {
+	Title         *string                           `json:"title"`
+	Body          *string                           `json:"body"`
+	Severity      *string                           `json:"severity"`
+	Priority      *string                           `json:"priority"`
+	Data          json.RawMessage                   `json:"data"`
+	Link          *string                           `json:"link"`
+	Actions       *[]model.Action                   `json:"actions"`
+	Localizations map[string]model.LocalizedContent `json:"localizations"`
 }
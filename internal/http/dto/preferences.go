@@ -0,0 +1,10 @@
+package dto
+
+import "sse_demo/internal/model"
+
+// PutPreferencesRequest replaces all preferences of a user.
+type PutPreferencesRequest struct {
+	MutedRooms  []model.RoomMute  `json:"muted_rooms"`
+	OptOutTypes []string          `json:"opt_out_types"`
+	QuietHours  *model.QuietHours `json:"quiet_hours"`
+}
@@ -1,7 +1,14 @@
 package dto
 
 type ErrorResponse struct {
-	Code    string `json:"code"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError names one rejected request field and why it was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
@@ -0,0 +1,23 @@
+package dto
+
+type CreateRoomRequest struct {
+	Name              string `json:"name"`
+	DisplayName       string `json:"display_name"`
+	Description       string `json:"description"`
+	Owner             string `json:"owner"`
+	Visibility        string `json:"visibility"`
+	HistoryLimit      int    `json:"history_limit"`
+	RetentionDays     int    `json:"retention_days"`
+	RetentionMaxCount int    `json:"retention_max_count"`
+}
+
+// UpdateRoomRequest is a partial update; omitted fields are unchanged.
+type UpdateRoomRequest struct {
+	DisplayName       *string `json:"display_name"`
+	Description       *string `json:"description"`
+	Owner             *string `json:"owner"`
+	Visibility        *string `json:"visibility"`
+	HistoryLimit      *int    `json:"history_limit"`
+	RetentionDays     *int    `json:"retention_days"`
+	RetentionMaxCount *int    `json:"retention_max_count"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "sse_demo/internal/model"
+
+// SearchHighlights hold HTML-escaped text with matches wrapped in <mark>.
+type SearchHighlights struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type SearchResultResponse struct {
+	Notification model.Notification `json:"notification"`
+	Score        float64            `json:"score"`
+	Highlights   SearchHighlights   `json:"highlights"`
+}
+
+// SearchResponse is one page of search results; NextOffset is omitted on the
+// last page.
+type SearchResponse struct {
+	Results    []SearchResultResponse `json:"results"`
+	NextOffset int                    `json:"next_offset,omitempty"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "encoding/json"
+
+// PutRoomStateRequest sets the value of a room state key. Value may be any
+// JSON except null.
+type PutRoomStateRequest struct {
+	Value json.RawMessage `json:"value"`
+}
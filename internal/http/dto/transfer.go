@@ -0,0 +1,9 @@
+package dto
+
+// ImportResponse reports how many notifications an import stored. On failure
+// Line and Error point at the input line that stopped it.
+type ImportResponse struct {
+	Imported int    `json:"imported"`
+	Line     int    `json:"line,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	request := func(token, header string) int {
+		router := gin.New()
+		router.GET("/admin", AdminAuth(token), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusServiceUnavailable, request("", ""))
+	require.Equal(t, http.StatusServiceUnavailable, request("", "Bearer "))
+	require.Equal(t, http.StatusUnauthorized, request("secret", ""))
+	require.Equal(t, http.StatusUnauthorized, request("secret", "Bearer wrong"))
+	require.Equal(t, http.StatusNoContent, request("secret", "Bearer secret"))
+}
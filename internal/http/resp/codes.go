@@ -2,6 +2,12 @@ package resp
 
 const (
 	CodeBadRequest    = "bad_request"
+	CodeUnauthorized  = "unauthorized"
+	CodeForbidden     = "forbidden"
+	CodeNotFound      = "not_found"
+	CodeConflict      = "conflict"
+	CodeGone          = "gone"
 	CodeInternalError = "internal_error"
+	CodeUnavailable   = "unavailable"
 	CodeQueued        = "queued"
 )
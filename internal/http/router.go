@@ -22,8 +22,42 @@ func NewRouter(handler *controller.Handler, logger *zap.Logger, cfg *config.Conf
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	router.StaticFile("/", "./public/index.html")
 	router.POST("/notifications", handler.CreateNotification)
+	router.GET("/notifications/search", handler.SearchNotifications)
 	router.POST("/notifications/publish", handler.PublishNotification)
+	router.POST("/notifications/batch", handler.CreateNotificationBatch)
+	router.POST("/notifications/publish/batch", handler.PublishNotificationBatch)
+	router.PATCH("/notifications/:id", handler.UpdateNotification)
+	router.DELETE("/notifications/:id", handler.DeleteNotification)
+	router.POST("/notifications/:id/pin", handler.PinNotification)
+	router.DELETE("/notifications/:id/pin", handler.UnpinNotification)
+	router.POST("/notifications/:id/actions/:action", handler.InvokeAction)
+	router.POST("/notifications/:id/ack", handler.AckNotification)
 	router.GET("/sse/:room", handler.SSE)
+	router.GET("/rooms", handler.ListRooms)
+	router.GET("/rooms/:room", handler.GetRoom)
+	router.GET("/rooms/:room/notifications", handler.ListRoomNotifications)
+	router.GET("/rooms/:room/state", handler.GetRoomState)
+	router.PUT("/rooms/:room/state/:key", handler.PutRoomState)
+	router.DELETE("/rooms/:room/state/:key", handler.DeleteRoomState)
+	router.GET("/notification-types", handler.ListNotificationTypes)
+	router.GET("/announcements", handler.ListAnnouncements)
+	router.GET("/users/:id/preferences", handler.GetUserPreferences)
+	router.PUT("/users/:id/preferences", handler.PutUserPreferences)
+
+	admin := router.Group("/", middleware.AdminAuth(cfg.AdminToken))
+	admin.PUT("/notification-types/:name", handler.UpsertNotificationType)
+	admin.DELETE("/notification-types/:name", handler.DeleteNotificationType)
+	admin.GET("/notifications/export", handler.ExportNotifications)
+	admin.GET("/notifications/:id/delivery", handler.GetNotificationDelivery)
+	admin.POST("/notifications/import", handler.ImportNotifications)
+	admin.POST("/rooms", handler.CreateRoom)
+	admin.PATCH("/rooms/:room", handler.UpdateRoom)
+	admin.DELETE("/rooms/:room", handler.DeleteRoom)
+	admin.POST("/maintenance-windows", handler.CreateMaintenanceWindow)
+	admin.GET("/maintenance-windows", handler.ListMaintenanceWindows)
+	admin.DELETE("/maintenance-windows/:id", handler.DeleteMaintenanceWindow)
+	admin.POST("/announcements", handler.CreateAnnouncement)
+	admin.DELETE("/announcements/:id", handler.DeleteAnnouncement)
 
 	return router
 }
@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/model"
+)
+
+// Catalog holds notification templates keyed by template key and locale.
+// A nil *Catalog is valid and behaves as an empty catalog.
+type Catalog struct {
+	templates map[string]map[string]model.LocalizedContent
+}
+
+// NewCatalog loads the templates file configured by NOTIFICATION_TEMPLATES_PATH.
+// The file maps template keys to per-locale title/body pairs, e.g.
+//
+//	{"deploy.finished": {"en": {"title": "Deploy {{service}} finished", "body": "..."}}}
+func NewCatalog(cfg *config.Config, logger *zap.Logger) (*Catalog, error) {
+	if cfg.NotificationTemplatesPath == "" {
+		return NewCatalogFromMap(nil), nil
+	}
+	raw, err := os.ReadFile(cfg.NotificationTemplatesPath)
+	if err != nil {
+		logger.Error("read notification templates failed", zap.String("path", cfg.NotificationTemplatesPath), zap.Error(err))
+		return nil, fmt.Errorf("read notification templates: %w", err)
+	}
+	var templates map[string]map[string]model.LocalizedContent
+	if err := json.Unmarshal(raw, &templates); err != nil {
+		logger.Error("parse notification templates failed", zap.String("path", cfg.NotificationTemplatesPath), zap.Error(err))
+		return nil, fmt.Errorf("parse notification templates: %w", err)
+	}
+	logger.Info("notification templates loaded", zap.Int("templates", len(templates)))
+	return NewCatalogFromMap(templates), nil
+}
+
+func NewCatalogFromMap(templates map[string]map[string]model.LocalizedContent) *Catalog {
+	normalized := make(map[string]map[string]model.LocalizedContent, len(templates))
+	for key, variants := range templates {
+		normalized[key] = NormalizeLocalizations(variants)
+	}
+	return &Catalog{templates: normalized}
+}
+
+func (c *Catalog) Has(key string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.templates[key]
+	return ok
+}
+
+// Locales returns the locales the catalog has variants of key in.
+func (c *Catalog) Locales(key string) []string {
+	if c == nil {
+		return nil
+	}
+	locales := make([]string, 0, len(c.templates[key]))
+	for locale := range c.templates[key] {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Render resolves the title and body of a notification for the given fallback
+// chain (see Chain). Per-notification localizations take precedence over the
+// template catalog for the same locale; if nothing matches, the notification's
+// own title and body are kept. The returned notification carries the chosen
+// locale and no longer includes the raw variants.
+func (c *Catalog) Render(notification model.Notification, chain []string) model.Notification {
+	for _, locale := range chain {
+		content, ok := notification.Localizations[locale]
+		if !ok && notification.TemplateKey != "" && c != nil {
+			content, ok = c.templates[notification.TemplateKey][locale]
+		}
+		if !ok {
+			continue
+		}
+		if content.Title != "" {
+			notification.Title = content.Title
+		}
+		if content.Body != "" {
+			notification.Body = content.Body
+		}
+		notification.Locale = locale
+		break
+	}
+	if len(notification.TemplateParams) > 0 {
+		pairs := make([]string, 0, len(notification.TemplateParams)*2)
+		for name, value := range notification.TemplateParams {
+			pairs = append(pairs, "{{"+name+"}}", value)
+		}
+		replacer := strings.NewReplacer(pairs...)
+		notification.Title = replacer.Replace(notification.Title)
+		notification.Body = replacer.Replace(notification.Body)
+	}
+	notification.Localizations = nil
+	notification.TemplateParams = nil
+	return notification
+}
+
+// NormalizeLocalizations canonicalizes the locale keys of a variant map and
+// drops entries whose key is not a valid locale.
+func NormalizeLocalizations(variants map[string]model.LocalizedContent) map[string]model.LocalizedContent {
+	if len(variants) == 0 {
+		return nil
+	}
+	normalized := make(map[string]model.LocalizedContent, len(variants))
+	for locale, content := range variants {
+		if tag := Normalize(locale); tag != "" {
+			normalized[tag] = content
+		}
+	}
+	return normalized
+}
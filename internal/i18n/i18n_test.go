@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/model"
+)
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, "zh-TW", Normalize("zh_tw"))
+	require.Equal(t, "zh-Hant-TW", Normalize("ZH-hant-tw"))
+	require.Equal(t, "en", Normalize(" EN "))
+	require.Equal(t, "", Normalize("*"))
+	require.Equal(t, "", Normalize("e"))
+	require.Equal(t, "", Normalize("en-<script>"))
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr;q=0.5, zh-TW, en;q=0.8, de;q=0, *;q=0.1")
+	require.Equal(t, []string{"zh-TW", "en", "fr"}, got)
+	require.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestChain(t *testing.T) {
+	require.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh", "fr", "en"}, Chain([]string{"zh-Hant-TW", "fr", "zh"}, "en"))
+	require.Equal(t, []string{"en"}, Chain(nil, "en"))
+}
+
+func TestCatalogRender(t *testing.T) {
+	catalog := NewCatalogFromMap(map[string]map[string]model.LocalizedContent{
+		"deploy.finished": {
+			"en":    {Title: "Deploy {{service}} finished", Body: "Version {{version}} is live"},
+			"zh_tw": {Title: "{{service}} 部署完成"},
+		},
+	})
+
+	t.Run("notification variant wins", func(t *testing.T) {
+		got := catalog.Render(model.Notification{
+			Title: "hello",
+			Body:  "world",
+			Localizations: map[string]model.LocalizedContent{
+				"fr": {Title: "bonjour", Body: "le monde"},
+			},
+		}, Chain([]string{"fr-CA"}, "en"))
+		require.Equal(t, "fr", got.Locale)
+		require.Equal(t, "bonjour", got.Title)
+		require.Equal(t, "le monde", got.Body)
+		require.Nil(t, got.Localizations)
+	})
+
+	t.Run("template with partial variant", func(t *testing.T) {
+		got := catalog.Render(model.Notification{
+			Title:          "fallback",
+			Body:           "fallback body",
+			TemplateKey:    "deploy.finished",
+			TemplateParams: map[string]string{"service": "api", "version": "1.2.3"},
+		}, Chain([]string{"zh-TW"}, "en"))
+		require.Equal(t, "zh-TW", got.Locale)
+		require.Equal(t, "api 部署完成", got.Title)
+		require.Equal(t, "fallback body", got.Body)
+	})
+
+	t.Run("default locale fallback", func(t *testing.T) {
+		got := catalog.Render(model.Notification{
+			Title:          "fallback",
+			Body:           "fallback body",
+			TemplateKey:    "deploy.finished",
+			TemplateParams: map[string]string{"service": "api", "version": "1.2.3"},
+		}, Chain([]string{"ja"}, "en"))
+		require.Equal(t, "en", got.Locale)
+		require.Equal(t, "Deploy api finished", got.Title)
+		require.Equal(t, "Version 1.2.3 is live", got.Body)
+	})
+
+	t.Run("no match keeps base content", func(t *testing.T) {
+		got := (*Catalog)(nil).Render(model.Notification{Title: "hello", Body: "world"}, Chain([]string{"ja"}, "en"))
+		require.Empty(t, got.Locale)
+		require.Equal(t, "hello", got.Title)
+		require.Equal(t, "world", got.Body)
+	})
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Normalize canonicalizes a locale tag such as "zh_tw" into "zh-TW".
+// It returns an empty string for tags that are not valid locale identifiers.
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	parts := strings.Split(tag, "-")
+	for i, part := range parts {
+		if part == "" || len(part) > 8 || !isAlphaNum(part) {
+			return ""
+		}
+		switch {
+		case i == 0:
+			if len(part) < 2 || len(part) > 3 {
+				return ""
+			}
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header ordered
+// by descending quality. Entries with q=0 or invalid tags are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := Normalize(fields[0])
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+				q = v
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+
+	locales := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		locales = append(locales, entry.tag)
+	}
+	return locales
+}
+
+// Chain builds the fallback chain for the requested locales: each locale is
+// followed by its parent tags ("zh-Hant-TW" -> "zh-Hant" -> "zh"), and the
+// default locale is tried last.
+func Chain(requested []string, defaultLocale string) []string {
+	seen := make(map[string]struct{})
+	var chain []string
+	add := func(tag string) {
+		if tag == "" {
+			return
+		}
+		if _, ok := seen[tag]; ok {
+			return
+		}
+		seen[tag] = struct{}{}
+		chain = append(chain, tag)
+	}
+	for _, tag := range requested {
+		tag = Normalize(tag)
+		for tag != "" {
+			add(tag)
+			idx := strings.LastIndex(tag, "-")
+			if idx < 0 {
+				break
+			}
+			tag = tag[:idx]
+		}
+	}
+	add(Normalize(defaultLocale))
+	return chain
+}
+
+func isAlphaNum(value string) bool {
+	for _, r := range value {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
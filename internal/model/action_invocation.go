@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type ActionInvocation struct {
+	ID             int64     `json:"id"`
+	NotificationID int64     `json:"notification_id"`
+	Room           string    `json:"room"`
+	ActionID       string    `json:"action_id"`
+	UserID         string    `json:"user_id"`
+	InvokedAt      time.Time `json:"invoked_at"`
+}
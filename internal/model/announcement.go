@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Announcement is a message for every connected client regardless of room,
+// such as a planned restart. It is active until ExpiresAt; clients that
+// connect while it is active receive it on connect.
+type Announcement struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Severity  string    `json:"severity,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
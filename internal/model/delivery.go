@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// NotificationDelivery counts the clients of one user a notification was
+// queued to and dropped for; UserID is empty for anonymous clients. A
+// notification evicted from a full client buffer after being queued counts
+// as dropped only.
+type NotificationDelivery struct {
+	NotificationID int64     `json:"-"`
+	UserID         string    `json:"user_id"`
+	Enqueued       int       `json:"enqueued"`
+	Dropped        int       `json:"dropped"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DeliveryStats sums the deliveries of a notification over every client, and
+// lists them per identified user.
+type DeliveryStats struct {
+	NotificationID int64                  `json:"notification_id"`
+	Enqueued       int                    `json:"enqueued"`
+	Dropped        int                    `json:"dropped"`
+	Users          []NotificationDelivery `json:"users"`
+}
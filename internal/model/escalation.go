@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// EscalationPolicy escalates notifications of Type in rooms matching the Room
+// glob that nobody acknowledges. Each step runs AfterSeconds after the
+// previous one, the first after the notification was created, until someone
+// acknowledges the notification or the chain ends.
+type EscalationPolicy struct {
+	Name  string           `json:"name"`
+	Room  string           `json:"room"`
+	Type  string           `json:"type"`
+	Steps []EscalationStep `json:"steps"`
+}
+
+// EscalationStep is one link of an escalation chain. Room is the target of a
+// copy step and URL the endpoint of a webhook step.
+type EscalationStep struct {
+	AfterSeconds int    `json:"after_seconds"`
+	Action       string `json:"action"`
+	Room         string `json:"room,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+func (s EscalationStep) After() time.Duration {
+	return time.Duration(s.AfterSeconds) * time.Second
+}
+
+// Escalation tracks a notification through its policy. Step is the index of
+// the next step to run at DueAt while Status is pending; Attempts counts the
+// runs of that step so far.
+type Escalation struct {
+	NotificationID int64      `json:"notification_id"`
+	Policy         string     `json:"policy"`
+	Step           int        `json:"step"`
+	Attempts       int        `json:"attempts"`
+	Status         string     `json:"status"`
+	DueAt          time.Time  `json:"due_at"`
+	AckedBy        string     `json:"acked_by,omitempty"`
+	AckedAt        *time.Time `json:"acked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// NotificationAck is the result of a user acknowledging a notification and
+// the payload of the notification.acked frame.
+type NotificationAck struct {
+	NotificationID      int64     `json:"notification_id"`
+	Room                string    `json:"room"`
+	UserID              string    `json:"user_id"`
+	AckedAt             time.Time `json:"acked_at"`
+	EscalationCancelled bool      `json:"escalation_cancelled"`
+}
+
+// EscalationRecord is the payload of the notification.escalated frame sent
+// to the room of the escalated notification after each step. EscalatedID is
+// the notification created by a system or copy step.
+type EscalationRecord struct {
+	NotificationID int64     `json:"notification_id"`
+	Policy         string    `json:"policy"`
+	Step           int       `json:"step"`
+	Action         string    `json:"action"`
+	EscalatedID    int64     `json:"escalated_id,omitempty"`
+	EscalatedAt    time.Time `json:"escalated_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// IdempotencyKey maps a producer supplied key to the notification it created.
+// NotificationID is zero while the original request is still in flight.
+type IdempotencyKey struct {
+	Key            string    `json:"key"`
+	NotificationID int64     `json:"notification_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -1,12 +1,55 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+type LocalizedContent struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Action is a button offered to the user; ID is reported back to the producer
+// when the action is invoked.
+type Action struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
 
 type Notification struct {
-	ID        int64     `json:"id"`
-	Room      string    `json:"room"`
-	Type      string    `json:"type"`
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             int64                       `json:"id"`
+	Room           string                      `json:"room"`
+	Type           string                      `json:"type"`
+	Title          string                      `json:"title"`
+	Body           string                      `json:"body"`
+	Severity       string                      `json:"severity,omitempty"`
+	Priority       string                      `json:"priority,omitempty"`
+	Data           json.RawMessage             `json:"data,omitempty"`
+	Link           string                      `json:"link,omitempty"`
+	Actions        []Action                    `json:"actions,omitempty"`
+	Locale         string                      `json:"locale,omitempty"`
+	Localizations  map[string]LocalizedContent `json:"localizations,omitempty"`
+	TemplateKey    string                      `json:"template_key,omitempty"`
+	TemplateParams map[string]string           `json:"template_params,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	ExpiresAt      *time.Time                  `json:"expires_at,omitempty"`
+	UpdatedAt      *time.Time                  `json:"updated_at,omitempty"`
+	DeletedAt      *time.Time                  `json:"deleted_at,omitempty"`
+	// CollapseKey groups notifications in a room: a new notification
+	// supersedes the previous one with the same key, whose id is reported
+	// in Replaces.
+	CollapseKey  string `json:"collapse_key,omitempty"`
+	Replaces     int64  `json:"replaces,omitempty"`
+	SupersededBy int64  `json:"superseded_by,omitempty"`
+	// Seq numbers the notifications of a room from 1 in the order they were
+	// stored, so a client can tell whether it missed any.
+	Seq int64 `json:"seq,omitempty"`
+	// Pinned is set on notifications read from a room's pins, which SSE
+	// sends ahead of the regular history.
+	Pinned   bool       `json:"pinned,omitempty"`
+	PinnedAt *time.Time `json:"pinned_at,omitempty"`
+	// DedupID is the producer's idempotency key. It is only used to detect
+	// retries and is never sent to clients.
+	DedupID string `json:"-"`
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type NotificationType struct {
+	Name              string   `json:"name"`
+	DefaultSeverity   string   `json:"default_severity,omitempty"`
+	DefaultTTLSeconds int      `json:"default_ttl_seconds,omitempty"`
+	AllowedRooms      []string `json:"allowed_rooms,omitempty"`
+}
+
+func (t NotificationType) DefaultTTL() time.Duration {
+	return time.Duration(t.DefaultTTLSeconds) * time.Second
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// NotificationPin keeps a notification at the top of its room: SSE sends
+// pinned notifications before the regular history on every connect.
+type NotificationPin struct {
+	NotificationID int64     `json:"notification_id"`
+	Room           string    `json:"room"`
+	PinnedBy       string    `json:"pinned_by,omitempty"`
+	PinnedAt       time.Time `json:"pinned_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RoomMute silences the rooms matching the Room glob. A nil Until mutes them
+// until the mute is removed.
+type RoomMute struct {
+	Room  string     `json:"room"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// UserPreferences controls which notifications reach a user's connections
+// and history listings.
+type UserPreferences struct {
+	UserID      string      `json:"user_id"`
+	MutedRooms  []RoomMute  `json:"muted_rooms"`
+	OptOutTypes []string    `json:"opt_out_types"`
+	QuietHours  *QuietHours `json:"quiet_hours,omitempty"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,7 @@
+package model
+
+// RoomType is a room together with one notification type stored in it.
+type RoomType struct {
+	Room string
+	Type string
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Room is a registered room. HistoryLimit and the retention fields are zero
+// when the room uses the server defaults.
+type Room struct {
+	Name              string    `json:"name"`
+	DisplayName       string    `json:"display_name,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Owner             string    `json:"owner,omitempty"`
+	Visibility        string    `json:"visibility"`
+	HistoryLimit      int       `json:"history_limit,omitempty"`
+	RetentionDays     int       `json:"retention_days,omitempty"`
+	RetentionMaxCount int       `json:"retention_max_count,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (r Room) RetentionMaxAge() time.Duration {
+	return time.Duration(r.RetentionDays) * 24 * time.Hour
+}
@@ -0,0 +1,8 @@
+package model
+
+// SearchHit is a notification matched by a full-text search. Higher scores
+// rank first; scores are only comparable within one result set.
+type SearchHit struct {
+	Notification Notification
+	Score        float64
+}
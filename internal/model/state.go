@@ -0,0 +1,15 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RoomState is the current value of one key of a room's state. Only the
+// last value is kept.
+type RoomState struct {
+	Room      string          `json:"room"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// MaintenanceWindow holds back live delivery of notifications of Type (all
+// types when empty) in rooms matching the Room glob between StartsAt and
+// EndsAt. The notifications are stored as usual.
+type MaintenanceWindow struct {
+	ID        int64     `json:"id"`
+	Room      string    `json:"room"`
+	Type      string    `json:"type,omitempty"`
+	Mode      string    `json:"mode"`
+	Reason    string    `json:"reason,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// QuietHours is a daily window, in the user's time zone, during which live
+// delivery to the user's connections is held back. Start and End are "15:04"
+// clock times; a window with End before Start spans midnight.
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// SuppressionSummary is the payload of the notification.summary frame sent
+// when a maintenance window or quiet hours end. Source is "maintenance" or
+// "quiet_hours"; NotificationIDs is capped, Count is not. In defer mode
+// Deferred of the Count notifications were replayed right before the summary,
+// up to SUPPRESSION_DEFER_LIMIT; clients fetch the others from history.
+type SuppressionSummary struct {
+	Source          string         `json:"source"`
+	WindowID        int64          `json:"window_id,omitempty"`
+	Room            string         `json:"room"`
+	Mode            string         `json:"mode"`
+	Reason          string         `json:"reason,omitempty"`
+	Count           int            `json:"count"`
+	Deferred        int            `json:"deferred"`
+	CountByType     map[string]int `json:"count_by_type"`
+	NotificationIDs []int64        `json:"notification_ids"`
+	From            time.Time      `json:"from"`
+	To              time.Time      `json:"to"`
+}
@@ -8,4 +8,7 @@ type Consumer interface {
 
 type Publisher interface {
 	Publish(ctx context.Context, payload []byte, routingKey string) error
+	// PublishBatch sends messages in order over a single channel. It stops
+	// at the first failure and reports how many messages were sent before it.
+	PublishBatch(ctx context.Context, messages []Message) (int, error)
 }
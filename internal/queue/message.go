@@ -0,0 +1,24 @@
+package queue
+
+import "context"
+
+// Message is one message of a batch publish. MessageID is sent as the broker
+// message id, like the id attached with WithMessageID for a single publish.
+type Message struct {
+	Payload    []byte
+	RoutingKey string
+	MessageID  string
+}
+
+type messageIDKey struct{}
+
+// WithMessageID attaches a message id to ctx; publishers send it as the
+// broker message id so consumers can deduplicate redeliveries.
+func WithMessageID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, id)
+}
+
+func MessageIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(messageIDKey{}).(string)
+	return id
+}
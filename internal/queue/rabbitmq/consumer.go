@@ -27,27 +27,31 @@ func (n *noopConsumer) Start(ctx context.Context) error {
 }
 
 type Consumer struct {
-	url    string
-	svc    *notify.Service
-	logger *zap.Logger
-	exchange    string
-	queue       string
-	routingKey  string
-	consumerTag string
+	url             string
+	svc             *notify.Service
+	announcements   *notify.AnnouncementService
+	logger          *zap.Logger
+	exchange        string
+	queue           string
+	routingKey      string
+	announcementKey string
+	consumerTag     string
 }
 
-func NewConsumer(cfg *config.Config, svc *notify.Service, logger *zap.Logger) queue.Consumer {
+func NewConsumer(cfg *config.Config, svc *notify.Service, announcements *notify.AnnouncementService, logger *zap.Logger) queue.Consumer {
 	if cfg.RabbitMQURL == "" {
 		return &noopConsumer{}
 	}
 	return &Consumer{
-		url:         cfg.RabbitMQURL,
-		svc:         svc,
-		logger:      logger,
-		exchange:    cfg.RabbitExchange,
-		queue:       cfg.RabbitQueue,
-		routingKey:  cfg.RabbitRoutingKey,
-		consumerTag: cfg.RabbitConsumerTag,
+		url:             cfg.RabbitMQURL,
+		svc:             svc,
+		announcements:   announcements,
+		logger:          logger,
+		exchange:        cfg.RabbitExchange,
+		queue:           cfg.RabbitQueue,
+		routingKey:      cfg.RabbitRoutingKey,
+		announcementKey: cfg.RabbitAnnouncementRoutingKey,
+		consumerTag:     cfg.RabbitConsumerTag,
 	}
 }
 
@@ -122,6 +126,19 @@ func (r *Consumer) Start(ctx context.Context) error {
 		span.SetStatus(codes.Error, "queue bind failed")
 		return fmt.Errorf("rabbitmq queue bind: %w", err)
 	}
+	if r.announcementKey != "" && r.announcementKey != r.routingKey {
+		if err := ch.QueueBind(
+			queueInfo.Name,
+			r.announcementKey,
+			r.exchange,
+			false,
+			nil,
+		); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "queue bind failed")
+			return fmt.Errorf("rabbitmq queue bind: %w", err)
+		}
+	}
 
 	deliveries, err := ch.Consume(
 		queueInfo.Name,
@@ -162,10 +179,20 @@ func (r *Consumer) Start(ctx context.Context) error {
 }
 
 type payload struct {
-	Room  string `json:"room"`
-	Type  string `json:"type"`
-	Title string `json:"title"`
-	Body  string `json:"body"`
+	Room           string                            `json:"room"`
+	Type           string                            `json:"type"`
+	Title          string                            `json:"title"`
+	Body           string                            `json:"body"`
+	Severity       string                            `json:"severity,omitempty"`
+	Priority       string                            `json:"priority,omitempty"`
+	Data           json.RawMessage                   `json:"data,omitempty"`
+	Link           string                            `json:"link,omitempty"`
+	Actions        []model.Action                    `json:"actions,omitempty"`
+	Localizations  map[string]model.LocalizedContent `json:"localizations,omitempty"`
+	TemplateKey    string                            `json:"template_key,omitempty"`
+	TemplateParams map[string]string                 `json:"template_params,omitempty"`
+	CollapseKey    string                            `json:"collapse_key,omitempty"`
+	DedupID        string                            `json:"dedup_id,omitempty"`
 }
 
 func (r *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
@@ -179,6 +206,10 @@ func (r *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
 	)
 	defer span.End()
 
+	if r.announcementKey != "" && msg.RoutingKey == r.announcementKey {
+		return r.handleAnnouncement(ctx, msg)
+	}
+
 	var p payload
 	if err := json.Unmarshal(msg.Body, &p); err != nil {
 		span.RecordError(err)
@@ -186,21 +217,27 @@ func (r *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
 		r.logger.Error("rabbitmq invalid json", zap.Error(err))
 		return msg.Ack(false)
 	}
-	if p.Room == "" || p.Type == "" || p.Title == "" || p.Body == "" {
-		span.SetStatus(codes.Error, "missing required fields")
-		r.logger.Warn("rabbitmq missing required fields",
-			zap.String("room", p.Room),
-			zap.String("type", p.Type),
-			zap.String("title", p.Title),
-		)
-		return msg.Ack(false)
-	}
 
 	notification := model.Notification{
-		Room:  p.Room,
-		Type:  p.Type,
-		Title: p.Title,
-		Body:  p.Body,
+		Room:           p.Room,
+		Type:           p.Type,
+		Title:          p.Title,
+		Body:           p.Body,
+		Severity:       p.Severity,
+		Priority:       p.Priority,
+		Data:           p.Data,
+		Link:           p.Link,
+		Actions:        p.Actions,
+		Localizations:  p.Localizations,
+		TemplateKey:    p.TemplateKey,
+		TemplateParams: p.TemplateParams,
+		CollapseKey:    p.CollapseKey,
+		DedupID:        p.DedupID,
+	}
+	// The AMQP message id only serves as a dedup id when it is a valid
+	// idempotency key; otherwise the message is processed without one.
+	if notification.DedupID == "" && domain.IsValidIdempotencyKey(msg.MessageId) {
+		notification.DedupID = msg.MessageId
 	}
 
 	createCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -208,9 +245,39 @@ func (r *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
 	_, err := r.svc.Create(createCtx, notification)
 	if err != nil {
 		span.RecordError(err)
-		if errors.Is(err, domain.ErrInvalidNotificationType) {
-			span.SetStatus(codes.Error, "invalid notification type")
-			r.logger.Warn("rabbitmq invalid notification type", zap.String("type", p.Type))
+		// Invalid messages will not become valid on redelivery, so they are
+		// acked and dropped.
+		if errors.Is(err, domain.ErrInvalidNotification) {
+			span.SetStatus(codes.Error, "invalid notification")
+			r.logger.Warn("rabbitmq invalid notification",
+				zap.String("room", p.Room),
+				zap.String("type", p.Type),
+				zap.Error(err),
+			)
+			return msg.Ack(false)
+		}
+		// Another delivery of the same message holds the claim and is acked
+		// or redelivered on its own, so this copy is dropped.
+		if errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+			span.SetStatus(codes.Error, "duplicate in progress")
+			r.logger.Info("rabbitmq duplicate message in progress", zap.String("dedup_id", notification.DedupID))
+			return msg.Ack(false)
+		}
+		// The message was already handled; its notification has since been
+		// deleted or purged and must not come back.
+		if errors.Is(err, domain.ErrIdempotentNotificationGone) {
+			span.SetStatus(codes.Error, "duplicate of removed notification")
+			r.logger.Info("rabbitmq duplicate of removed notification", zap.String("dedup_id", notification.DedupID))
+			return msg.Ack(false)
+		}
+		if errors.Is(err, domain.ErrNotificationRejected) {
+			span.SetStatus(codes.Error, "notification rejected")
+			r.logger.Warn("rabbitmq notification rejected", zap.String("room", p.Room), zap.Error(err))
+			return msg.Ack(false)
+		}
+		if errors.Is(err, domain.ErrRoomNotFound) {
+			span.SetStatus(codes.Error, "unknown room")
+			r.logger.Warn("rabbitmq unknown room", zap.String("room", p.Room))
 			return msg.Ack(false)
 		}
 		span.SetStatus(codes.Error, "create notification failed")
@@ -223,3 +290,53 @@ func (r *Consumer) handleMessage(ctx context.Context, msg amqp.Delivery) error {
 
 	return msg.Ack(false)
 }
+
+type announcementPayload struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Severity  string     `json:"severity,omitempty"`
+	Link      string     `json:"link,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// handleAnnouncement stores and broadcasts an announcement received on the
+// announcement routing key.
+func (r *Consumer) handleAnnouncement(ctx context.Context, msg amqp.Delivery) error {
+	ctx, span := otel.Tracer("rabbitmq").Start(ctx, "rabbitmq.handle_announcement")
+	defer span.End()
+
+	var p announcementPayload
+	if err := json.Unmarshal(msg.Body, &p); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid json")
+		r.logger.Error("rabbitmq invalid announcement json", zap.Error(err))
+		return msg.Ack(false)
+	}
+	announcement := model.Announcement{
+		Title:    p.Title,
+		Body:     p.Body,
+		Severity: p.Severity,
+		Link:     p.Link,
+	}
+	if p.ExpiresAt != nil {
+		announcement.ExpiresAt = *p.ExpiresAt
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := r.announcements.Create(createCtx, announcement, ""); err != nil {
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrInvalidAnnouncement) {
+			span.SetStatus(codes.Error, "invalid announcement")
+			r.logger.Warn("rabbitmq invalid announcement", zap.String("title", p.Title), zap.Error(err))
+			return msg.Ack(false)
+		}
+		span.SetStatus(codes.Error, "create announcement failed")
+		r.logger.Error("rabbitmq create announcement failed", zap.Error(err))
+		if nackErr := msg.Nack(false, true); nackErr != nil {
+			r.logger.Error("rabbitmq nack failed", zap.Error(nackErr))
+		}
+		return nil
+	}
+	return msg.Ack(false)
+}
@@ -17,6 +17,7 @@ import (
 	"sse_demo/internal/model"
 	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
 )
 
 func TestConsumerIntegration(t *testing.T) {
@@ -50,8 +51,8 @@ func TestConsumerIntegration(t *testing.T) {
 	}).Once()
 
 	hub := sse.NewHub()
-	svc := notify.NewService(repo, hub, zap.NewNop())
-	consumer := NewConsumer(cfg, svc, zap.NewNop())
+	svc := notify.NewService(cfg, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+	consumer := NewConsumer(cfg, svc, notify.NewAnnouncementService(memory.New(zap.NewNop()), hub, zap.NewNop()), zap.NewNop())
 
 	consumeCtx, cancel := context.WithCancel(ctx)
 	errCh := make(chan error, 1)
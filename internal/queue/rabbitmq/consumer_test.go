@@ -5,15 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"sse_demo/internal/config"
 	"sse_demo/internal/domain"
 	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
 	"sse_demo/internal/service/notify"
 	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
 )
 
 type repoMock struct {
@@ -25,11 +29,36 @@ func (m *repoMock) CreateNotification(ctx context.Context, n model.Notification)
 	return args.Get(0).(model.Notification), args.Error(1)
 }
 
-func (m *repoMock) ListNotifications(ctx context.Context, room string, limit int) ([]model.Notification, error) {
-	args := m.Called(ctx, room, limit)
+func (m *repoMock) CreateNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	args := m.Called(ctx, notifications)
 	return args.Get(0).([]model.Notification), args.Error(1)
 }
 
+func (m *repoMock) GetNotification(ctx context.Context, id int64) (model.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) UpdateNotification(ctx context.Context, n model.Notification) (model.Notification, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) DeleteNotification(ctx context.Context, id int64, deletedAt time.Time) (model.Notification, error) {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) ListNotifications(ctx context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
+	args := m.Called(ctx, room, opts)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *repoMock) SearchNotifications(ctx context.Context, query repository.SearchQuery) ([]model.SearchHit, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.SearchHit), args.Error(1)
+}
+
 type ackMock struct {
 	acked   int
 	nacked  int
@@ -54,7 +83,7 @@ func (a *ackMock) Reject(_ uint64, _ bool) error {
 func TestConsumerHandleMessage(t *testing.T) {
 	t.Run("invalid json", func(t *testing.T) {
 		repo := &repoMock{}
-		svc := notify.NewService(repo, sse.NewHub(), zap.NewNop())
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
 		ack := &ackMock{}
 
@@ -72,7 +101,7 @@ func TestConsumerHandleMessage(t *testing.T) {
 
 	t.Run("missing fields", func(t *testing.T) {
 		repo := &repoMock{}
-		svc := notify.NewService(repo, sse.NewHub(), zap.NewNop())
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
 		ack := &ackMock{}
 
@@ -90,7 +119,7 @@ func TestConsumerHandleMessage(t *testing.T) {
 
 	t.Run("invalid type", func(t *testing.T) {
 		repo := &repoMock{}
-		svc := notify.NewService(repo, sse.NewHub(), zap.NewNop())
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
 		ack := &ackMock{}
 
@@ -110,7 +139,7 @@ func TestConsumerHandleMessage(t *testing.T) {
 		storeErr := errors.New("store failed")
 		repo := &repoMock{}
 		repo.On("CreateNotification", mock.Anything, mock.Anything).Return(model.Notification{}, storeErr).Once()
-		svc := notify.NewService(repo, sse.NewHub(), zap.NewNop())
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
 		ack := &ackMock{}
 
@@ -136,7 +165,7 @@ func TestConsumerHandleMessage(t *testing.T) {
 			Title: "t",
 			Body:  "b",
 		}, nil).Once()
-		svc := notify.NewService(repo, sse.NewHub(), zap.NewNop())
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
 		ack := &ackMock{}
 
@@ -159,4 +188,105 @@ func TestConsumerHandleMessage(t *testing.T) {
 		require.Equal(t, 0, ack.nacked)
 		repo.AssertExpectations(t)
 	})
+
+	t.Run("redelivery with same message id -> ack once stored", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("CreateNotification", mock.Anything, mock.Anything).Return(model.Notification{
+			ID:    1,
+			Room:  "room-1",
+			Type:  domain.NotificationTypeInfo,
+			Title: "t",
+			Body:  "b",
+		}, nil).Once()
+		repo.On("GetNotification", mock.Anything, int64(1)).Return(model.Notification{ID: 1, Room: "room-1"}, nil).Once()
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
+
+		payload, err := json.Marshal(map[string]string{
+			"room":  "room-1",
+			"type":  domain.NotificationTypeInfo,
+			"title": "t",
+			"body":  "b",
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			ack := &ackMock{}
+			msg := amqp.Delivery{
+				Body:         payload,
+				MessageId:    "msg-1",
+				Acknowledger: ack,
+			}
+			require.NoError(t, consumer.handleMessage(context.Background(), msg))
+			require.Equal(t, 1, ack.acked)
+		}
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate in progress -> ack", func(t *testing.T) {
+		repo := &repoMock{}
+		keys := memory.New(zap.NewNop())
+		now := time.Now().UTC()
+		_, _, err := keys.ClaimIdempotencyKey(context.Background(), "msg-1", now, now.Add(-time.Hour), now.Add(-time.Minute))
+		require.NoError(t, err)
+		svc := notify.NewService(&config.Config{}, repo, keys, nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
+		ack := &ackMock{}
+
+		msg := amqp.Delivery{
+			Body:         []byte(`{"room":"room-1","type":"info","title":"t","body":"b"}`),
+			MessageId:    "msg-1",
+			Acknowledger: ack,
+		}
+
+		require.NoError(t, consumer.handleMessage(context.Background(), msg))
+		require.Equal(t, 1, ack.acked)
+		require.Equal(t, 0, ack.nacked)
+		repo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid message id is not used as dedup id", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(n model.Notification) bool {
+			return n.DedupID == ""
+		})).Return(model.Notification{ID: 1, Room: "room-1"}, nil).Once()
+		svc := notify.NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		consumer := &Consumer{svc: svc, logger: zap.NewNop()}
+		ack := &ackMock{}
+
+		msg := amqp.Delivery{
+			Body:         []byte(`{"room":"room-1","type":"info","title":"t","body":"b"}`),
+			MessageId:    "id with\ttab",
+			Acknowledger: ack,
+		}
+
+		require.NoError(t, consumer.handleMessage(context.Background(), msg))
+		require.Equal(t, 1, ack.acked)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("announcement routing key", func(t *testing.T) {
+		store := memory.New(zap.NewNop())
+		consumer := &Consumer{
+			announcements:   notify.NewAnnouncementService(store, sse.NewHub(), zap.NewNop()),
+			announcementKey: "announcement.broadcast",
+			logger:          zap.NewNop(),
+		}
+
+		for _, body := range []string{`{bad json`, `{"title":"t"}`, `{"title":"Restart","body":"in 5 minutes"}`} {
+			ack := &ackMock{}
+			msg := amqp.Delivery{
+				Body:         []byte(body),
+				RoutingKey:   "announcement.broadcast",
+				Acknowledger: ack,
+			}
+			require.NoError(t, consumer.handleMessage(context.Background(), msg))
+			require.Equal(t, 1, ack.acked)
+		}
+
+		active, err := store.ListActiveAnnouncements(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, "Restart", active[0].Title)
+	})
 }
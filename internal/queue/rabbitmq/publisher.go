@@ -22,6 +22,11 @@ func (n *noopPublisher) Publish(ctx context.Context, payload []byte, routingKey
 	return nil
 }
 
+func (n *noopPublisher) PublishBatch(ctx context.Context, messages []queue.Message) (int, error) {
+	_ = ctx
+	return len(messages), nil
+}
+
 type Publisher struct {
 	url    string
 	logger *zap.Logger
@@ -45,22 +50,79 @@ func (p *Publisher) Publish(ctx context.Context, payload []byte, routingKey stri
 	)
 	defer span.End()
 
-	conn, err := amqp.Dial(p.url)
+	conn, ch, err := p.open()
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "dial failed")
-		return fmt.Errorf("rabbitmq dial: %w", err)
+		span.SetStatus(codes.Error, "connect failed")
+		return err
 	}
 	defer func() { _ = conn.Close() }()
+	defer func() { _ = ch.Close() }()
 
-	ch, err := conn.Channel()
+	if err := p.send(ctx, ch, queue.Message{
+		Payload:    payload,
+		RoutingKey: routingKey,
+		MessageID:  queue.MessageIDFromContext(ctx),
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "publish failed")
+		p.logger.Error("rabbitmq publish failed", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// PublishBatch sends every message over one connection and channel, so a
+// batch costs a single dial.
+func (p *Publisher) PublishBatch(ctx context.Context, messages []queue.Message) (int, error) {
+	ctx, span := otel.Tracer("rabbitmq").Start(ctx, "rabbitmq.publish_batch")
+	span.SetAttributes(
+		attribute.String("messaging.system", "rabbitmq"),
+		attribute.String("messaging.destination", p.exchange),
+		attribute.String("messaging.destination_kind", "exchange"),
+		attribute.Int("messaging.batch.message_count", len(messages)),
+	)
+	defer span.End()
+
+	conn, ch, err := p.open()
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "channel failed")
-		return fmt.Errorf("rabbitmq channel: %w", err)
+		span.SetStatus(codes.Error, "connect failed")
+		return 0, err
 	}
+	defer func() { _ = conn.Close() }()
 	defer func() { _ = ch.Close() }()
 
+	for i, message := range messages {
+		if err := p.send(ctx, ch, message); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "publish failed")
+			p.logger.Error("rabbitmq batch publish failed",
+				zap.Int("index", i),
+				zap.String("routing_key", message.RoutingKey),
+				zap.Error(err),
+			)
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// open dials the broker and declares the exchange on a new channel. The
+// caller closes both.
+func (p *Publisher) open() (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rabbitmq dial: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("rabbitmq channel: %w", err)
+	}
+
 	if err := ch.ExchangeDeclare(
 		p.exchange,
 		"topic",
@@ -70,31 +132,28 @@ func (p *Publisher) Publish(ctx context.Context, payload []byte, routingKey stri
 		false,
 		nil,
 	); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "exchange declare failed")
-		return fmt.Errorf("rabbitmq exchange declare: %w", err)
+		_ = ch.Close()
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("rabbitmq exchange declare: %w", err)
 	}
+	return conn, ch, nil
+}
 
+func (p *Publisher) send(ctx context.Context, ch *amqp.Channel, message queue.Message) error {
 	headers := amqp.Table{}
 	otel.GetTextMapPropagator().Inject(ctx, amqpHeaderCarrier(headers))
 
-	if err := ch.PublishWithContext(ctx,
+	return ch.PublishWithContext(ctx,
 		p.exchange,
-		routingKey,
+		message.RoutingKey,
 		false,
 		false,
 		amqp.Publishing{
 			ContentType:  "application/json",
 			DeliveryMode: amqp.Persistent,
+			MessageId:    message.MessageID,
 			Headers:      headers,
-			Body:         payload,
+			Body:         message.Payload,
 		},
-	); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "publish failed")
-		p.logger.Error("rabbitmq publish failed", zap.Error(err))
-		return err
-	}
-
-	return nil
+	)
 }
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/zap"
 	"sse_demo/internal/config"
 	"sse_demo/internal/domain"
+	"sse_demo/internal/queue"
 )
 
 func TestPublisherIntegration(t *testing.T) {
@@ -69,6 +70,21 @@ func TestPublisherIntegration(t *testing.T) {
 	case <-time.After(5 * time.Second):
 		t.Fatalf("timeout waiting for published message")
 	}
+
+	sent, err := publisher.PublishBatch(ctx, []queue.Message{
+		{Payload: body, RoutingKey: "notification." + domain.NotificationTypeInfo, MessageID: "batch-1"},
+		{Payload: body, RoutingKey: "notification." + domain.NotificationTypeInfo, MessageID: "batch-2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, sent)
+	for _, id := range []string{"batch-1", "batch-2"} {
+		select {
+		case msg := <-deliveries:
+			require.Equal(t, id, msg.MessageId)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for batch message %s", id)
+		}
+	}
 }
 
 // setupRabbitMQContainer is defined in testhelpers_integration.go
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type ActionInvocationRepository interface {
+	CreateActionInvocation(ctx context.Context, invocation model.ActionInvocation) (model.ActionInvocation, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+type AnnouncementRepository interface {
+	CreateAnnouncement(ctx context.Context, announcement model.Announcement) (model.Announcement, error)
+	// ListActiveAnnouncements returns the announcements expiring after now,
+	// oldest first.
+	ListActiveAnnouncements(ctx context.Context, now time.Time) ([]model.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id int64) (bool, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type DeliveryRepository interface {
+	// AddNotificationDeliveries adds the counts to those already stored for
+	// each notification and user.
+	AddNotificationDeliveries(ctx context.Context, deliveries []model.NotificationDelivery) error
+	// ListNotificationDeliveries returns the counts of a notification ordered
+	// by user id; anonymous clients are under the empty user id.
+	ListNotificationDeliveries(ctx context.Context, notificationID int64) ([]model.NotificationDelivery, error)
+}
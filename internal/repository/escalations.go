@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+type EscalationRepository interface {
+	// CreateEscalation starts tracking a notification; a notification that is
+	// already tracked is left as is.
+	CreateEscalation(ctx context.Context, escalation model.Escalation) error
+	// ListDueEscalations returns up to limit pending escalations due at now,
+	// earliest first.
+	ListDueEscalations(ctx context.Context, now time.Time, limit int) ([]model.Escalation, error)
+	// ClaimEscalation moves the due time of a due, pending step to leaseUntil,
+	// counts an attempt and reports whether it did; only the caller that
+	// claimed a step runs it.
+	ClaimEscalation(ctx context.Context, notificationID int64, step int, now, leaseUntil time.Time) (bool, error)
+	// AdvanceEscalation sets the next step, attempts, status and due time of a
+	// pending escalation and reports whether it was still pending.
+	AdvanceEscalation(ctx context.Context, escalation model.Escalation) (bool, error)
+	// AckEscalation marks a pending escalation as acknowledged and reports
+	// whether there was one.
+	AckEscalation(ctx context.Context, notificationID int64, userID string, at time.Time) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+type IdempotencyKeyRepository interface {
+	// ClaimIdempotencyKey atomically reserves key. Keys created before
+	// expiredBefore are treated as free, and so are claims that were never
+	// completed and were made before abandonedBefore. When the key is already
+	// taken it returns the existing record and claimed=false.
+	ClaimIdempotencyKey(ctx context.Context, key string, now, expiredBefore, abandonedBefore time.Time) (existing model.IdempotencyKey, claimed bool, err error)
+	CompleteIdempotencyKey(ctx context.Context, key string, notificationID int64) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+type MaintenanceWindowRepository interface {
+	CreateMaintenanceWindow(ctx context.Context, window model.MaintenanceWindow) (model.MaintenanceWindow, error)
+	// ListMaintenanceWindows returns the windows ending after endsAfter,
+	// earliest start first.
+	ListMaintenanceWindows(ctx context.Context, endsAfter time.Time) ([]model.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, id int64) (bool, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type NotificationTypeRepository interface {
+	ListNotificationTypes(ctx context.Context) ([]model.NotificationType, error)
+	UpsertNotificationType(ctx context.Context, notificationType model.NotificationType) error
+	DeleteNotificationType(ctx context.Context, name string) (bool, error)
+}
@@ -2,11 +2,68 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"sse_demo/internal/model"
 )
 
+var (
+	// ErrNotFound is returned by repositories when the requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by repositories when a record with the same key
+	// already exists.
+	ErrConflict = errors.New("already exists")
+	// ErrLimitReached is returned by repositories when adding a record would
+	// exceed a limit checked in the same transaction.
+	ErrLimitReached = errors.New("limit reached")
+)
+
 type NotificationRepository interface {
 	CreateNotification(ctx context.Context, notification model.Notification) (model.Notification, error)
-	ListNotifications(ctx context.Context, room string, limit int) ([]model.Notification, error)
+	// CreateNotifications stores a batch atomically and returns the stored
+	// notifications in input order.
+	CreateNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error)
+	GetNotification(ctx context.Context, id int64) (model.Notification, error)
+	// UpdateNotification replaces the editable content of a notification that
+	// has not been deleted. It returns ErrNotFound otherwise.
+	UpdateNotification(ctx context.Context, notification model.Notification) (model.Notification, error)
+	// DeleteNotification soft-deletes a notification so it is no longer
+	// returned by Get or List. It returns ErrNotFound if it is already gone.
+	DeleteNotification(ctx context.Context, id int64, deletedAt time.Time) (model.Notification, error)
+	ListNotifications(ctx context.Context, room string, opts ListOptions) ([]model.Notification, error)
+	// SearchNotifications matches Text against titles and bodies of live
+	// notifications, best matches first.
+	SearchNotifications(ctx context.Context, query SearchQuery) ([]model.SearchHit, error)
+}
+
+// ListOptions narrows a room history listing. Results are ordered by id,
+// newest first, except with AfterSeq, where they follow the room sequence.
+type ListOptions struct {
+	Limit int
+	// MinPriority drops notifications ranked below it; empty keeps all.
+	MinPriority string
+	// BeforeID and AfterID are exclusive id cursors; zero leaves that side
+	// open. With AfterID set the page holds the notifications closest to it.
+	BeforeID int64
+	AfterID  int64
+	// AfterSeq is an exclusive room sequence cursor, used to resync after a
+	// gap; it walks forward like AfterID, in sequence order. Imported
+	// notifications with preserved ids can sort before existing ones by id
+	// while coming after them in sequence.
+	AfterSeq int64
+	Type     string
+	// Since is inclusive and Until exclusive, both on created_at.
+	Since *time.Time
+	Until *time.Time
+}
+
+// SearchQuery is a full-text search over notification titles and bodies.
+// Room and Type narrow the search when set.
+type SearchQuery struct {
+	Text   string
+	Room   string
+	Type   string
+	Limit  int
+	Offset int
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type PinRepository interface {
+	// PinNotification records a pin and reports false if the notification
+	// was already pinned. With maxPerRoom > 0 it returns ErrLimitReached
+	// when the room already has that many live pins; the count and the
+	// insert happen atomically.
+	PinNotification(ctx context.Context, pin model.NotificationPin, maxPerRoom int) (bool, error)
+	// UnpinNotification removes a pin and reports false if there was none.
+	UnpinNotification(ctx context.Context, notificationID int64) (bool, error)
+	// ListPinnedNotifications returns the live pinned notifications of a room,
+	// most recently pinned first, with Pinned and PinnedAt set.
+	ListPinnedNotifications(ctx context.Context, room string) ([]model.Notification, error)
+}
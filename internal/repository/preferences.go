@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type PreferenceRepository interface {
+	// GetUserPreferences returns ErrNotFound for users that never stored
+	// preferences.
+	GetUserPreferences(ctx context.Context, userID string) (model.UserPreferences, error)
+	UpsertUserPreferences(ctx context.Context, prefs model.UserPreferences) error
+}
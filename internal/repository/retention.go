@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+// PurgeScope selects the notifications of Types in Room that one retention
+// rule covers, and the limits of that rule.
+type PurgeScope struct {
+	Room  string
+	Types []string
+	// Before purges notifications created before it; zero disables the age
+	// limit.
+	Before time.Time
+	// Keep purges all but the newest Keep notifications; zero disables the
+	// count limit.
+	Keep int
+}
+
+type RetentionRepository interface {
+	// ListRoomTypes returns the distinct room and type pairs that have stored
+	// notifications, including deleted ones.
+	ListRoomTypes(ctx context.Context) ([]model.RoomType, error)
+	// PurgeNotifications permanently deletes up to limit of the oldest
+	// notifications in scope that exceed its limits, together with their pins,
+	// deliveries and escalations, and reports how many were deleted.
+	PurgeNotifications(ctx context.Context, scope PurgeScope, limit int) (int64, error)
+}
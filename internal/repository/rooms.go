@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type RoomRepository interface {
+	// CreateRoom returns ErrConflict when a room with the same name exists.
+	CreateRoom(ctx context.Context, room model.Room) (model.Room, error)
+	GetRoom(ctx context.Context, name string) (model.Room, error)
+	ListRooms(ctx context.Context) ([]model.Room, error)
+	// UpdateRoom replaces the metadata of an existing room. It returns
+	// ErrNotFound when the room does not exist.
+	UpdateRoom(ctx context.Context, room model.Room) (model.Room, error)
+	DeleteRoom(ctx context.Context, name string) (bool, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"sse_demo/internal/model"
+)
+
+type RoomStateRepository interface {
+	// UpsertRoomState replaces the value of a key.
+	UpsertRoomState(ctx context.Context, state model.RoomState) error
+	// ListRoomState returns all keys of a room ordered by key.
+	ListRoomState(ctx context.Context, room string) ([]model.RoomState, error)
+	DeleteRoomState(ctx context.Context, room, key string) (bool, error)
+}
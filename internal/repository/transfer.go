@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+// ExportFilter selects notifications for export. Empty fields match
+// everything; AfterID is the keyset cursor of the previous page.
+type ExportFilter struct {
+	Room    string
+	Since   *time.Time
+	Until   *time.Time
+	AfterID int64
+	Limit   int
+}
+
+type TransferRepository interface {
+	// ExportNotifications returns stored notifications in id order, including
+	// superseded, expired and deleted ones.
+	ExportNotifications(ctx context.Context, filter ExportFilter) ([]model.Notification, error)
+	// ImportNotifications stores the notifications as given in one
+	// transaction. A non-zero ID is kept and must be free; zero assigns a new
+	// one. A notification that Replaces another marks it as superseded.
+	ImportNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error)
+}
@@ -0,0 +1,116 @@
+// Package search holds the text handling shared by the notification search
+// implementations: tokenizing for the in-memory index and highlighting of
+// matches in results.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	// snippetRunes is the approximate length of a highlighted body excerpt.
+	snippetRunes = 160
+)
+
+// Terms splits text into lower-cased words of letters and digits, keeping
+// repeats so callers can count term frequency.
+func Terms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// UniqueTerms returns the distinct terms of text in first-seen order.
+func UniqueTerms(text string) []string {
+	var unique []string
+	seen := make(map[string]struct{})
+	for _, term := range Terms(text) {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		unique = append(unique, term)
+	}
+	return unique
+}
+
+// Highlight HTML-escapes text and wraps every word matching one of terms in
+// <mark> tags, so the result can be inserted into a page as is.
+func Highlight(text string, terms []string) string {
+	wanted := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		wanted[term] = struct{}{}
+	}
+
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		word := text[start:end]
+		if _, ok := wanted[strings.ToLower(word)]; ok {
+			b.WriteString(markOpen + html.EscapeString(word) + markClose)
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			flush(i)
+		}
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	if start >= 0 {
+		flush(len(text))
+	}
+	return b.String()
+}
+
+// Snippet highlights an excerpt of text centred on the first match. Text
+// short enough to show whole is highlighted in full.
+func Snippet(text string, terms []string) string {
+	if utf8.RuneCountInString(text) <= snippetRunes {
+		return Highlight(text, terms)
+	}
+	runes := []rune(text)
+	first := -1
+	lower := strings.ToLower(text)
+	for _, term := range terms {
+		i := strings.Index(lower, term)
+		if i < 0 {
+			continue
+		}
+		if at := utf8.RuneCountInString(lower[:i]); first < 0 || at < first {
+			first = at
+		}
+	}
+	from := max(first-snippetRunes/4, 0)
+	to := min(from+snippetRunes, len(runes))
+	from = max(to-snippetRunes, 0)
+	// Widen to word boundaries so no word is cut in half.
+	for from > 0 && !unicode.IsSpace(runes[from-1]) {
+		from--
+	}
+	for to < len(runes) && !unicode.IsSpace(runes[to]) {
+		to++
+	}
+
+	excerpt := Highlight(string(runes[from:to]), terms)
+	if from > 0 {
+		excerpt = "…" + excerpt
+	}
+	if to < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerms(t *testing.T) {
+	require.Equal(t, []string{"invoice", "1234", "is", "overdue", "invoice"}, Terms("Invoice #1234 is overdue (invoice)"))
+	require.Equal(t, []string{"invoice", "1234"}, UniqueTerms("invoice 1234, Invoice"))
+	require.Empty(t, Terms(" -- ?"))
+}
+
+func TestHighlight(t *testing.T) {
+	got := Highlight("Invoice <1234> paid, invoices pending", []string{"invoice", "1234"})
+	require.Equal(t, "<mark>Invoice</mark> &lt;<mark>1234</mark>&gt; paid, invoices pending", got)
+}
+
+func TestSnippet(t *testing.T) {
+	t.Run("short text", func(t *testing.T) {
+		require.Equal(t, "pay <mark>invoice</mark>", Snippet("pay invoice", []string{"invoice"}))
+	})
+
+	t.Run("long text", func(t *testing.T) {
+		text := strings.Repeat("lorem ipsum ", 40) + "invoice 1234 " + strings.Repeat("dolor sit ", 40)
+		got := Snippet(text, []string{"1234"})
+		require.True(t, strings.HasPrefix(got, "…"))
+		require.True(t, strings.HasSuffix(got, "…"))
+		require.Contains(t, got, "invoice <mark>1234</mark>")
+		require.Less(t, len([]rune(got)), 200)
+	})
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// ActionInvokedEvent is the message published when a user invokes a
+// notification action. Producers bind to "<prefix>.<type>.<action_id>".
+type ActionInvokedEvent struct {
+	Event          string    `json:"event"`
+	InvocationID   int64     `json:"invocation_id"`
+	NotificationID int64     `json:"notification_id"`
+	Room           string    `json:"room"`
+	Type           string    `json:"type"`
+	ActionID       string    `json:"action_id"`
+	UserID         string    `json:"user_id"`
+	InvokedAt      time.Time `json:"invoked_at"`
+}
+
+type ActionService struct {
+	notifications repository.NotificationRepository
+	invocations   repository.ActionInvocationRepository
+	pub           queue.Publisher
+	hub           *sse.Hub
+	prefix        string
+	log           *zap.Logger
+}
+
+func NewActionService(cfg *config.Config, notifications repository.NotificationRepository, invocations repository.ActionInvocationRepository, publisher queue.Publisher, hub *sse.Hub, logger *zap.Logger) *ActionService {
+	prefix := cfg.RabbitActionPrefix
+	if prefix == "" {
+		prefix = "action.invoked"
+	}
+	return &ActionService{
+		notifications: notifications,
+		invocations:   invocations,
+		pub:           publisher,
+		hub:           hub,
+		prefix:        prefix,
+		log:           logger,
+	}
+}
+
+// Invoke records that userID invoked actionID on a notification, publishes an
+// action.invoked message for the producer and lets the room know.
+func (s *ActionService) Invoke(ctx context.Context, notificationID int64, actionID, userID string) (model.ActionInvocation, error) {
+	notification, err := s.notifications.GetNotification(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.ActionInvocation{}, domain.ErrNotificationNotFound
+		}
+		s.log.Error("store get notification failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return model.ActionInvocation{}, err
+	}
+	if !hasAction(notification.Actions, actionID) {
+		return model.ActionInvocation{}, domain.ErrActionNotFound
+	}
+
+	invocation, err := s.invocations.CreateActionInvocation(ctx, model.ActionInvocation{
+		NotificationID: notification.ID,
+		Room:           notification.Room,
+		ActionID:       actionID,
+		UserID:         userID,
+		InvokedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		s.log.Error("store create action invocation failed",
+			zap.Int64("notification_id", notificationID),
+			zap.String("action_id", actionID),
+			zap.Error(err),
+		)
+		return model.ActionInvocation{}, err
+	}
+	invocation.Room = notification.Room
+
+	payload, err := json.Marshal(ActionInvokedEvent{
+		Event:          "action.invoked",
+		InvocationID:   invocation.ID,
+		NotificationID: notification.ID,
+		Room:           notification.Room,
+		Type:           notification.Type,
+		ActionID:       actionID,
+		UserID:         userID,
+		InvokedAt:      invocation.InvokedAt,
+	})
+	if err != nil {
+		return model.ActionInvocation{}, err
+	}
+	routingKey := s.prefix + "." + notification.Type + "." + actionID
+	if err := s.pub.Publish(ctx, payload, routingKey); err != nil {
+		s.log.Error("publish action invoked failed",
+			zap.Int64("notification_id", notificationID),
+			zap.String("action_id", actionID),
+			zap.String("routing_key", routingKey),
+			zap.Error(err),
+		)
+		return model.ActionInvocation{}, err
+	}
+
+	s.hub.Publish(sse.Event{Type: sse.EventAction, Room: notification.Room, NotificationType: notification.Type, Payload: invocation})
+	return invocation, nil
+}
+
+func hasAction(actions []model.Action, actionID string) bool {
+	for _, action := range actions {
+		if action.ID == actionID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/queue"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+type publisherMock struct {
+	mock.Mock
+}
+
+func (m *publisherMock) Publish(ctx context.Context, payload []byte, routingKey string) error {
+	args := m.Called(ctx, payload, routingKey)
+	return args.Error(0)
+}
+
+func (m *publisherMock) PublishBatch(ctx context.Context, messages []queue.Message) (int, error) {
+	args := m.Called(ctx, messages)
+	return args.Int(0), args.Error(1)
+}
+
+func TestActionServiceInvoke(t *testing.T) {
+	cfg := &config.Config{RabbitActionPrefix: "action.invoked"}
+	notification := model.Notification{
+		ID:      5,
+		Room:    "room-1",
+		Type:    domain.NotificationTypeWarning,
+		Actions: []model.Action{{ID: "ack", Label: "Acknowledge"}},
+	}
+
+	t.Run("notification not found", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("GetNotification", mock.Anything, int64(5)).Return(model.Notification{}, repository.ErrNotFound).Once()
+		svc := NewActionService(cfg, repo, memory.New(zap.NewNop()), &publisherMock{}, sse.NewHub(), zap.NewNop())
+
+		_, err := svc.Invoke(context.Background(), 5, "ack", "user-1")
+		require.ErrorIs(t, err, domain.ErrNotificationNotFound)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		repo := &repoMock{}
+		repo.On("GetNotification", mock.Anything, int64(5)).Return(notification, nil).Once()
+		pub := &publisherMock{}
+		svc := NewActionService(cfg, repo, memory.New(zap.NewNop()), pub, sse.NewHub(), zap.NewNop())
+
+		_, err := svc.Invoke(context.Background(), 5, "delete", "user-1")
+		require.ErrorIs(t, err, domain.ErrActionNotFound)
+		pub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("publishes and broadcasts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		hub := sse.NewHub()
+		go hub.Run(ctx)
+		client := &sse.Client{Room: "room-1", Ch: make(chan sse.Event, 1)}
+		hub.Register(client)
+		defer hub.Unregister(client)
+
+		repo := &repoMock{}
+		repo.On("GetNotification", mock.Anything, int64(5)).Return(notification, nil).Once()
+		pub := &publisherMock{}
+		pub.On("Publish", mock.Anything, mock.Anything, "action.invoked.warning.ack").Return(nil).Once()
+		svc := NewActionService(cfg, repo, memory.New(zap.NewNop()), pub, hub, zap.NewNop())
+
+		invocation, err := svc.Invoke(context.Background(), 5, "ack", "user-1")
+		require.NoError(t, err)
+		require.NotZero(t, invocation.ID)
+		require.Equal(t, "user-1", invocation.UserID)
+		pub.AssertExpectations(t)
+
+		var event ActionInvokedEvent
+		require.NoError(t, json.Unmarshal(pub.Calls[0].Arguments.Get(1).([]byte), &event))
+		require.Equal(t, "action.invoked", event.Event)
+		require.Equal(t, int64(5), event.NotificationID)
+		require.Equal(t, "ack", event.ActionID)
+
+		select {
+		case got := <-client.Ch:
+			require.Equal(t, sse.EventAction, got.Type)
+			require.Equal(t, invocation, got.Payload)
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("expected action broadcast")
+		}
+	})
+}
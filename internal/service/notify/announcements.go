@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// AnnouncementDeleted is the payload of announcement.deleted frames.
+type AnnouncementDeleted struct {
+	ID int64 `json:"id"`
+}
+
+// AnnouncementService stores announcements and broadcasts them to every
+// connected client regardless of room. Announcements are not notifications:
+// preferences, quiet hours and maintenance windows do not apply to them.
+type AnnouncementService struct {
+	repo repository.AnnouncementRepository
+	hub  *sse.Hub
+	log  *zap.Logger
+}
+
+func NewAnnouncementService(repo repository.AnnouncementRepository, hub *sse.Hub, logger *zap.Logger) *AnnouncementService {
+	return &AnnouncementService{repo: repo, hub: hub, log: logger}
+}
+
+// Create stores an announcement and broadcasts it. ExpiresAt defaults to
+// domain.DefaultAnnouncementTTL from now.
+func (s *AnnouncementService) Create(ctx context.Context, announcement model.Announcement, userID string) (model.Announcement, error) {
+	now := time.Now().UTC()
+	if announcement.ExpiresAt.IsZero() {
+		announcement.ExpiresAt = now.Add(domain.DefaultAnnouncementTTL)
+	}
+	announcement.ExpiresAt = announcement.ExpiresAt.UTC()
+	if err := domain.ValidateAnnouncement(announcement, now); err != nil {
+		return model.Announcement{}, err
+	}
+	announcement.CreatedBy = userID
+	announcement.CreatedAt = now
+	created, err := s.repo.CreateAnnouncement(ctx, announcement)
+	if err != nil {
+		s.log.Error("store create announcement failed", zap.Error(err))
+		return model.Announcement{}, err
+	}
+	s.hub.PublishAll(sse.Event{Type: sse.EventAnnouncement, Payload: created})
+	return created, nil
+}
+
+// ListActive returns the announcements that have not expired, oldest first.
+func (s *AnnouncementService) ListActive(ctx context.Context) ([]model.Announcement, error) {
+	announcements, err := s.repo.ListActiveAnnouncements(ctx, time.Now().UTC())
+	if err != nil {
+		s.log.Error("store list announcements failed", zap.Error(err))
+		return nil, err
+	}
+	return announcements, nil
+}
+
+// Delete removes an announcement and tells connected clients to drop it.
+func (s *AnnouncementService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		s.log.Error("store delete announcement failed", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return domain.ErrAnnouncementNotFound
+	}
+	s.hub.PublishAll(sse.Event{Type: sse.EventAnnouncementDeleted, Payload: AnnouncementDeleted{ID: id}})
+	return nil
+}
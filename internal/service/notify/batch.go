@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+)
+
+// BatchMode selects how a batch reacts to invalid items.
+type BatchMode string
+
+const (
+	// BatchAtomic rejects the whole batch when any item is invalid.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort stores the valid items and reports the invalid ones.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// ParseBatchMode maps the request value to a BatchMode; empty means atomic.
+func ParseBatchMode(value string) (BatchMode, error) {
+	switch BatchMode(value) {
+	case "", BatchAtomic:
+		return BatchAtomic, nil
+	case BatchBestEffort:
+		return BatchBestEffort, nil
+	default:
+		return "", domain.ErrInvalidBatchMode
+	}
+}
+
+// BatchItemResult reports the outcome of one batch item. Err is set for items
+// that were not stored.
+type BatchItemResult struct {
+	Index        int
+	Notification model.Notification
+	Err          error
+}
+
+// ValidateBatch validates every item of a batch create and returns the
+// per-item errors, keyed by position in the batch.
+func (s *Service) ValidateBatch(notifications []model.Notification) map[int]error {
+	invalid := make(map[int]error)
+	for i, notification := range notifications {
+		if err := s.Validate(notification); err != nil {
+			invalid[i] = err
+		} else if notification.DedupID != "" {
+			invalid[i] = domain.ErrBatchDedupUnsupported
+		}
+	}
+	return invalid
+}
+
+// runBatchHooks runs the before-create hooks on the otherwise valid items,
+// marking the ones they reject or leave invalid. Any other hook error fails
+// the whole batch.
+func (s *Service) runBatchHooks(ctx context.Context, notifications []model.Notification, invalid map[int]error) error {
+	if !s.hooks.hasBefore() {
+		return nil
+	}
+	for i := range notifications {
+		if invalid[i] != nil {
+			continue
+		}
+		notification := notifications[i]
+		domain.CleanNotification(&notification, s.sanitize)
+		if err := s.hooks.beforeCreate(ctx, &notification); err != nil {
+			if !errors.Is(err, domain.ErrNotificationRejected) {
+				return err
+			}
+			invalid[i] = err
+			continue
+		}
+		if err := s.Validate(notification); err != nil {
+			invalid[i] = err
+			continue
+		}
+		notifications[i] = notification
+	}
+	return nil
+}
+
+// checkBatchRooms marks the otherwise valid items addressed to unregistered
+// rooms as invalid, looking each room up once.
+func (s *Service) checkBatchRooms(ctx context.Context, notifications []model.Notification, invalid map[int]error) error {
+	if !s.strictRooms {
+		return nil
+	}
+	checked := make(map[string]error)
+	for i, notification := range notifications {
+		if invalid[i] != nil {
+			continue
+		}
+		err, ok := checked[notification.Room]
+		if !ok {
+			err = s.CheckRoom(ctx, notification.Room)
+			if err != nil && !errors.Is(err, domain.ErrRoomNotFound) {
+				return err
+			}
+			checked[notification.Room] = err
+		}
+		if err != nil {
+			invalid[i] = err
+		}
+	}
+	return nil
+}
+
+// CreateBatch validates the items, stores the valid ones in a single
+// transaction and broadcasts them in input order. In atomic mode any invalid
+// item rejects the batch with domain.ErrBatchRejected; the results still carry the
+// per-item errors.
+func (s *Service) CreateBatch(ctx context.Context, notifications []model.Notification, mode BatchMode) ([]BatchItemResult, error) {
+	invalid := s.ValidateBatch(notifications)
+	if err := s.runBatchHooks(ctx, notifications, invalid); err != nil {
+		return nil, err
+	}
+	if err := s.checkBatchRooms(ctx, notifications, invalid); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchItemResult, len(notifications))
+	pending := make([]model.Notification, 0, len(notifications))
+	positions := make([]int, 0, len(notifications))
+	for i, notification := range notifications {
+		results[i] = BatchItemResult{Index: i, Err: invalid[i]}
+		if invalid[i] != nil {
+			continue
+		}
+		domain.CleanNotification(&notification, s.sanitize)
+		s.prepare(&notification)
+		pending = append(pending, notification)
+		positions = append(positions, i)
+	}
+	if len(invalid) > 0 && mode != BatchBestEffort {
+		return results, domain.ErrBatchRejected
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	created, err := s.store.CreateNotifications(ctx, pending)
+	if err != nil {
+		s.log.Error("store create notifications failed", zap.Int("batch_size", len(pending)), zap.Error(err))
+		return nil, err
+	}
+	for i, notification := range created {
+		results[positions[i]].Notification = notification
+		s.escalations.Track(ctx, notification)
+		s.deliver(notification)
+		s.hooks.afterCreate(ctx, notification)
+	}
+	return results, nil
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestServiceCreateBatch(t *testing.T) {
+	batch := []model.Notification{
+		{Room: "room-1", Type: domain.NotificationTypeInfo, Title: "first", Body: "body"},
+		{Room: "room-1", Type: "bad", Title: "second", Body: "body"},
+		{Room: "room-1", Type: domain.NotificationTypeWarning, Title: "third", Body: "body"},
+	}
+
+	t.Run("atomic rejects invalid items", func(t *testing.T) {
+		repo := &repoMock{}
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+
+		results, err := svc.CreateBatch(context.Background(), batch, BatchAtomic)
+		require.ErrorIs(t, err, domain.ErrBatchRejected)
+		require.Len(t, results, 3)
+		require.NoError(t, results[0].Err)
+		require.ErrorIs(t, results[1].Err, domain.ErrInvalidNotificationType)
+		repo.AssertNotCalled(t, "CreateNotifications", mock.Anything, mock.Anything)
+	})
+
+	t.Run("best effort stores valid items in order", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hub := sse.NewHub()
+		go hub.Run(ctx)
+		client := &sse.Client{Room: "room-1", Ch: make(chan sse.Event, 4)}
+		hub.Register(client)
+		defer hub.Unregister(client)
+
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{}, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+
+		results, err := svc.CreateBatch(context.Background(), batch, BatchBestEffort)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Equal(t, "first", results[0].Notification.Title)
+		require.Equal(t, domain.SeverityNormal, results[0].Notification.Severity)
+		require.ErrorIs(t, results[1].Err, domain.ErrInvalidNotificationType)
+		require.Zero(t, results[1].Notification.ID)
+		require.Equal(t, "third", results[2].Notification.Title)
+
+		for _, title := range []string{"first", "third"} {
+			select {
+			case got := <-client.Ch:
+				require.Equal(t, title, got.Notification.Title)
+			case <-time.After(200 * time.Millisecond):
+				t.Fatalf("expected broadcast of %q", title)
+			}
+		}
+	})
+
+	t.Run("dedup id is rejected", func(t *testing.T) {
+		repo := &repoMock{}
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+
+		results, err := svc.CreateBatch(context.Background(), []model.Notification{
+			{Room: "room-1", Type: domain.NotificationTypeInfo, Title: "t", Body: "b", DedupID: "msg-1"},
+		}, BatchAtomic)
+		require.ErrorIs(t, err, domain.ErrBatchRejected)
+		require.ErrorIs(t, results[0].Err, domain.ErrBatchDedupUnsupported)
+	})
+}
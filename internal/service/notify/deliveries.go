@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+const (
+	deliveryFlushInterval = 5 * time.Second
+	deliveryFlushTimeout  = 5 * time.Second
+)
+
+type deliveryKey struct {
+	notificationID int64
+	userID         string
+}
+
+// DeliveryTracker records how many clients each notification was queued to
+// and dropped for. The hub reports every broadcast; the counts are kept in
+// memory and added to the store every few seconds, so each instance
+// contributes the clients connected to it.
+type DeliveryTracker struct {
+	notifications repository.NotificationRepository
+	repo          repository.DeliveryRepository
+	mu            sync.Mutex
+	pending       map[deliveryKey]model.NotificationDelivery
+	log           *zap.Logger
+}
+
+func NewDeliveryTracker(notifications repository.NotificationRepository, repo repository.DeliveryRepository, hub *sse.Hub, logger *zap.Logger) *DeliveryTracker {
+	t := &DeliveryTracker{
+		notifications: notifications,
+		repo:          repo,
+		pending:       make(map[deliveryKey]model.NotificationDelivery),
+		log:           logger,
+	}
+	hub.OnDelivery(t.record)
+	return t
+}
+
+// record adds the outcome of one broadcast to the pending counts. It runs on
+// the hub loop, so it only touches memory.
+func (t *DeliveryTracker) record(deliveries []model.NotificationDelivery) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mergeLocked(deliveries)
+}
+
+func (t *DeliveryTracker) mergeLocked(deliveries []model.NotificationDelivery) {
+	for _, delivery := range deliveries {
+		key := deliveryKey{notificationID: delivery.NotificationID, userID: delivery.UserID}
+		pending := t.pending[key]
+		pending.NotificationID = delivery.NotificationID
+		pending.UserID = delivery.UserID
+		pending.Enqueued += delivery.Enqueued
+		pending.Dropped += delivery.Dropped
+		t.pending[key] = pending
+	}
+}
+
+// Get returns the delivery counts of a notification, including those not
+// flushed yet.
+func (t *DeliveryTracker) Get(ctx context.Context, id int64) (model.DeliveryStats, error) {
+	if _, err := t.notifications.GetNotification(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.DeliveryStats{}, domain.ErrNotificationNotFound
+		}
+		t.log.Error("store get notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.DeliveryStats{}, err
+	}
+	stored, err := t.repo.ListNotificationDeliveries(ctx, id)
+	if err != nil {
+		t.log.Error("store list notification deliveries failed", zap.Int64("id", id), zap.Error(err))
+		return model.DeliveryStats{}, err
+	}
+
+	byUser := make(map[string]int, len(stored))
+	for i, delivery := range stored {
+		byUser[delivery.UserID] = i
+	}
+	now := time.Now().UTC()
+	t.mu.Lock()
+	for key, pending := range t.pending {
+		if key.notificationID != id {
+			continue
+		}
+		if i, ok := byUser[key.userID]; ok {
+			stored[i].Enqueued += pending.Enqueued
+			stored[i].Dropped += pending.Dropped
+			stored[i].UpdatedAt = now
+			continue
+		}
+		pending.UpdatedAt = now
+		stored = append(stored, pending)
+	}
+	t.mu.Unlock()
+
+	stats := model.DeliveryStats{NotificationID: id, Users: []model.NotificationDelivery{}}
+	for _, delivery := range stored {
+		stats.Enqueued += delivery.Enqueued
+		stats.Dropped += delivery.Dropped
+		if delivery.UserID != "" {
+			stats.Users = append(stats.Users, delivery)
+		}
+	}
+	slices.SortFunc(stats.Users, func(a, b model.NotificationDelivery) int { return cmp.Compare(a.UserID, b.UserID) })
+	return stats, nil
+}
+
+func (t *DeliveryTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(deliveryFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Counts of the last seconds are still worth keeping on shutdown.
+			flushCtx, cancel := context.WithTimeout(context.Background(), deliveryFlushTimeout)
+			t.flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			t.flush(ctx)
+		}
+	}
+}
+
+// flush adds the pending counts to the store. On failure they are merged
+// back so the next flush retries them.
+func (t *DeliveryTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	if len(t.pending) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	deliveries := make([]model.NotificationDelivery, 0, len(t.pending))
+	for _, delivery := range t.pending {
+		delivery.UpdatedAt = now
+		deliveries = append(deliveries, delivery)
+	}
+	t.pending = make(map[deliveryKey]model.NotificationDelivery)
+	t.mu.Unlock()
+
+	if err := t.repo.AddNotificationDeliveries(ctx, deliveries); err != nil {
+		t.log.Warn("delivery flush failed", zap.Int("deliveries", len(deliveries)), zap.Error(err))
+		t.mu.Lock()
+		t.mergeLocked(deliveries)
+		t.mu.Unlock()
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestDeliveryTracker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := sse.NewHub()
+	repo := memory.New(zap.NewNop())
+	tracker := NewDeliveryTracker(repo, repo, hub, zap.NewNop())
+	go hub.Run(ctx)
+	// alice's buffer holds a single event, so the second notification is
+	// dropped for her.
+	alice := &sse.Client{Room: "incidents", UserID: "alice", Ch: make(chan sse.Event, 1)}
+	bob := &sse.Client{Room: "incidents", UserID: "bob", Ch: make(chan sse.Event, 4)}
+	anonymous := &sse.Client{Room: "incidents", Ch: make(chan sse.Event, 4)}
+	for _, client := range []*sse.Client{alice, bob, anonymous} {
+		hub.Register(client)
+		defer hub.Unregister(client)
+	}
+
+	svc := NewService(&config.Config{}, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+	first, err := svc.Create(ctx, model.Notification{Room: "incidents", Type: domain.NotificationTypeInfo, Title: "first", Body: "body"})
+	require.NoError(t, err)
+	second, err := svc.Create(ctx, model.Notification{Room: "incidents", Type: domain.NotificationTypeInfo, Title: "second", Body: "body"})
+	require.NoError(t, err)
+
+	check := func() {
+		t.Helper()
+		require.Eventually(t, func() bool {
+			stats, err := tracker.Get(ctx, second.ID)
+			return err == nil && stats.Enqueued+stats.Dropped == 3
+		}, time.Second, 10*time.Millisecond)
+
+		stats, err := tracker.Get(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, stats.Enqueued)
+		require.Zero(t, stats.Dropped)
+		require.Len(t, stats.Users, 2)
+
+		stats, err = tracker.Get(ctx, second.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, stats.Enqueued)
+		require.Equal(t, 1, stats.Dropped)
+		require.Equal(t, "alice", stats.Users[0].UserID)
+		require.Equal(t, 1, stats.Users[0].Dropped)
+		require.Zero(t, stats.Users[0].Enqueued)
+	}
+	check()
+
+	// Flushed counts read the same from the store.
+	tracker.flush(ctx)
+	require.Empty(t, tracker.pending)
+	check()
+
+	_, err = tracker.Get(ctx, 999)
+	require.ErrorIs(t, err, domain.ErrNotificationNotFound)
+}
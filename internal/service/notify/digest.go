@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+const (
+	digestCheckInterval = 5 * time.Second
+	digestFlushTimeout  = 5 * time.Second
+	digestMaxTitles     = 10
+)
+
+// Digest summarizes the notifications accumulated for one room and type
+// during a digest window. It is stored as the data of the digest
+// notification, under the "digest" key.
+type Digest struct {
+	Room            string    `json:"room"`
+	Type            string    `json:"type"`
+	Count           int       `json:"count"`
+	Titles          []string  `json:"titles"`
+	NotificationIDs []int64   `json:"notification_ids"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+}
+
+type digestKey struct {
+	room             string
+	notificationType string
+}
+
+type digestBucket struct {
+	digest Digest
+	due    time.Time
+}
+
+// Digester holds back notifications matched by a digest rule and, when the
+// rule's window ends, stores and broadcasts one digest notification per room
+// and type, so that digests show up in history like any other notification.
+// The digested notifications themselves are stored as usual. A nil Digester
+// digests nothing.
+type Digester struct {
+	rules   []config.DigestRule
+	store   repository.NotificationRepository
+	hub     *sse.Hub
+	mu      sync.Mutex
+	buckets map[digestKey]*digestBucket
+	log     *zap.Logger
+}
+
+func NewDigester(cfg *config.Config, store repository.NotificationRepository, hub *sse.Hub, logger *zap.Logger) *Digester {
+	return &Digester{
+		rules:   cfg.DigestRules,
+		store:   store,
+		hub:     hub,
+		buckets: make(map[digestKey]*digestBucket),
+		log:     logger,
+	}
+}
+
+// Add accumulates the notification if a rule matches it and reports whether
+// it did; otherwise the caller delivers it immediately. High and critical
+// notifications and high-priority ones, which the hub lets preempt, are never
+// digested.
+func (d *Digester) Add(notification model.Notification) bool {
+	if d == nil || notification.Severity == domain.SeverityHigh || notification.Severity == domain.SeverityCritical || notification.Priority == domain.PriorityHigh {
+		return false
+	}
+	rule, ok := d.match(notification)
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bucket := d.bucket(notification.Room, notification.Type, time.Now().UTC().Add(rule.Window))
+	bucket.digest.Count++
+	bucket.digest.NotificationIDs = append(bucket.digest.NotificationIDs, notification.ID)
+	if len(bucket.digest.Titles) < digestMaxTitles {
+		bucket.digest.Titles = append(bucket.digest.Titles, notification.Title)
+	}
+	if bucket.digest.From.IsZero() || notification.CreatedAt.Before(bucket.digest.From) {
+		bucket.digest.From = notification.CreatedAt
+	}
+	if notification.CreatedAt.After(bucket.digest.To) {
+		bucket.digest.To = notification.CreatedAt
+	}
+	return true
+}
+
+// Holds reports whether an open digest holds back the notification with the
+// given ID.
+func (d *Digester) Holds(id int64) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, bucket := range d.buckets {
+		if slices.Contains(bucket.digest.NotificationIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucket returns the open bucket of room and type, opening one due at due if
+// there is none. The caller holds d.mu.
+func (d *Digester) bucket(room, notificationType string, due time.Time) *digestBucket {
+	key := digestKey{room: room, notificationType: notificationType}
+	bucket := d.buckets[key]
+	if bucket == nil {
+		bucket = &digestBucket{digest: Digest{Room: room, Type: notificationType}, due: due}
+		d.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// Run flushes due digests until ctx is done. On shutdown the open buckets
+// are stored regardless of their window, so no digest is lost; they are not
+// broadcast because the hub has stopped, and clients find them in history.
+func (d *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), digestFlushTimeout)
+			for _, digest := range d.take(time.Time{}) {
+				if _, err := d.save(flushCtx, digest); err != nil {
+					d.log.Error("store digest failed on shutdown", zap.String("room", digest.Room), zap.String("type", digest.Type), zap.Int("count", digest.Count), zap.Error(err))
+				}
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			d.flush(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// flush stores and broadcasts the digests whose window ended before now. A
+// digest that cannot be stored goes back to its bucket and is retried on the
+// next check.
+func (d *Digester) flush(ctx context.Context, now time.Time) {
+	for _, digest := range d.take(now) {
+		created, err := d.save(ctx, digest)
+		if err != nil {
+			d.log.Error("store digest failed", zap.String("room", digest.Room), zap.String("type", digest.Type), zap.Error(err))
+			d.requeue(digest, now)
+			continue
+		}
+		d.log.Debug("digest emitted", zap.String("room", digest.Room), zap.String("type", digest.Type), zap.Int("count", digest.Count))
+		d.hub.Broadcast(created)
+	}
+}
+
+// take removes and returns the digests due before now; a zero now takes
+// them all.
+func (d *Digester) take(now time.Time) []Digest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var due []Digest
+	for key, bucket := range d.buckets {
+		if now.IsZero() || !now.Before(bucket.due) {
+			due = append(due, bucket.digest)
+			delete(d.buckets, key)
+		}
+	}
+	return due
+}
+
+// requeue merges a digest that could not be stored back into its bucket.
+func (d *Digester) requeue(digest Digest, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bucket := d.bucket(digest.Room, digest.Type, now)
+	bucket.digest.Count += digest.Count
+	bucket.digest.NotificationIDs = append(digest.NotificationIDs, bucket.digest.NotificationIDs...)
+	bucket.digest.Titles = append(digest.Titles, bucket.digest.Titles...)
+	if len(bucket.digest.Titles) > digestMaxTitles {
+		bucket.digest.Titles = bucket.digest.Titles[:digestMaxTitles]
+	}
+	if bucket.digest.From.IsZero() || digest.From.Before(bucket.digest.From) {
+		bucket.digest.From = digest.From
+	}
+	if digest.To.After(bucket.digest.To) {
+		bucket.digest.To = digest.To
+	}
+	bucket.due = now
+}
+
+// save stores a digest as a notification of the digested type in its room.
+// The digest notification bypasses digests and maintenance windows itself.
+func (d *Digester) save(ctx context.Context, digest Digest) (model.Notification, error) {
+	data, err := json.Marshal(map[string]Digest{"digest": digest})
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return d.store.CreateNotification(ctx, model.Notification{
+		Room:      digest.Room,
+		Type:      digest.Type,
+		Title:     fmt.Sprintf("%d %s notifications", digest.Count, digest.Type),
+		Body:      strings.Join(digest.Titles, "\n"),
+		Severity:  domain.SeverityNormal,
+		Priority:  domain.DefaultPriority(domain.SeverityNormal),
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+func (d *Digester) match(notification model.Notification) (config.DigestRule, bool) {
+	for _, rule := range d.rules {
+		if rule.Type != notification.Type {
+			continue
+		}
+		if ok, err := path.Match(rule.Room, notification.Room); err == nil && ok {
+			return rule, true
+		}
+	}
+	return config.DigestRule{}, false
+}
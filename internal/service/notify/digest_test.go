@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestDigester(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := sse.NewHub()
+	go hub.Run(ctx)
+	client := &sse.Client{Room: "ops-eu", Ch: make(chan sse.Event, 8)}
+	hub.Register(client)
+	defer hub.Unregister(client)
+
+	cfg := &config.Config{DigestRules: []config.DigestRule{{Room: "ops-*", Type: domain.NotificationTypeInfo, Window: time.Minute}}}
+	repo := memory.New(zap.NewNop())
+	digests := NewDigester(cfg, repo, hub, zap.NewNop())
+	svc := NewService(cfg, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, digests, nil, nil, nil, zap.NewNop())
+
+	for _, title := range []string{"disk 80%", "disk 85%"} {
+		_, err := svc.Create(context.Background(), model.Notification{Room: "ops-eu", Type: domain.NotificationTypeInfo, Title: title, Body: "body"})
+		require.NoError(t, err)
+	}
+	_, err := svc.Create(context.Background(), model.Notification{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "warning", Body: "body"})
+	require.NoError(t, err)
+	_, err = svc.Create(context.Background(), model.Notification{Room: "ops-eu", Type: domain.NotificationTypeInfo, Title: "urgent", Body: "body", Priority: domain.PriorityHigh})
+	require.NoError(t, err)
+
+	for _, title := range []string{"warning", "urgent"} {
+		select {
+		case got := <-client.Ch:
+			require.Equal(t, sse.EventNotification, got.Type)
+			require.Equal(t, title, got.Notification.Title)
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("expected %q to be delivered immediately", title)
+		}
+	}
+
+	history, err := svc.ListHistory(context.Background(), "ops-eu", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	digests.flush(context.Background(), time.Now().UTC())
+	select {
+	case got := <-client.Ch:
+		t.Fatalf("unexpected event before the window ended: %s", got.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	digests.flush(context.Background(), time.Now().UTC().Add(time.Minute))
+	select {
+	case got := <-client.Ch:
+		require.Equal(t, sse.EventNotification, got.Type)
+		require.Equal(t, "2 info notifications", got.Notification.Title)
+		var data struct {
+			Digest Digest `json:"digest"`
+		}
+		require.NoError(t, json.Unmarshal(got.Notification.Data, &data))
+		require.Equal(t, 2, data.Digest.Count)
+		require.Equal(t, []string{"disk 80%", "disk 85%"}, data.Digest.Titles)
+		require.Len(t, data.Digest.NotificationIDs, 2)
+		require.False(t, data.Digest.To.Before(data.Digest.From))
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("expected digest notification")
+	}
+
+	// The digest is stored like any other notification.
+	history, err = svc.ListHistory(context.Background(), "ops-eu", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+}
+
+func TestDigesterStoresOpenDigestsOnShutdown(t *testing.T) {
+	cfg := &config.Config{DigestRules: []config.DigestRule{{Room: "ops-*", Type: domain.NotificationTypeInfo, Window: time.Hour}}}
+	repo := memory.New(zap.NewNop())
+	digests := NewDigester(cfg, repo, sse.NewHub(), zap.NewNop())
+	require.True(t, digests.Add(model.Notification{ID: 1, Room: "ops-eu", Type: domain.NotificationTypeInfo, Title: "disk 80%", CreatedAt: time.Now().UTC()}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	digests.Run(ctx)
+
+	history, err := repo.ListNotifications(context.Background(), "ops-eu", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "1 info notifications", history[0].Title)
+}
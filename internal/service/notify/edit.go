@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/i18n"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// NotificationPatch holds the editable fields of a notification. Nil fields
+// are left unchanged; a Data value of JSON null clears the data.
+type NotificationPatch struct {
+	Title         *string
+	Body          *string
+	Severity      *string
+	Priority      *string
+	Data          json.RawMessage
+	Link          *string
+	Actions       *[]model.Action
+	Localizations map[string]model.LocalizedContent
+}
+
+// DeletedEvent is the payload of a notification.deleted frame.
+type DeletedEvent struct {
+	ID   int64  `json:"id"`
+	Room string `json:"room"`
+}
+
+// Update applies a patch to a stored notification and sends the new version
+// to the room as a notification.updated event.
+func (s *Service) Update(ctx context.Context, id int64, patch NotificationPatch) (model.Notification, error) {
+	notification, err := s.store.GetNotification(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Notification{}, domain.ErrNotificationNotFound
+		}
+		s.log.Error("store get notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+
+	if patch.Title != nil {
+		notification.Title = *patch.Title
+	}
+	if patch.Body != nil {
+		notification.Body = *patch.Body
+	}
+	if patch.Severity != nil {
+		notification.Severity = *patch.Severity
+	}
+	if patch.Priority != nil {
+		notification.Priority = *patch.Priority
+	}
+	if patch.Data != nil {
+		notification.Data = patch.Data
+	}
+	if patch.Link != nil {
+		notification.Link = *patch.Link
+	}
+	if patch.Actions != nil {
+		notification.Actions = *patch.Actions
+	}
+	if patch.Localizations != nil {
+		notification.Localizations = patch.Localizations
+	}
+	domain.CleanNotification(&notification, s.sanitize)
+	if err := s.Validate(notification); err != nil {
+		return model.Notification{}, err
+	}
+	notification.Localizations = i18n.NormalizeLocalizations(notification.Localizations)
+	if string(bytes.TrimSpace(notification.Data)) == "null" {
+		notification.Data = nil
+	}
+	now := time.Now().UTC()
+	notification.UpdatedAt = &now
+
+	updated, err := s.store.UpdateNotification(ctx, notification)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Notification{}, domain.ErrNotificationNotFound
+		}
+		s.log.Error("store update notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+	s.hub.Publish(sse.Event{Type: sse.EventUpdated, Room: updated.Room, Notification: updated})
+	return updated, nil
+}
+
+// Delete retracts a notification: it is soft-deleted so history replay skips
+// it, and the room receives a notification.deleted event.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.store.DeleteNotification(ctx, id, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.ErrNotificationNotFound
+		}
+		s.log.Error("store delete notification failed", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+	s.hub.Publish(sse.Event{Type: sse.EventDeleted, Room: deleted.Room, Payload: DeletedEvent{ID: deleted.ID, Room: deleted.Room}})
+	return nil
+}
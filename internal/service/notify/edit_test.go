@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestServiceUpdateAndDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := sse.NewHub()
+	go hub.Run(ctx)
+	client := &sse.Client{Room: "room-1", Ch: make(chan sse.Event, 4)}
+	hub.Register(client)
+	defer hub.Unregister(client)
+
+	repo := memory.New(zap.NewNop())
+	svc := NewService(&config.Config{}, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+	created, err := svc.Create(context.Background(), model.Notification{
+		Room:  "room-1",
+		Type:  domain.NotificationTypeInfo,
+		Title: "Deploy finshed",
+		Body:  "body",
+	})
+	require.NoError(t, err)
+	<-client.Ch
+
+	title := "Deploy finished"
+	updated, err := svc.Update(context.Background(), created.ID, NotificationPatch{Title: &title})
+	require.NoError(t, err)
+	require.Equal(t, title, updated.Title)
+	require.Equal(t, "body", updated.Body)
+	require.NotNil(t, updated.UpdatedAt)
+
+	select {
+	case got := <-client.Ch:
+		require.Equal(t, sse.EventUpdated, got.Type)
+		require.Equal(t, title, got.Notification.Title)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("expected updated event")
+	}
+
+	history, err := svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, title, history[0].Title)
+
+	severity := "bad"
+	_, err = svc.Update(context.Background(), created.ID, NotificationPatch{Severity: &severity})
+	require.ErrorIs(t, err, domain.ErrInvalidSeverity)
+
+	require.NoError(t, svc.Delete(context.Background(), created.ID))
+	select {
+	case got := <-client.Ch:
+		require.Equal(t, sse.EventDeleted, got.Type)
+		require.Equal(t, DeletedEvent{ID: created.ID, Room: "room-1"}, got.Payload)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("expected deleted event")
+	}
+
+	history, err = svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	require.ErrorIs(t, svc.Delete(context.Background(), created.ID), domain.ErrNotificationNotFound)
+	_, err = svc.Update(context.Background(), created.ID, NotificationPatch{Title: &title})
+	require.ErrorIs(t, err, domain.ErrNotificationNotFound)
+}
@@ -0,0 +1,357 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+const (
+	escalationCheckInterval = 5 * time.Second
+	escalationBatchSize     = 100
+	// escalationLease is how long a claimed step may take before the step is
+	// due again, on this or another instance.
+	escalationLease          = time.Minute
+	escalationWebhookTimeout = 10 * time.Second
+	// escalationMaxAttempts is how often a step runs before the escalation is
+	// marked failed. Failed attempts are retried once their lease expires.
+	escalationMaxAttempts = 5
+)
+
+// EscalationWebhook is the body a webhook step POSTs to its URL.
+type EscalationWebhook struct {
+	Event        string             `json:"event"`
+	Policy       string             `json:"policy"`
+	Step         int                `json:"step"`
+	Notification model.Notification `json:"notification"`
+}
+
+// EscalationService escalates notifications nobody acknowledges, following
+// the policies in ESCALATION_POLICIES_PATH. Tracked notifications and their
+// progress are stored, so pending escalations survive restarts; a step cut
+// short by a restart runs again once its lease expires. A nil
+// EscalationService tracks nothing.
+type EscalationService struct {
+	notifications repository.NotificationRepository
+	repo          repository.EscalationRepository
+	hub           *sse.Hub
+	client        *http.Client
+	policies      []model.EscalationPolicy
+	byName        map[string]model.EscalationPolicy
+	// create admits, stores and delivers escalated copies; NewService sets it
+	// so that copies pass the same checks, hooks and holds as any other
+	// notification.
+	create func(ctx context.Context, notification model.Notification) (model.Notification, error)
+	log    *zap.Logger
+}
+
+func NewEscalationService(cfg *config.Config, notifications repository.NotificationRepository, repo repository.EscalationRepository, hub *sse.Hub, logger *zap.Logger) (*EscalationService, error) {
+	policies, err := loadEscalationPolicies(cfg.EscalationPoliciesPath)
+	if err != nil {
+		logger.Error("load escalation policies failed", zap.String("path", cfg.EscalationPoliciesPath), zap.Error(err))
+		return nil, err
+	}
+	byName := make(map[string]model.EscalationPolicy, len(policies))
+	for _, policy := range policies {
+		byName[policy.Name] = policy
+	}
+	return &EscalationService{
+		notifications: notifications,
+		repo:          repo,
+		hub:           hub,
+		client:        &http.Client{Timeout: escalationWebhookTimeout},
+		policies:      policies,
+		byName:        byName,
+		log:           logger,
+	}, nil
+}
+
+// Track starts the escalation of a stored notification if a policy covers
+// it. Failures are logged; the notification itself is already delivered.
+func (s *EscalationService) Track(ctx context.Context, notification model.Notification) {
+	if s == nil {
+		return
+	}
+	policy, ok := domain.MatchEscalationPolicy(s.policies, notification)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	if err := s.repo.CreateEscalation(ctx, model.Escalation{
+		NotificationID: notification.ID,
+		Policy:         policy.Name,
+		Status:         domain.EscalationPending,
+		DueAt:          notification.CreatedAt.Add(policy.Steps[0].After()),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}); err != nil {
+		s.log.Error("store create escalation failed", zap.Int64("notification_id", notification.ID), zap.Error(err))
+	}
+}
+
+// Ack records that userID acknowledged a notification, cancelling its
+// pending escalation if any, and lets the room know.
+func (s *EscalationService) Ack(ctx context.Context, notificationID int64, userID string) (model.NotificationAck, error) {
+	notification, err := s.notifications.GetNotification(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.NotificationAck{}, domain.ErrNotificationNotFound
+		}
+		s.log.Error("store get notification failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return model.NotificationAck{}, err
+	}
+	now := time.Now().UTC()
+	cancelled, err := s.repo.AckEscalation(ctx, notificationID, userID, now)
+	if err != nil {
+		s.log.Error("store ack escalation failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return model.NotificationAck{}, err
+	}
+	ack := model.NotificationAck{
+		NotificationID:      notification.ID,
+		Room:                notification.Room,
+		UserID:              userID,
+		AckedAt:             now,
+		EscalationCancelled: cancelled,
+	}
+	s.hub.Publish(sse.Event{Type: sse.EventAcked, Room: notification.Room, NotificationType: notification.Type, Payload: ack})
+	return ack, nil
+}
+
+func (s *EscalationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(escalationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.escalate(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// escalate runs the steps due at now.
+func (s *EscalationService) escalate(ctx context.Context, now time.Time) {
+	due, err := s.repo.ListDueEscalations(ctx, now, escalationBatchSize)
+	if err != nil {
+		s.log.Error("list due escalations failed", zap.Error(err))
+		return
+	}
+	for _, escalation := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		s.runStep(ctx, escalation, now)
+	}
+}
+
+// runStep claims and runs the next step of an escalation. A failed step is
+// retried when its lease expires, up to escalationMaxAttempts runs; then the
+// escalation is marked failed.
+func (s *EscalationService) runStep(ctx context.Context, escalation model.Escalation, now time.Time) {
+	claimed, err := s.repo.ClaimEscalation(ctx, escalation.NotificationID, escalation.Step, now, now.Add(escalationLease))
+	if err != nil {
+		s.log.Error("store claim escalation failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// Notifications that were deleted, or whose policy is no longer
+	// configured, stop escalating.
+	policy, ok := s.byName[escalation.Policy]
+	if !ok || escalation.Step >= len(policy.Steps) {
+		s.advance(ctx, escalation, policy, now)
+		return
+	}
+	notification, err := s.notifications.GetNotification(ctx, escalation.NotificationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		escalation.Step = len(policy.Steps)
+		s.advance(ctx, escalation, policy, now)
+		return
+	}
+	if err != nil {
+		s.log.Error("store get notification failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+		return
+	}
+
+	step := policy.Steps[escalation.Step]
+	record := model.EscalationRecord{
+		NotificationID: notification.ID,
+		Policy:         policy.Name,
+		Step:           escalation.Step,
+		Action:         step.Action,
+		EscalatedAt:    now,
+	}
+	record.EscalatedID, err = s.apply(ctx, policy, escalation.Step, step, notification, now)
+	if err != nil {
+		// The claim counted this run.
+		escalation.Attempts++
+		s.log.Warn("escalation step failed",
+			zap.Int64("notification_id", notification.ID),
+			zap.String("policy", policy.Name),
+			zap.Int("step", escalation.Step),
+			zap.Int("attempts", escalation.Attempts),
+			zap.Error(err),
+		)
+		if escalation.Attempts >= escalationMaxAttempts {
+			s.fail(ctx, escalation, now)
+		}
+		return
+	}
+	s.log.Info("notification escalated",
+		zap.Int64("notification_id", notification.ID),
+		zap.String("policy", policy.Name),
+		zap.Int("step", escalation.Step),
+		zap.String("action", step.Action),
+	)
+	s.hub.Publish(sse.Event{Type: sse.EventEscalated, Room: notification.Room, NotificationType: notification.Type, Payload: record})
+	escalation.Step++
+	s.advance(ctx, escalation, policy, now)
+}
+
+// advance schedules the step at escalation.Step, or completes the escalation
+// when the policy has no such step.
+func (s *EscalationService) advance(ctx context.Context, escalation model.Escalation, policy model.EscalationPolicy, now time.Time) {
+	escalation.Attempts = 0
+	escalation.UpdatedAt = now
+	escalation.DueAt = now
+	if escalation.Step < len(policy.Steps) {
+		escalation.DueAt = now.Add(policy.Steps[escalation.Step].After())
+	} else {
+		escalation.Status = domain.EscalationCompleted
+	}
+	if _, err := s.repo.AdvanceEscalation(ctx, escalation); err != nil {
+		s.log.Error("store advance escalation failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+	}
+}
+
+// fail gives up on an escalation whose current step keeps failing.
+func (s *EscalationService) fail(ctx context.Context, escalation model.Escalation, now time.Time) {
+	s.log.Error("escalation failed",
+		zap.Int64("notification_id", escalation.NotificationID),
+		zap.String("policy", escalation.Policy),
+		zap.Int("step", escalation.Step),
+	)
+	escalation.Status = domain.EscalationFailed
+	escalation.DueAt = now
+	escalation.UpdatedAt = now
+	if _, err := s.repo.AdvanceEscalation(ctx, escalation); err != nil {
+		s.log.Error("store fail escalation failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+	}
+}
+
+// apply runs one step and returns the id of the notification it created, if
+// any.
+func (s *EscalationService) apply(ctx context.Context, policy model.EscalationPolicy, index int, step model.EscalationStep, notification model.Notification, now time.Time) (int64, error) {
+	switch step.Action {
+	case domain.EscalationActionSystem:
+		return s.resend(ctx, escalatedCopy(notification, notification.Room, domain.NotificationTypeSystem, now))
+	case domain.EscalationActionCopy:
+		return s.resend(ctx, escalatedCopy(notification, step.Room, notification.Type, now))
+	case domain.EscalationActionWebhook:
+		return 0, s.post(ctx, step.URL, EscalationWebhook{
+			Event:        "notification.escalated",
+			Policy:       policy.Name,
+			Step:         index,
+			Notification: notification,
+		})
+	default:
+		return 0, fmt.Errorf("unknown escalation action %q", step.Action)
+	}
+}
+
+// resend creates an escalated copy through the notification service. Copies
+// are not tracked themselves, so escalations cannot chain.
+func (s *EscalationService) resend(ctx context.Context, notification model.Notification) (int64, error) {
+	if s.create == nil {
+		return 0, errors.New("escalated copies need a notification service")
+	}
+	created, err := s.create(ctx, notification)
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (s *EscalationService) post(ctx context.Context, url string, payload EscalationWebhook) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+	return nil
+}
+
+// escalatedCopy carries the content of a notification over to a new one in
+// room with the given type, raised to at least high severity.
+func escalatedCopy(notification model.Notification, room, notificationType string, now time.Time) model.Notification {
+	severity := notification.Severity
+	if severity != domain.SeverityCritical {
+		severity = domain.SeverityHigh
+	}
+	return model.Notification{
+		Room:           room,
+		Type:           notificationType,
+		Title:          notification.Title,
+		Body:           notification.Body,
+		Severity:       severity,
+		Priority:       domain.PriorityHigh,
+		Data:           notification.Data,
+		Link:           notification.Link,
+		Localizations:  notification.Localizations,
+		TemplateKey:    notification.TemplateKey,
+		TemplateParams: notification.TemplateParams,
+		CreatedAt:      now,
+	}
+}
+
+func loadEscalationPolicies(path string) ([]model.EscalationPolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read escalation policies: %w", err)
+	}
+	var policies []model.EscalationPolicy
+	if err := json.Unmarshal(raw, &policies); err != nil {
+		return nil, fmt.Errorf("parse escalation policies: %w", err)
+	}
+	seen := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		if err := domain.ValidateEscalationPolicy(policy); err != nil {
+			return nil, fmt.Errorf("escalation policy %q: %w", policy.Name, err)
+		}
+		if seen[policy.Name] {
+			return nil, fmt.Errorf("escalation policy %q: %w: duplicate name", policy.Name, domain.ErrInvalidEscalationPolicy)
+		}
+		seen[policy.Name] = true
+	}
+	return policies, nil
+}
@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestEscalationService(t *testing.T) {
+	ctx := context.Background()
+
+	webhooks := make(chan EscalationWebhook, 4)
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var payload EscalationWebhook
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		webhooks <- payload
+	}))
+	defer server.Close()
+
+	policies := []model.EscalationPolicy{{
+		Name: "ops-warnings",
+		Room: "ops-*",
+		Type: domain.NotificationTypeWarning,
+		Steps: []model.EscalationStep{
+			{AfterSeconds: 60, Action: domain.EscalationActionSystem},
+			{AfterSeconds: 120, Action: domain.EscalationActionCopy, Room: "escalations"},
+			{AfterSeconds: 60, Action: domain.EscalationActionWebhook, URL: server.URL},
+		},
+	}}
+	raw, err := json.Marshal(policies)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "escalations.json")
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	hub := sse.NewHub()
+	repo := memory.New(zap.NewNop())
+	cfg := &config.Config{EscalationPoliciesPath: path}
+	escalations, err := NewEscalationService(cfg, repo, repo, hub, zap.NewNop())
+	require.NoError(t, err)
+	svc := NewService(cfg, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, escalations, nil, zap.NewNop())
+
+	listRoom := func(room string) []model.Notification {
+		t.Helper()
+		notifications, err := repo.ListNotifications(ctx, room, repository.ListOptions{Limit: 10})
+		require.NoError(t, err)
+		return notifications
+	}
+
+	warning, err := svc.Create(ctx, model.Notification{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "disk almost full", Body: "body"})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, model.Notification{Room: "ops-eu", Type: domain.NotificationTypeInfo, Title: "not escalated", Body: "body"})
+	require.NoError(t, err)
+	start := warning.CreatedAt
+
+	escalations.escalate(ctx, start.Add(59*time.Second))
+	require.Len(t, listRoom("ops-eu"), 2)
+
+	escalations.escalate(ctx, start.Add(time.Minute))
+	resent := listRoom("ops-eu")
+	require.Len(t, resent, 3)
+	require.Equal(t, domain.NotificationTypeSystem, resent[0].Type)
+	require.Equal(t, "disk almost full", resent[0].Title)
+	require.Equal(t, domain.SeverityHigh, resent[0].Severity)
+
+	escalations.escalate(ctx, start.Add(2*time.Minute))
+	require.Empty(t, listRoom("escalations"))
+	escalations.escalate(ctx, start.Add(3*time.Minute))
+	copied := listRoom("escalations")
+	require.Len(t, copied, 1)
+	require.Equal(t, domain.NotificationTypeWarning, copied[0].Type)
+
+	// The first webhook call fails; the step runs again once its lease ends.
+	escalations.escalate(ctx, start.Add(4*time.Minute))
+	require.Empty(t, webhooks)
+	escalations.escalate(ctx, start.Add(4*time.Minute+escalationLease))
+	payload := <-webhooks
+	require.Equal(t, "ops-warnings", payload.Policy)
+	require.Equal(t, 2, payload.Step)
+	require.Equal(t, warning.ID, payload.Notification.ID)
+
+	due, err := repo.ListDueEscalations(ctx, start.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	// Acknowledging cancels the escalation.
+	acked, err := svc.Create(ctx, model.Notification{Room: "ops-us", Type: domain.NotificationTypeWarning, Title: "cpu", Body: "body"})
+	require.NoError(t, err)
+	ack, err := escalations.Ack(ctx, acked.ID, "alice")
+	require.NoError(t, err)
+	require.True(t, ack.EscalationCancelled)
+	require.Equal(t, "ops-us", ack.Room)
+	escalations.escalate(ctx, acked.CreatedAt.Add(time.Hour))
+	require.Len(t, listRoom("ops-us"), 1)
+	ack, err = escalations.Ack(ctx, acked.ID, "bob")
+	require.NoError(t, err)
+	require.False(t, ack.EscalationCancelled)
+
+	_, err = escalations.Ack(ctx, 999, "alice")
+	require.ErrorIs(t, err, domain.ErrNotificationNotFound)
+}
+
+func TestEscalationCopiesAreAdmittedAndFailuresGiveUp(t *testing.T) {
+	ctx := context.Background()
+
+	// Copies go through the service, so strict rooms reject the copy into a
+	// room nobody registered; the step fails until the escalation gives up.
+	policies := []model.EscalationPolicy{{
+		Name:  "ops-warnings",
+		Room:  "ops-*",
+		Type:  domain.NotificationTypeWarning,
+		Steps: []model.EscalationStep{{AfterSeconds: 60, Action: domain.EscalationActionCopy, Room: "escalations"}},
+	}}
+	raw, err := json.Marshal(policies)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "escalations.json")
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	hub := sse.NewHub()
+	repo := memory.New(zap.NewNop())
+	cfg := &config.Config{EscalationPoliciesPath: path, RoomsStrict: true}
+	escalations, err := NewEscalationService(cfg, repo, repo, hub, zap.NewNop())
+	require.NoError(t, err)
+	svc := NewService(cfg, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, escalations, nil, zap.NewNop())
+	_, err = repo.CreateRoom(ctx, model.Room{Name: "ops-eu", Visibility: domain.VisibilityPublic})
+	require.NoError(t, err)
+
+	warning, err := svc.Create(ctx, model.Notification{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "disk almost full", Body: "body"})
+	require.NoError(t, err)
+	now := warning.CreatedAt.Add(time.Minute)
+	for range escalationMaxAttempts {
+		due, err := repo.ListDueEscalations(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		escalations.escalate(ctx, now)
+		now = now.Add(escalationLease)
+	}
+
+	due, err := repo.ListDueEscalations(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+	copies, err := repo.ListNotifications(ctx, "escalations", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, copies)
+}
+
+func TestLoadEscalationPolicies(t *testing.T) {
+	policies, err := loadEscalationPolicies("")
+	require.NoError(t, err)
+	require.Empty(t, policies)
+
+	path := filepath.Join(t.TempDir(), "escalations.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name":"p","room":"*","type":"warning","steps":[{"after_seconds":60,"action":"page"}]}]`), 0o600))
+	_, err = loadEscalationPolicies(path)
+	require.ErrorIs(t, err, domain.ErrInvalidEscalationPolicy)
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+// HistoryPage is one page of a room history listing, newest first. Exactly one
+// of the cursors is set when more notifications follow in the paging
+// direction: NextBefore for older pages, NextAfter when paging towards newer
+// ones with an after cursor and NextAfterSeq with a sequence cursor.
+type HistoryPage struct {
+	Notifications []model.Notification
+	NextBefore    int64
+	NextAfter     int64
+	NextAfterSeq  int64
+}
+
+func (s *Service) ListHistory(ctx context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
+	if opts.MinPriority != "" && !domain.IsValidPriority(opts.MinPriority) {
+		return nil, domain.ErrInvalidPriority
+	}
+	history, err := s.store.ListNotifications(ctx, room, opts)
+	if err != nil {
+		s.log.Error("store list notifications failed", zap.String("room", room), zap.Int("limit", opts.Limit), zap.Error(err))
+		return nil, err
+	}
+	return history, nil
+}
+
+// ListHistoryPage lists one page of history and works out the cursor for the
+// next page by fetching one notification more than requested.
+func (s *Service) ListHistoryPage(ctx context.Context, room string, opts repository.ListOptions) (HistoryPage, error) {
+	limit := max(opts.Limit, 1)
+	opts.Limit = limit + 1
+	history, err := s.ListHistory(ctx, room, opts)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	page := HistoryPage{Notifications: history}
+	if len(history) <= limit {
+		return page, nil
+	}
+	if opts.AfterID > 0 || opts.AfterSeq > 0 {
+		// Forward pages are filled from the cursor, so the extra row is the
+		// newest one.
+		page.Notifications = history[1:]
+		if opts.AfterSeq > 0 {
+			page.NextAfterSeq = page.Notifications[0].Seq
+		} else {
+			page.NextAfter = page.Notifications[0].ID
+		}
+	} else {
+		page.Notifications = history[:limit]
+		page.NextBefore = page.Notifications[limit-1].ID
+	}
+	return page, nil
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+)
+
+// afterCreateHookTimeout bounds each after-create hook, which runs detached
+// from the request that created the notification.
+const afterCreateHookTimeout = 30 * time.Second
+
+// BeforeCreateHook runs before a notification is stored and may change it.
+// Returning an error that wraps domain.ErrNotificationRejected vetoes the
+// notification and is reported to the producer; any other error fails the
+// create as an internal error.
+type BeforeCreateHook interface {
+	Name() string
+	BeforeCreate(ctx context.Context, notification *model.Notification) error
+}
+
+// AfterCreateHook runs once a notification is stored and broadcast. Errors
+// are only logged.
+type AfterCreateHook interface {
+	Name() string
+	AfterCreate(ctx context.Context, notification model.Notification) error
+}
+
+// Hooks is the create hook chain of a Service. Before-create hooks run in
+// order on the creating goroutine; after-create hooks each run on their own
+// goroutine, so a slow or failing hook does not hold up the producer or the
+// other hooks. A nil Hooks runs nothing.
+type Hooks struct {
+	before []BeforeCreateHook
+	after  []AfterCreateHook
+	wg     sync.WaitGroup
+	log    *zap.Logger
+}
+
+func NewHooks(before []BeforeCreateHook, after []AfterCreateHook, logger *zap.Logger) *Hooks {
+	return &Hooks{before: before, after: after, log: logger}
+}
+
+func (h *Hooks) hasBefore() bool {
+	return h != nil && len(h.before) > 0
+}
+
+// beforeCreate runs the before-create hooks in order and stops at the first
+// error.
+func (h *Hooks) beforeCreate(ctx context.Context, notification *model.Notification) error {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.before {
+		if err := h.runBefore(ctx, hook, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) runBefore(ctx context.Context, hook BeforeCreateHook, notification *model.Notification) (err error) {
+	ctx, span := otel.Tracer("notify").Start(ctx, "notify.before_create_hook")
+	span.SetAttributes(
+		attribute.String("notify.hook", hook.Name()),
+		attribute.String("notify.room", notification.Room),
+	)
+	defer span.End()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("before-create hook %s panicked: %v", hook.Name(), recovered)
+		}
+		if err == nil {
+			return
+		}
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrNotificationRejected) {
+			span.SetStatus(codes.Error, "notification rejected")
+			h.log.Info("notification rejected by hook", zap.String("hook", hook.Name()), zap.String("room", notification.Room), zap.Error(err))
+			return
+		}
+		span.SetStatus(codes.Error, "before-create hook failed")
+		h.log.Error("before-create hook failed", zap.String("hook", hook.Name()), zap.String("room", notification.Room), zap.Error(err))
+		err = fmt.Errorf("before-create hook %s: %w", hook.Name(), err)
+	}()
+	return hook.BeforeCreate(ctx, notification)
+}
+
+// afterCreate starts the after-create hooks for a stored notification. They
+// keep the trace of ctx but not its cancellation.
+func (h *Hooks) afterCreate(ctx context.Context, notification model.Notification) {
+	if h == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, hook := range h.after {
+		h.wg.Add(1)
+		go func() {
+			defer h.wg.Done()
+			h.runAfter(ctx, hook, notification)
+		}()
+	}
+}
+
+func (h *Hooks) runAfter(ctx context.Context, hook AfterCreateHook, notification model.Notification) {
+	ctx, cancel := context.WithTimeout(ctx, afterCreateHookTimeout)
+	defer cancel()
+	ctx, span := otel.Tracer("notify").Start(ctx, "notify.after_create_hook")
+	span.SetAttributes(
+		attribute.String("notify.hook", hook.Name()),
+		attribute.Int64("notify.notification_id", notification.ID),
+	)
+	defer span.End()
+
+	var err error
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		err = hook.AfterCreate(ctx, notification)
+	}()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "after-create hook failed")
+		h.log.Warn("after-create hook failed", zap.String("hook", hook.Name()), zap.Int64("id", notification.ID), zap.Error(err))
+	}
+}
+
+// Wait blocks until the after-create hooks started so far have finished.
+func (h *Hooks) Wait() {
+	if h == nil {
+		return
+	}
+	h.wg.Wait()
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+type beforeHookFunc struct {
+	name string
+	fn   func(*model.Notification) error
+}
+
+func (h beforeHookFunc) Name() string { return h.name }
+
+func (h beforeHookFunc) BeforeCreate(_ context.Context, notification *model.Notification) error {
+	return h.fn(notification)
+}
+
+type afterHookFunc struct {
+	name string
+	fn   func(model.Notification) error
+}
+
+func (h afterHookFunc) Name() string { return h.name }
+
+func (h afterHookFunc) AfterCreate(_ context.Context, notification model.Notification) error {
+	return h.fn(notification)
+}
+
+func TestServiceHooks(t *testing.T) {
+	ctx := context.Background()
+	redact := beforeHookFunc{name: "redact", fn: func(n *model.Notification) error {
+		n.Body = strings.ReplaceAll(n.Body, "hunter2", "[redacted]")
+		return nil
+	}}
+	spam := beforeHookFunc{name: "spam", fn: func(n *model.Notification) error {
+		if strings.Contains(n.Title, "WIN") {
+			return fmt.Errorf("%w: looks like spam", domain.ErrNotificationRejected)
+		}
+		return nil
+	}}
+	newService := func(hooks *Hooks) (*Service, *memory.Store) {
+		repo := memory.New(zap.NewNop())
+		return NewService(&config.Config{}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, hooks, zap.NewNop()), repo
+	}
+
+	t.Run("before hooks mutate and reject", func(t *testing.T) {
+		svc, repo := newService(NewHooks([]BeforeCreateHook{redact, spam}, nil, zap.NewNop()))
+
+		created, err := svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "login", Body: "password hunter2"})
+		require.NoError(t, err)
+		stored, err := repo.GetNotification(ctx, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "password [redacted]", stored.Body)
+
+		_, err = svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "WIN a prize", Body: "body"})
+		require.ErrorIs(t, err, domain.ErrNotificationRejected)
+		require.EqualError(t, err, "notification rejected: looks like spam")
+	})
+
+	t.Run("hook changes are validated", func(t *testing.T) {
+		blank := beforeHookFunc{name: "blank", fn: func(n *model.Notification) error {
+			n.Title = ""
+			return nil
+		}}
+		svc, _ := newService(NewHooks([]BeforeCreateHook{blank}, nil, zap.NewNop()))
+
+		_, err := svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "title", Body: "body"})
+		require.ErrorIs(t, err, domain.ErrInvalidNotification)
+	})
+
+	t.Run("failing before hook is an internal error", func(t *testing.T) {
+		down := errors.New("enrichment unavailable")
+		failing := beforeHookFunc{name: "enrich", fn: func(*model.Notification) error { return down }}
+		panicking := beforeHookFunc{name: "broken", fn: func(*model.Notification) error { panic("boom") }}
+
+		svc, _ := newService(NewHooks([]BeforeCreateHook{failing}, nil, zap.NewNop()))
+		_, err := svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "title", Body: "body"})
+		require.ErrorIs(t, err, down)
+		require.NotErrorIs(t, err, domain.ErrNotificationRejected)
+
+		svc, _ = newService(NewHooks([]BeforeCreateHook{panicking}, nil, zap.NewNop()))
+		_, err = svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "title", Body: "body"})
+		require.ErrorContains(t, err, "before-create hook broken panicked: boom")
+	})
+
+	t.Run("after hooks are isolated", func(t *testing.T) {
+		var mu sync.Mutex
+		var audited []int64
+		audit := afterHookFunc{name: "audit", fn: func(n model.Notification) error {
+			mu.Lock()
+			defer mu.Unlock()
+			audited = append(audited, n.ID)
+			return nil
+		}}
+		failing := afterHookFunc{name: "mirror", fn: func(model.Notification) error { return errors.New("mirror down") }}
+		panicking := afterHookFunc{name: "broken", fn: func(model.Notification) error { panic("boom") }}
+		hooks := NewHooks(nil, []AfterCreateHook{panicking, failing, audit}, zap.NewNop())
+		svc, _ := newService(hooks)
+
+		created, err := svc.Create(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "title", Body: "body"})
+		require.NoError(t, err)
+		hooks.Wait()
+		require.Equal(t, []int64{created.ID}, audited)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		var mu sync.Mutex
+		var audited []string
+		audit := afterHookFunc{name: "audit", fn: func(n model.Notification) error {
+			mu.Lock()
+			defer mu.Unlock()
+			audited = append(audited, n.Body)
+			return nil
+		}}
+		hooks := NewHooks([]BeforeCreateHook{redact, spam}, []AfterCreateHook{audit}, zap.NewNop())
+		svc, _ := newService(hooks)
+
+		results, err := svc.CreateBatch(ctx, []model.Notification{
+			{Room: "ops", Type: domain.NotificationTypeInfo, Title: "login", Body: "hunter2"},
+			{Room: "ops", Type: domain.NotificationTypeInfo, Title: "WIN", Body: "body"},
+		}, BatchBestEffort)
+		require.NoError(t, err)
+		require.Equal(t, "[redacted]", results[0].Notification.Body)
+		require.ErrorIs(t, results[1].Err, domain.ErrNotificationRejected)
+		hooks.Wait()
+		require.Equal(t, []string{"[redacted]"}, audited)
+	})
+}
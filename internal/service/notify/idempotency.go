@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/repository"
+)
+
+const (
+	idempotencyPurgeInterval    = 10 * time.Minute
+	idempotencyPurgeBatchSize   = 500
+	defaultIdempotencyRetention = 24 * time.Hour
+	// idempotencyClaimLease is how long a claimed key may stay without a
+	// notification before a retry may take it over, e.g. after a crash
+	// between claiming and completing it.
+	idempotencyClaimLease = time.Minute
+)
+
+// IdempotencyPurger periodically deletes idempotency keys that are older than
+// the configured retention window.
+type IdempotencyPurger struct {
+	keys      repository.IdempotencyKeyRepository
+	retention time.Duration
+	log       *zap.Logger
+}
+
+func NewIdempotencyPurger(cfg *config.Config, keys repository.IdempotencyKeyRepository, logger *zap.Logger) *IdempotencyPurger {
+	return &IdempotencyPurger{keys: keys, retention: idempotencyRetention(cfg), log: logger}
+}
+
+// idempotencyRetention falls back to the default window when the config
+// leaves it unset, so keys never expire the moment they are claimed.
+func idempotencyRetention(cfg *config.Config) time.Duration {
+	if cfg.IdempotencyRetention <= 0 {
+		return defaultIdempotencyRetention
+	}
+	return cfg.IdempotencyRetention
+}
+
+func (p *IdempotencyPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *IdempotencyPurger) purge(ctx context.Context) {
+	before := time.Now().UTC().Add(-p.retention)
+	var total int64
+	for {
+		purged, err := p.keys.PurgeIdempotencyKeys(ctx, before, idempotencyPurgeBatchSize)
+		if err != nil {
+			p.log.Error("purge idempotency keys failed", zap.Error(err))
+			return
+		}
+		total += purged
+		if purged < idempotencyPurgeBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+	if total > 0 {
+		p.log.Info("idempotency keys purged", zap.Int64("count", total))
+	}
+}
@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+const (
+	maintenanceCheckInterval   = 5 * time.Second
+	maintenanceRefreshInterval = 30 * time.Second
+	maintenanceSummaryLimit    = 100
+)
+
+type maintenanceKey struct {
+	windowID int64
+	room     string
+}
+
+type maintenanceBucket struct {
+	summary model.SuppressionSummary
+	held    []model.Notification
+	ids     map[int64]bool
+}
+
+// MaintenanceService manages maintenance windows and holds back live delivery
+// of the notifications they cover. Suppressed notifications are only counted;
+// deferred ones, up to the configured defer limit per room, are broadcast
+// when the window ends and the rest stay in history. Either way a
+// notification.summary event per room follows. The notifications themselves
+// are stored as usual. Windows created on other instances are picked up on
+// the next refresh. A nil MaintenanceService holds nothing.
+type MaintenanceService struct {
+	repo      repository.MaintenanceWindowRepository
+	hub       *sse.Hub
+	mu        sync.Mutex
+	windows   []model.MaintenanceWindow
+	refreshed time.Time
+	buckets   map[maintenanceKey]*maintenanceBucket
+	deferMax  int
+	log       *zap.Logger
+}
+
+func NewMaintenanceService(cfg *config.Config, repo repository.MaintenanceWindowRepository, hub *sse.Hub, logger *zap.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		repo:     repo,
+		hub:      hub,
+		buckets:  make(map[maintenanceKey]*maintenanceBucket),
+		deferMax: cfg.SuppressionDeferLimit,
+		log:      logger,
+	}
+}
+
+// Create stores a maintenance window. Mode defaults to suppress and StartsAt
+// to now.
+func (s *MaintenanceService) Create(ctx context.Context, window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	now := time.Now().UTC()
+	if window.Mode == "" {
+		window.Mode = domain.SuppressionSuppress
+	}
+	if window.StartsAt.IsZero() {
+		window.StartsAt = now
+	}
+	window.StartsAt = window.StartsAt.UTC()
+	window.EndsAt = window.EndsAt.UTC()
+	if err := domain.ValidateMaintenanceWindow(window, now); err != nil {
+		return model.MaintenanceWindow{}, err
+	}
+	window.CreatedAt = now
+	created, err := s.repo.CreateMaintenanceWindow(ctx, window)
+	if err != nil {
+		s.log.Error("store create maintenance window failed", zap.String("room", window.Room), zap.Error(err))
+		return model.MaintenanceWindow{}, err
+	}
+
+	s.mu.Lock()
+	s.windows = append(s.windows, created)
+	s.mu.Unlock()
+	return created, nil
+}
+
+// List returns the windows that have not ended yet.
+func (s *MaintenanceService) List(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	windows, err := s.repo.ListMaintenanceWindows(ctx, time.Now().UTC())
+	if err != nil {
+		s.log.Error("store list maintenance windows failed", zap.Error(err))
+		return nil, err
+	}
+	return windows, nil
+}
+
+// Delete removes a window and ends it immediately: whatever it held back is
+// released right away.
+func (s *MaintenanceService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteMaintenanceWindow(ctx, id)
+	if err != nil {
+		s.log.Error("store delete maintenance window failed", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return domain.ErrMaintenanceWindowNotFound
+	}
+
+	s.mu.Lock()
+	active := s.windows[:0]
+	for _, window := range s.windows {
+		if window.ID != id {
+			active = append(active, window)
+		}
+	}
+	s.windows = active
+	s.mu.Unlock()
+	s.flush(time.Now().UTC())
+	return nil
+}
+
+// Hold records the notification if an active window covers it and reports
+// whether it did; otherwise the caller delivers it immediately. Critical and
+// high-priority notifications are never held, matching the hub, which lets
+// high priority through quiet hours.
+func (s *MaintenanceService) Hold(notification model.Notification) bool {
+	if s == nil || notification.Severity == domain.SeverityCritical || notification.Priority == domain.PriorityHigh {
+		return false
+	}
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, window := range s.windows {
+		if !domain.MaintenanceWindowCovers(window, notification, now) {
+			continue
+		}
+		key := maintenanceKey{windowID: window.ID, room: notification.Room}
+		bucket := s.buckets[key]
+		if bucket == nil {
+			bucket = &maintenanceBucket{summary: model.SuppressionSummary{
+				Source:      domain.SuppressionSourceMaintenance,
+				WindowID:    window.ID,
+				Room:        notification.Room,
+				Mode:        window.Mode,
+				Reason:      window.Reason,
+				CountByType: make(map[string]int),
+				From:        notification.CreatedAt,
+			}, ids: make(map[int64]bool)}
+			s.buckets[key] = bucket
+		}
+		bucket.ids[notification.ID] = true
+		bucket.summary.Count++
+		bucket.summary.CountByType[notification.Type]++
+		if len(bucket.summary.NotificationIDs) < maintenanceSummaryLimit {
+			bucket.summary.NotificationIDs = append(bucket.summary.NotificationIDs, notification.ID)
+		}
+		bucket.summary.To = notification.CreatedAt
+		if window.Mode == domain.SuppressionDefer && len(bucket.held) < s.deferMax {
+			bucket.held = append(bucket.held, notification)
+			bucket.summary.Deferred++
+		}
+		return true
+	}
+	return false
+}
+
+// Holds reports whether an active window currently holds back the
+// notification with the given ID.
+func (s *MaintenanceService) Holds(id int64) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, bucket := range s.buckets {
+		if bucket.ids[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MaintenanceService) Run(ctx context.Context) {
+	s.refresh(ctx)
+	ticker := time.NewTicker(maintenanceCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Since(s.refreshed) >= maintenanceRefreshInterval {
+				s.refresh(ctx)
+			}
+			s.flush(time.Now().UTC())
+		}
+	}
+}
+
+// refresh reloads the windows that have not ended yet from the store.
+func (s *MaintenanceService) refresh(ctx context.Context) {
+	windows, err := s.repo.ListMaintenanceWindows(ctx, time.Now().UTC())
+	if err != nil {
+		s.log.Warn("maintenance windows refresh failed", zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	s.windows = windows
+	s.refreshed = time.Now()
+	s.mu.Unlock()
+}
+
+// flush releases the buckets of windows that ended before now or are no
+// longer known, and drops ended windows from the cache.
+func (s *MaintenanceService) flush(now time.Time) {
+	s.mu.Lock()
+	active := make(map[int64]bool, len(s.windows))
+	current := s.windows[:0]
+	for _, window := range s.windows {
+		if now.Before(window.EndsAt) {
+			active[window.ID] = true
+			current = append(current, window)
+		}
+	}
+	s.windows = current
+	var due []*maintenanceBucket
+	for key, bucket := range s.buckets {
+		if !active[key.windowID] {
+			due = append(due, bucket)
+			delete(s.buckets, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, bucket := range due {
+		for _, notification := range bucket.held {
+			s.hub.Broadcast(notification)
+		}
+		s.log.Debug("maintenance summary emitted",
+			zap.Int64("window_id", bucket.summary.WindowID),
+			zap.String("room", bucket.summary.Room),
+			zap.Int("count", bucket.summary.Count),
+		)
+		s.hub.Publish(sse.Event{Type: sse.EventSummary, Room: bucket.summary.Room, Payload: bucket.summary})
+	}
+}
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestMaintenanceService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := sse.NewHub()
+	go hub.Run(ctx)
+	client := &sse.Client{Room: "ops-eu", Ch: make(chan sse.Event, 8)}
+	hub.Register(client)
+	defer hub.Unregister(client)
+
+	repo := memory.New(zap.NewNop())
+	// Only the first held notification is replayed; the summary says so.
+	cfg := &config.Config{SuppressionDeferLimit: 1}
+	maintenance := NewMaintenanceService(cfg, repo, hub, zap.NewNop())
+	svc := NewService(&config.Config{}, repo, repo, repo, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, maintenance, nil, nil, zap.NewNop())
+
+	_, err := maintenance.Create(ctx, model.MaintenanceWindow{Room: "ops-*", Type: domain.NotificationTypeWarning, EndsAt: time.Now().Add(-time.Minute)})
+	require.ErrorIs(t, err, domain.ErrInvalidMaintenanceWindow)
+	window, err := maintenance.Create(ctx, model.MaintenanceWindow{
+		Room:   "ops-*",
+		Type:   domain.NotificationTypeWarning,
+		Mode:   domain.SuppressionDefer,
+		Reason: "db upgrade",
+		EndsAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	for _, n := range []model.Notification{
+		{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "held 1", Body: "body", Severity: domain.SeverityNormal},
+		{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "held 2", Body: "body", Severity: domain.SeverityNormal},
+		{Room: "ops-eu", Type: domain.NotificationTypeInfo, Title: "other type", Body: "body"},
+		{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "high priority", Body: "body", Severity: domain.SeverityNormal, Priority: domain.PriorityHigh},
+		{Room: "ops-eu", Type: domain.NotificationTypeWarning, Title: "critical", Body: "body", Severity: domain.SeverityCritical},
+	} {
+		_, err := svc.Create(ctx, n)
+		require.NoError(t, err)
+	}
+	for _, title := range []string{"other type", "high priority", "critical"} {
+		select {
+		case got := <-client.Ch:
+			require.Equal(t, title, got.Notification.Title)
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("expected %q to be delivered immediately", title)
+		}
+	}
+
+	history, err := svc.ListHistory(ctx, "ops-eu", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+
+	maintenance.flush(time.Now().UTC())
+	select {
+	case got := <-client.Ch:
+		t.Fatalf("unexpected event before the window ended: %s", got.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, maintenance.Delete(ctx, window.ID))
+	require.ErrorIs(t, maintenance.Delete(ctx, window.ID), domain.ErrMaintenanceWindowNotFound)
+	for _, title := range []string{"held 1"} {
+		select {
+		case got := <-client.Ch:
+			require.Equal(t, sse.EventNotification, got.Type)
+			require.Equal(t, title, got.Notification.Title)
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("expected deferred %q", title)
+		}
+	}
+	select {
+	case got := <-client.Ch:
+		require.Equal(t, sse.EventSummary, got.Type)
+		summary := got.Payload.(model.SuppressionSummary)
+		require.Equal(t, domain.SuppressionSourceMaintenance, summary.Source)
+		require.Equal(t, window.ID, summary.WindowID)
+		require.Equal(t, 2, summary.Count)
+		require.Equal(t, 1, summary.Deferred)
+		require.Equal(t, map[string]int{domain.NotificationTypeWarning: 2}, summary.CountByType)
+		require.Equal(t, "db upgrade", summary.Reason)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("expected summary event")
+	}
+}
@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// PinEvent is the payload of notification.pinned and notification.unpinned
+// frames.
+type PinEvent struct {
+	ID   int64  `json:"id"`
+	Room string `json:"room"`
+}
+
+// PinService pins notifications to the top of their room. Each room holds
+// at most PINS_MAX_PER_ROOM live pins; pins of deleted, superseded or
+// expired notifications do not count. The store checks the limit in the same
+// transaction as the insert, so it holds across instances.
+type PinService struct {
+	notifications repository.NotificationRepository
+	pins          repository.PinRepository
+	hub           *sse.Hub
+	maxPerRoom    int
+	log           *zap.Logger
+}
+
+func NewPinService(cfg *config.Config, notifications repository.NotificationRepository, pins repository.PinRepository, hub *sse.Hub, logger *zap.Logger) *PinService {
+	return &PinService{notifications: notifications, pins: pins, hub: hub, maxPerRoom: cfg.PinsMaxPerRoom, log: logger}
+}
+
+// Pin pins a notification on behalf of userID. Superseded and expired
+// notifications cannot be pinned. Pinning a pinned notification returns it
+// unchanged and publishes nothing.
+func (s *PinService) Pin(ctx context.Context, id int64, userID string) (model.Notification, error) {
+	notification, err := s.get(ctx, id)
+	if err != nil {
+		return model.Notification{}, err
+	}
+	now := time.Now().UTC()
+	if notification.SupersededBy != 0 || (notification.ExpiresAt != nil && !notification.ExpiresAt.After(now)) {
+		return model.Notification{}, domain.ErrNotificationNotLive
+	}
+
+	added, err := s.pins.PinNotification(ctx, model.NotificationPin{
+		NotificationID: id,
+		Room:           notification.Room,
+		PinnedBy:       userID,
+		PinnedAt:       now,
+	}, s.maxPerRoom)
+	if errors.Is(err, repository.ErrLimitReached) {
+		return model.Notification{}, domain.ErrPinLimitReached
+	}
+	if err != nil {
+		s.log.Error("store pin notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+	if !added {
+		return s.pinned(ctx, notification)
+	}
+	notification.Pinned = true
+	notification.PinnedAt = &now
+	s.hub.Publish(sse.Event{Type: sse.EventPinned, Room: notification.Room, Payload: PinEvent{ID: id, Room: notification.Room}})
+	return notification, nil
+}
+
+func (s *PinService) Unpin(ctx context.Context, id int64) error {
+	notification, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	removed, err := s.pins.UnpinNotification(ctx, id)
+	if err != nil {
+		s.log.Error("store unpin notification failed", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+	if !removed {
+		return domain.ErrNotificationNotPinned
+	}
+	s.hub.Publish(sse.Event{Type: sse.EventUnpinned, Room: notification.Room, Payload: PinEvent{ID: id, Room: notification.Room}})
+	return nil
+}
+
+// List returns the live pinned notifications of room, most recently pinned
+// first.
+func (s *PinService) List(ctx context.Context, room string) ([]model.Notification, error) {
+	pinned, err := s.pins.ListPinnedNotifications(ctx, room)
+	if err != nil {
+		s.log.Error("store list pinned notifications failed", zap.String("room", room), zap.Error(err))
+		return nil, err
+	}
+	return pinned, nil
+}
+
+// pinned returns notification as it is currently pinned.
+func (s *PinService) pinned(ctx context.Context, notification model.Notification) (model.Notification, error) {
+	pinned, err := s.List(ctx, notification.Room)
+	if err != nil {
+		return model.Notification{}, err
+	}
+	if i := slices.IndexFunc(pinned, func(n model.Notification) bool { return n.ID == notification.ID }); i >= 0 {
+		return pinned[i], nil
+	}
+	notification.Pinned = true
+	return notification, nil
+}
+
+func (s *PinService) get(ctx context.Context, id int64) (model.Notification, error) {
+	notification, err := s.notifications.GetNotification(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Notification{}, domain.ErrNotificationNotFound
+		}
+		s.log.Error("store get notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+	return notification, nil
+}
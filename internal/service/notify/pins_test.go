@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
+)
+
+func TestPinService(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := sse.NewHub()
+	go hub.Run(ctx)
+	client := &sse.Client{Room: "room-1", Ch: make(chan sse.Event, 8)}
+	hub.Register(client)
+	defer hub.Unregister(client)
+
+	repo := memory.New(zap.NewNop())
+	pins := NewPinService(&config.Config{PinsMaxPerRoom: 1}, repo, repo, hub, zap.NewNop())
+	now := time.Now().UTC()
+	create := func(notification model.Notification) model.Notification {
+		notification.Room, notification.Type, notification.Title, notification.Body, notification.CreatedAt = "room-1", domain.NotificationTypeInfo, "t", "b", now
+		created, err := repo.CreateNotification(context.Background(), notification)
+		require.NoError(t, err)
+		return created
+	}
+
+	t.Run("pinning twice publishes once", func(t *testing.T) {
+		notification := create(model.Notification{})
+		for range 2 {
+			pinned, err := pins.Pin(context.Background(), notification.ID, "alice")
+			require.NoError(t, err)
+			require.True(t, pinned.Pinned)
+		}
+		select {
+		case got := <-client.Ch:
+			require.Equal(t, sse.EventPinned, got.Type)
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("expected a pinned event")
+		}
+		select {
+		case got := <-client.Ch:
+			t.Fatalf("unexpected %s event", got.Type)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		_, err := pins.Pin(context.Background(), create(model.Notification{}).ID, "alice")
+		require.ErrorIs(t, err, domain.ErrPinLimitReached)
+		require.NoError(t, pins.Unpin(context.Background(), notification.ID))
+		<-client.Ch
+	})
+
+	t.Run("rejects expired notifications", func(t *testing.T) {
+		expired := now.Add(-time.Minute)
+		_, err := pins.Pin(context.Background(), create(model.Notification{ExpiresAt: &expired}).ID, "alice")
+		require.ErrorIs(t, err, domain.ErrNotificationNotLive)
+	})
+}
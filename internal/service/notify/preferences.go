@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// PreferenceService stores per-user mutes and type opt-outs and pushes
+// changes to the user's open connections on this instance. Connections on
+// other instances pick them up when they reconnect.
+type PreferenceService struct {
+	repo repository.PreferenceRepository
+	hub  *sse.Hub
+	log  *zap.Logger
+}
+
+func NewPreferenceService(repo repository.PreferenceRepository, hub *sse.Hub, logger *zap.Logger) *PreferenceService {
+	return &PreferenceService{repo: repo, hub: hub, log: logger}
+}
+
+// Get returns the preferences of userID. Anonymous users and users without
+// stored preferences get empty preferences.
+func (s *PreferenceService) Get(ctx context.Context, userID string) (model.UserPreferences, error) {
+	if userID == "" {
+		return emptyPreferences(model.UserPreferences{}), nil
+	}
+	prefs, err := s.repo.GetUserPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return emptyPreferences(model.UserPreferences{UserID: userID}), nil
+		}
+		s.log.Error("store get user preferences failed", zap.String("user_id", userID), zap.Error(err))
+		return model.UserPreferences{}, err
+	}
+	return emptyPreferences(prefs), nil
+}
+
+// Put replaces the preferences of prefs.UserID. Mutes that already expired
+// are dropped.
+func (s *PreferenceService) Put(ctx context.Context, prefs model.UserPreferences) (model.UserPreferences, error) {
+	if err := domain.ValidatePreferences(prefs); err != nil {
+		return model.UserPreferences{}, err
+	}
+	now := time.Now().UTC()
+	active := make([]model.RoomMute, 0, len(prefs.MutedRooms))
+	for _, mute := range prefs.MutedRooms {
+		if mute.Until == nil || mute.Until.After(now) {
+			active = append(active, mute)
+		}
+	}
+	prefs.MutedRooms = active
+	prefs.UpdatedAt = now
+	prefs = emptyPreferences(prefs)
+
+	if err := s.repo.UpsertUserPreferences(ctx, prefs); err != nil {
+		s.log.Error("store upsert user preferences failed", zap.String("user_id", prefs.UserID), zap.Error(err))
+		return model.UserPreferences{}, err
+	}
+	s.hub.SetPreferences(prefs.UserID, prefs)
+	return prefs, nil
+}
+
+// FilterAllowed drops the notifications that prefs mute or opt out of.
+func FilterAllowed(prefs model.UserPreferences, notifications []model.Notification) []model.Notification {
+	now := time.Now()
+	allowed := make([]model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if domain.PreferencesAllow(prefs, notification, now) {
+			allowed = append(allowed, notification)
+		}
+	}
+	return allowed
+}
+
+// emptyPreferences replaces nil lists so they encode as [] rather than null.
+func emptyPreferences(prefs model.UserPreferences) model.UserPreferences {
+	if prefs.MutedRooms == nil {
+		prefs.MutedRooms = []model.RoomMute{}
+	}
+	if prefs.OptOutTypes == nil {
+		prefs.OptOutTypes = []string{}
+	}
+	return prefs
+}
@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/repository"
+)
+
+const (
+	retentionPurgeInterval  = 5 * time.Minute
+	retentionPurgeBatchSize = 500
+)
+
+var notificationsPurged = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "notifications_purged_total",
+	Help: "Notifications permanently deleted by the retention purger.",
+})
+
+// RetentionPurger periodically deletes notifications that are past the
+// retention rules. Rooms with retention settings in the room registry use
+// those; every other room and type takes the first matching rule from
+// RETENTION_RULES, falling back to the global max age and per-room count.
+type RetentionPurger struct {
+	store repository.RetentionRepository
+	rooms repository.RoomRepository
+	rules []config.RetentionRule
+	log   *zap.Logger
+}
+
+func NewRetentionPurger(cfg *config.Config, store repository.RetentionRepository, rooms repository.RoomRepository, logger *zap.Logger) *RetentionPurger {
+	rules := append([]config.RetentionRule(nil), cfg.RetentionRules...)
+	if cfg.RetentionMaxAge > 0 || cfg.RetentionMaxPerRoom > 0 {
+		rules = append(rules, config.RetentionRule{Room: "*", Type: "*", MaxAge: cfg.RetentionMaxAge, MaxCount: cfg.RetentionMaxPerRoom})
+	}
+	return &RetentionPurger{store: store, rooms: rooms, rules: rules, log: logger}
+}
+
+func (p *RetentionPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(retentionPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *RetentionPurger) purge(ctx context.Context) {
+	rules, err := p.currentRules(ctx)
+	if err != nil {
+		p.log.Error("list rooms failed", zap.Error(err))
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+	scopes, err := p.scopes(ctx, rules, time.Now().UTC())
+	if err != nil {
+		p.log.Error("list room types failed", zap.Error(err))
+		return
+	}
+	var total int64
+	for _, scope := range scopes {
+		for ctx.Err() == nil {
+			purged, err := p.store.PurgeNotifications(ctx, scope, retentionPurgeBatchSize)
+			if err != nil {
+				p.log.Error("purge notifications failed", zap.String("room", scope.Room), zap.Error(err))
+				break
+			}
+			total += purged
+			notificationsPurged.Add(float64(purged))
+			if purged < retentionPurgeBatchSize {
+				break
+			}
+		}
+	}
+	if total > 0 {
+		p.log.Info("notifications purged", zap.Int64("count", total))
+	}
+}
+
+// scopes groups the stored room and type pairs by the rule that applies to
+// them, so a room-wide count limit covers every type without its own rule.
+func (p *RetentionPurger) scopes(ctx context.Context, rules []config.RetentionRule, now time.Time) ([]repository.PurgeScope, error) {
+	pairs, err := p.store.ListRoomTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	type scopeKey struct {
+		room string
+		rule int
+	}
+	grouped := make(map[scopeKey]*repository.PurgeScope)
+	var keys []scopeKey
+	for _, pair := range pairs {
+		index, ok := matchRetentionRule(rules, pair.Room, pair.Type)
+		if !ok {
+			continue
+		}
+		rule := rules[index]
+		if rule.MaxAge <= 0 && rule.MaxCount <= 0 {
+			continue
+		}
+		key := scopeKey{room: pair.Room, rule: index}
+		scope := grouped[key]
+		if scope == nil {
+			scope = &repository.PurgeScope{Room: pair.Room, Keep: rule.MaxCount}
+			if rule.MaxAge > 0 {
+				scope.Before = now.Add(-rule.MaxAge)
+			}
+			grouped[key] = scope
+			keys = append(keys, key)
+		}
+		scope.Types = append(scope.Types, pair.Type)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].room != keys[j].room {
+			return keys[i].room < keys[j].room
+		}
+		return keys[i].rule < keys[j].rule
+	})
+	scopes := make([]repository.PurgeScope, len(keys))
+	for i, key := range keys {
+		scopes[i] = *grouped[key]
+		sort.Strings(scopes[i].Types)
+	}
+	return scopes, nil
+}
+
+// currentRules puts a rule for every registered room with retention settings
+// ahead of the configured rules.
+func (p *RetentionPurger) currentRules(ctx context.Context) ([]config.RetentionRule, error) {
+	rooms, err := p.rooms.ListRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rules []config.RetentionRule
+	for _, room := range rooms {
+		if room.RetentionDays > 0 || room.RetentionMaxCount > 0 {
+			rules = append(rules, config.RetentionRule{Room: room.Name, Type: "*", MaxAge: room.RetentionMaxAge(), MaxCount: room.RetentionMaxCount})
+		}
+	}
+	return append(rules, p.rules...), nil
+}
+
+func matchRetentionRule(rules []config.RetentionRule, room, notificationType string) (int, bool) {
+	for i, rule := range rules {
+		if rule.Type != "*" && rule.Type != notificationType {
+			continue
+		}
+		if ok, _ := path.Match(rule.Room, room); ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
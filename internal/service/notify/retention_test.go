@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/store/memory"
+)
+
+func TestRetentionPurger(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New(zap.NewNop())
+	create := func(room, notificationType, title string, createdAt time.Time) {
+		_, err := store.CreateNotification(ctx, model.Notification{Room: room, Type: notificationType, Title: title, Body: "body", CreatedAt: createdAt})
+		require.NoError(t, err)
+	}
+	now := time.Now().UTC()
+	for _, title := range []string{"info 1", "info 2", "info 3"} {
+		create("ops", domain.NotificationTypeInfo, title, now)
+	}
+	create("ops", domain.NotificationTypeWarning, "warning 1", now)
+	create("ops", domain.NotificationTypeWarning, "warning 2", now)
+	stale, err := store.CreateNotification(ctx, model.Notification{Room: "dev", Type: domain.NotificationTypeInfo, Title: "stale", Body: "body", CreatedAt: now.Add(-48 * time.Hour)})
+	require.NoError(t, err)
+	// Rows hanging off a purged notification go with it.
+	_, err = store.PinNotification(ctx, model.NotificationPin{NotificationID: stale.ID, Room: "dev", PinnedAt: now}, 0)
+	require.NoError(t, err)
+	require.NoError(t, store.AddNotificationDeliveries(ctx, []model.NotificationDelivery{{NotificationID: stale.ID, UserID: "alice", Enqueued: 1, UpdatedAt: now}}))
+	require.NoError(t, store.CreateEscalation(ctx, model.Escalation{NotificationID: stale.ID, Policy: "oncall", Status: domain.EscalationPending, DueAt: now, CreatedAt: now, UpdatedAt: now}))
+	create("dev", domain.NotificationTypeInfo, "fresh", now)
+	for range 3 {
+		create("audit", domain.NotificationTypeInfo, "audit", now.Add(-48*time.Hour))
+	}
+	for _, title := range []string{"alert 1", "alert 2", "alert 3"} {
+		create("alerts", domain.NotificationTypeWarning, title, now)
+	}
+	// Registered room settings take precedence over the configured rules.
+	_, err = store.CreateRoom(ctx, model.Room{Name: "alerts", Visibility: domain.VisibilityPublic, RetentionMaxCount: 1})
+	require.NoError(t, err)
+
+	purger := NewRetentionPurger(&config.Config{
+		RetentionMaxAge:     24 * time.Hour,
+		RetentionMaxPerRoom: 2,
+		RetentionRules: []config.RetentionRule{
+			{Room: "audit", Type: "*"},
+			{Room: "ops", Type: domain.NotificationTypeWarning, MaxCount: 1},
+		},
+	}, store, store, zap.NewNop())
+	before := testutil.ToFloat64(notificationsPurged)
+	purger.purge(ctx)
+	require.Equal(t, float64(5), testutil.ToFloat64(notificationsPurged)-before)
+
+	titles := func(room string) []string {
+		history, err := store.ListNotifications(ctx, room, repository.ListOptions{})
+		require.NoError(t, err)
+		var got []string
+		for _, n := range history {
+			got = append(got, n.Title)
+		}
+		return got
+	}
+	require.Equal(t, []string{"warning 2", "info 3", "info 2"}, titles("ops"))
+	require.Equal(t, []string{"fresh"}, titles("dev"))
+	require.Len(t, titles("audit"), 3)
+	require.Equal(t, []string{"alert 3"}, titles("alerts"))
+
+	// Search results must not point at purged records.
+	hits, err := store.SearchNotifications(ctx, repository.SearchQuery{Text: "stale"})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	deliveries, err := store.ListNotificationDeliveries(ctx, stale.ID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+	due, err := store.ListDueEscalations(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+	// The old pin is gone, so pinning the id again adds a fresh row.
+	added, err := store.PinNotification(ctx, model.NotificationPin{NotificationID: stale.ID, Room: "dev", PinnedAt: now}, 0)
+	require.NoError(t, err)
+	require.True(t, added)
+}
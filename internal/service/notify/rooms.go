@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+// RoomPatch holds the editable fields of a room. Nil fields are left
+// unchanged.
+type RoomPatch struct {
+	DisplayName       *string
+	Description       *string
+	Owner             *string
+	Visibility        *string
+	HistoryLimit      *int
+	RetentionDays     *int
+	RetentionMaxCount *int
+}
+
+// RoomService manages the room registry. Rooms do not have to be registered
+// unless ROOMS_STRICT is set; registered rooms carry metadata and per-room
+// history and retention settings.
+type RoomService struct {
+	repo repository.RoomRepository
+	log  *zap.Logger
+}
+
+func NewRoomService(repo repository.RoomRepository, logger *zap.Logger) *RoomService {
+	return &RoomService{repo: repo, log: logger}
+}
+
+func (s *RoomService) Create(ctx context.Context, room model.Room) (model.Room, error) {
+	if room.Visibility == "" {
+		room.Visibility = domain.VisibilityPublic
+	}
+	if err := domain.ValidateRoom(room); err != nil {
+		return model.Room{}, err
+	}
+	now := time.Now().UTC()
+	room.CreatedAt = now
+	room.UpdatedAt = now
+	created, err := s.repo.CreateRoom(ctx, room)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return model.Room{}, domain.ErrRoomExists
+		}
+		s.log.Error("store create room failed", zap.String("name", room.Name), zap.Error(err))
+		return model.Room{}, err
+	}
+	return created, nil
+}
+
+func (s *RoomService) Get(ctx context.Context, name string) (model.Room, error) {
+	room, err := s.repo.GetRoom(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Room{}, domain.ErrRoomNotFound
+		}
+		s.log.Error("store get room failed", zap.String("name", name), zap.Error(err))
+		return model.Room{}, err
+	}
+	return room, nil
+}
+
+func (s *RoomService) List(ctx context.Context) ([]model.Room, error) {
+	rooms, err := s.repo.ListRooms(ctx)
+	if err != nil {
+		s.log.Error("store list rooms failed", zap.Error(err))
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (s *RoomService) Update(ctx context.Context, name string, patch RoomPatch) (model.Room, error) {
+	room, err := s.Get(ctx, name)
+	if err != nil {
+		return model.Room{}, err
+	}
+
+	if patch.DisplayName != nil {
+		room.DisplayName = *patch.DisplayName
+	}
+	if patch.Description != nil {
+		room.Description = *patch.Description
+	}
+	if patch.Owner != nil {
+		room.Owner = *patch.Owner
+	}
+	if patch.Visibility != nil {
+		room.Visibility = *patch.Visibility
+	}
+	if patch.HistoryLimit != nil {
+		room.HistoryLimit = *patch.HistoryLimit
+	}
+	if patch.RetentionDays != nil {
+		room.RetentionDays = *patch.RetentionDays
+	}
+	if patch.RetentionMaxCount != nil {
+		room.RetentionMaxCount = *patch.RetentionMaxCount
+	}
+	if err := domain.ValidateRoom(room); err != nil {
+		return model.Room{}, err
+	}
+
+	room.UpdatedAt = time.Now().UTC()
+	updated, err := s.repo.UpdateRoom(ctx, room)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Room{}, domain.ErrRoomNotFound
+		}
+		s.log.Error("store update room failed", zap.String("name", name), zap.Error(err))
+		return model.Room{}, err
+	}
+	return updated, nil
+}
+
+// Delete removes a room from the registry. Its notifications are kept and
+// age out under the global retention rules.
+func (s *RoomService) Delete(ctx context.Context, name string) error {
+	deleted, err := s.repo.DeleteRoom(ctx, name)
+	if err != nil {
+		s.log.Error("store delete room failed", zap.String("name", name), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return domain.ErrRoomNotFound
+	}
+	return nil
+}
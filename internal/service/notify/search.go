@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/search"
+)
+
+// SearchResult is a search hit with its title and body highlighted. The
+// highlights are HTML-escaped with matches wrapped in <mark>; the body is
+// shortened to an excerpt around the first match.
+type SearchResult struct {
+	Notification   model.Notification
+	Score          float64
+	TitleHighlight string
+	BodyHighlight  string
+}
+
+// SearchPage is one page of search results. NextOffset is the offset of the
+// following page, or zero on the last page.
+type SearchPage struct {
+	Results    []SearchResult
+	NextOffset int
+}
+
+// Search runs a full-text search and highlights the matches.
+func (s *Service) Search(ctx context.Context, query repository.SearchQuery) (SearchPage, error) {
+	terms := search.UniqueTerms(query.Text)
+	if len(terms) == 0 {
+		return SearchPage{}, domain.ErrInvalidSearchQuery
+	}
+	limit := max(query.Limit, 1)
+	query.Limit = limit + 1
+	hits, err := s.store.SearchNotifications(ctx, query)
+	if err != nil {
+		s.log.Error("store search notifications failed", zap.String("room", query.Room), zap.String("type", query.Type), zap.Error(err))
+		return SearchPage{}, err
+	}
+
+	var page SearchPage
+	if len(hits) > limit {
+		hits = hits[:limit]
+		page.NextOffset = query.Offset + limit
+	}
+	page.Results = make([]SearchResult, len(hits))
+	for i, hit := range hits {
+		page.Results[i] = SearchResult{
+			Notification:   hit.Notification,
+			Score:          hit.Score,
+			TitleHighlight: search.Highlight(hit.Notification.Title, terms),
+			BodyHighlight:  search.Snippet(hit.Notification.Body, terms),
+		}
+	}
+	return page, nil
+}
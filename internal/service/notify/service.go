@@ -1,29 +1,243 @@
 package notify
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
+	"sse_demo/internal/config"
 	"sse_demo/internal/domain"
+	"sse_demo/internal/i18n"
 	"sse_demo/internal/model"
 	"sse_demo/internal/repository"
 	"sse_demo/internal/sse"
 )
 
 type Service struct {
-	store repository.NotificationRepository
-	hub   *sse.Hub
-	log   *zap.Logger
+	store       repository.NotificationRepository
+	keys        repository.IdempotencyKeyRepository
+	rooms       repository.RoomRepository
+	hub         *sse.Hub
+	types       *domain.TypeRegistry
+	catalog     *i18n.Catalog
+	digests     *Digester
+	maintenance *MaintenanceService
+	escalations *EscalationService
+	hooks       *Hooks
+	retention   time.Duration
+	strictRooms bool
+	sanitize    string
+	log         *zap.Logger
 }
 
-func NewService(store repository.NotificationRepository, hub *sse.Hub, logger *zap.Logger) *Service {
-	return &Service{store: store, hub: hub, log: logger}
+func NewService(cfg *config.Config, store repository.NotificationRepository, keys repository.IdempotencyKeyRepository, rooms repository.RoomRepository, hub *sse.Hub, types *domain.TypeRegistry, catalog *i18n.Catalog, digests *Digester, maintenance *MaintenanceService, escalations *EscalationService, hooks *Hooks, logger *zap.Logger) *Service {
+	sanitize := cfg.NotificationSanitize
+	if sanitize == "" {
+		sanitize = domain.SanitizeNone
+	} else if !domain.IsValidSanitizeMode(sanitize) {
+		logger.Warn("unknown notification sanitize mode, sanitization disabled", zap.String("mode", sanitize))
+		sanitize = domain.SanitizeNone
+	}
+	s := &Service{
+		store:       store,
+		keys:        keys,
+		rooms:       rooms,
+		hub:         hub,
+		types:       types,
+		catalog:     catalog,
+		digests:     digests,
+		maintenance: maintenance,
+		escalations: escalations,
+		hooks:       hooks,
+		retention:   idempotencyRetention(cfg),
+		strictRooms: cfg.RoomsStrict,
+		sanitize:    sanitize,
+		log:         logger,
+	}
+	if escalations != nil {
+		escalations.create = s.createEscalated
+	}
+	return s
+}
+
+// Validate checks a notification against the domain rules without storing it.
+// Text is checked as it would be stored, after cleaning. Validation errors are
+// *domain.ValidationError values listing every rejected field.
+func (s *Service) Validate(notification model.Notification) error {
+	domain.CleanNotification(&notification, s.sanitize)
+	invalid := domain.ValidateNotificationFields(notification)
+	if notification.Type != "" {
+		if _, err := s.types.Validate(notification.Type, notification.Room); err != nil {
+			if errors.Is(err, domain.ErrTypeNotAllowedInRoom) {
+				invalid.Add("type", fmt.Errorf("%w: is not allowed in this room", domain.ErrTypeNotAllowedInRoom))
+			} else {
+				invalid.Add("type", fmt.Errorf("%w: must be one of: %s", domain.ErrInvalidNotificationType, strings.Join(s.types.Names(), ", ")))
+			}
+		}
+	}
+	if notification.Severity != "" && !domain.IsValidSeverity(notification.Severity) {
+		invalid.Add("severity", fmt.Errorf("%w: must be one of: low, normal, high, critical", domain.ErrInvalidSeverity))
+	}
+	if notification.Priority != "" && !domain.IsValidPriority(notification.Priority) {
+		invalid.Add("priority", fmt.Errorf("%w: must be one of: low, normal, high", domain.ErrInvalidPriority))
+	}
+	if notification.DedupID != "" && !domain.IsValidIdempotencyKey(notification.DedupID) {
+		invalid.Add("dedup_id", fmt.Errorf("%w: must be 1-255 printable ASCII characters", domain.ErrInvalidIdempotencyKey))
+	}
+	if err := domain.ValidateData(notification.Data); err != nil {
+		invalid.Add("data", err)
+	}
+	if err := domain.ValidateLink(notification.Link); err != nil {
+		invalid.Add("link", err)
+	}
+	if err := domain.ValidateActions(notification.Actions); err != nil {
+		invalid.Add("actions", err)
+	}
+	if err := domain.ValidateCollapseKey(notification.CollapseKey); err != nil {
+		invalid.Add("collapse_key", err)
+	}
+	for locale := range notification.Localizations {
+		if i18n.Normalize(locale) == "" {
+			invalid.Add("localizations", fmt.Errorf("%w: %q is not a valid locale", domain.ErrInvalidLocale, locale))
+			break
+		}
+	}
+	if notification.TemplateKey != "" && !s.catalog.Has(notification.TemplateKey) {
+		invalid.Add("template_key", fmt.Errorf("%w: is not a known template", domain.ErrUnknownTemplate))
+	}
+	if len(notification.TemplateParams) > 0 {
+		s.checkRendered(invalid, notification)
+	}
+	return invalid.Err()
 }
 
+// checkRendered substitutes the template params into every variant a
+// subscriber may be sent, so params cannot push a title or body past its
+// limit.
+func (s *Service) checkRendered(invalid *domain.ValidationError, notification model.Notification) {
+	chains := [][]string{nil}
+	for locale := range notification.Localizations {
+		chains = append(chains, []string{locale})
+	}
+	for _, locale := range s.catalog.Locales(notification.TemplateKey) {
+		chains = append(chains, []string{locale})
+	}
+	var title, body bool
+	for _, chain := range chains {
+		rendered := s.catalog.Render(notification, chain)
+		if !title && utf8.RuneCountInString(rendered.Title) > domain.MaxTitleLength {
+			invalid.Add("template_params", fmt.Errorf("%w: rendered title must not exceed %d characters", domain.ErrInvalidNotification, domain.MaxTitleLength))
+			title = true
+		}
+		if !body && utf8.RuneCountInString(rendered.Body) > domain.MaxBodyLength {
+			invalid.Add("template_params", fmt.Errorf("%w: rendered body must not exceed %d characters", domain.ErrInvalidNotification, domain.MaxBodyLength))
+			body = true
+		}
+	}
+}
+
+// CheckRoom rejects rooms that are not registered when ROOMS_STRICT is set.
+// Without strict mode every room is accepted.
+func (s *Service) CheckRoom(ctx context.Context, room string) error {
+	if !s.strictRooms {
+		return nil
+	}
+	if _, err := s.rooms.GetRoom(ctx, room); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.ErrRoomNotFound
+		}
+		s.log.Error("store get room failed", zap.String("room", room), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create cleans, validates, stores and broadcasts a notification, running the
+// create hooks around it. Retries carrying the same DedupID return the
+// original notification instead of a new one and do not run the hooks again.
 func (s *Service) Create(ctx context.Context, notification model.Notification) (model.Notification, error) {
-	if !domain.IsValidNotificationType(notification.Type) {
-		return model.Notification{}, domain.ErrInvalidNotificationType
+	created, _, err := s.CreateIdempotent(ctx, notification)
+	return created, err
+}
+
+// CreateIdempotent behaves like Create and additionally reports whether the
+// result is a replay of an earlier request with the same DedupID. A replay
+// whose original was deleted or purged fails with
+// domain.ErrIdempotentNotificationGone rather than creating it again.
+func (s *Service) CreateIdempotent(ctx context.Context, notification model.Notification) (model.Notification, bool, error) {
+	if err := s.admit(ctx, &notification); err != nil {
+		return model.Notification{}, false, err
+	}
+	if err := s.CheckRoom(ctx, notification.Room); err != nil {
+		return model.Notification{}, false, err
+	}
+	if notification.DedupID == "" {
+		created, err := s.create(ctx, notification)
+		return created, false, err
+	}
+
+	now := time.Now().UTC()
+	existing, claimed, err := s.keys.ClaimIdempotencyKey(ctx, notification.DedupID, now, now.Add(-s.retention), now.Add(-idempotencyClaimLease))
+	if err != nil {
+		s.log.Error("store claim idempotency key failed", zap.String("room", notification.Room), zap.Error(err))
+		return model.Notification{}, false, err
+	}
+	if !claimed {
+		if existing.NotificationID == 0 {
+			return model.Notification{}, false, domain.ErrIdempotencyKeyInProgress
+		}
+		original, err := s.store.GetNotification(ctx, existing.NotificationID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.Notification{}, false, domain.ErrIdempotentNotificationGone
+		}
+		if err != nil {
+			s.log.Error("store get notification failed", zap.Int64("id", existing.NotificationID), zap.Error(err))
+			return model.Notification{}, false, err
+		}
+		return original, true, nil
+	}
+
+	created, err := s.create(ctx, notification)
+	if err != nil {
+		if releaseErr := s.keys.ReleaseIdempotencyKey(context.WithoutCancel(ctx), notification.DedupID); releaseErr != nil {
+			s.log.Error("store release idempotency key failed", zap.Error(releaseErr))
+		}
+		return model.Notification{}, false, err
+	}
+	if err := s.keys.CompleteIdempotencyKey(context.WithoutCancel(ctx), notification.DedupID, created.ID); err != nil {
+		// The notification is already stored and broadcast; a retry within
+		// the claim lease sees the key as in progress, a later one creates
+		// the notification again.
+		s.log.Error("store complete idempotency key failed", zap.Int64("id", created.ID), zap.Error(err))
+	}
+	return created, false, nil
+}
+
+func (s *Service) create(ctx context.Context, notification model.Notification) (model.Notification, error) {
+	return s.insert(ctx, notification, true)
+}
+
+// createEscalated admits and creates an escalated copy like Create does, but
+// does not track the copy for escalation itself.
+func (s *Service) createEscalated(ctx context.Context, notification model.Notification) (model.Notification, error) {
+	if err := s.admit(ctx, &notification); err != nil {
+		return model.Notification{}, err
+	}
+	if err := s.CheckRoom(ctx, notification.Room); err != nil {
+		return model.Notification{}, err
 	}
+	return s.insert(ctx, notification, false)
+}
+
+// insert stores, delivers and runs the after-create hooks for an admitted
+// notification; track starts its escalation if a policy covers it.
+func (s *Service) insert(ctx context.Context, notification model.Notification, track bool) (model.Notification, error) {
+	s.prepare(&notification)
 	created, err := s.store.CreateNotification(ctx, notification)
 	if err != nil {
 		s.log.Error("store create notification failed",
@@ -34,15 +248,86 @@ func (s *Service) Create(ctx context.Context, notification model.Notification) (
 		)
 		return model.Notification{}, err
 	}
-	s.hub.Broadcast(created)
+	if track {
+		s.escalations.Track(ctx, created)
+	}
+	s.deliver(created)
+	s.hooks.afterCreate(ctx, created)
 	return created, nil
 }
 
-func (s *Service) ListHistory(ctx context.Context, room string, limit int) ([]model.Notification, error) {
-	history, err := s.store.ListNotifications(ctx, room, limit)
-	if err != nil {
-		s.log.Error("store list notifications failed", zap.String("room", room), zap.Int("limit", limit), zap.Error(err))
-		return nil, err
+// admit cleans and validates a notification and runs the before-create
+// hooks. Hooks only see valid notifications, and whatever they change is
+// cleaned and validated again.
+func (s *Service) admit(ctx context.Context, notification *model.Notification) error {
+	domain.CleanNotification(notification, s.sanitize)
+	if err := s.Validate(*notification); err != nil {
+		return err
 	}
-	return history, nil
+	if !s.hooks.hasBefore() {
+		return nil
+	}
+	if err := s.hooks.beforeCreate(ctx, notification); err != nil {
+		return err
+	}
+	domain.CleanNotification(notification, s.sanitize)
+	return s.Validate(*notification)
+}
+
+// deliver broadcasts a stored notification unless a maintenance window or a
+// digest rule holds it back.
+func (s *Service) deliver(notification model.Notification) {
+	if s.maintenance.Hold(notification) || s.digests.Add(notification) {
+		return
+	}
+	s.hub.Broadcast(notification)
+}
+
+// Held reports whether a maintenance window or a digest rule currently holds
+// back live delivery of the notification. Replays leave such notifications
+// out so that a resync shows what the live stream would have.
+func (s *Service) Held(notification model.Notification) bool {
+	return s.maintenance.Holds(notification.ID) || s.digests.Holds(notification.ID)
+}
+
+// prepare normalizes a validated notification before it is stored.
+func (s *Service) prepare(notification *model.Notification) {
+	notification.Localizations = i18n.NormalizeLocalizations(notification.Localizations)
+	if string(bytes.TrimSpace(notification.Data)) == "null" {
+		notification.Data = nil
+	}
+	s.applyTypeDefaults(notification)
+}
+
+// applyTypeDefaults fills severity and expiry from the registered type
+// metadata when the producer did not set them. Priority follows severity
+// unless it was given explicitly.
+func (s *Service) applyTypeDefaults(notification *model.Notification) {
+	notificationType, _ := s.types.Lookup(notification.Type)
+	if notification.Severity == "" {
+		notification.Severity = notificationType.DefaultSeverity
+	}
+	if notification.Severity == "" {
+		notification.Severity = domain.SeverityNormal
+	}
+	if notification.Priority == "" {
+		notification.Priority = domain.DefaultPriority(notification.Severity)
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now().UTC()
+	}
+	if notification.ExpiresAt == nil && notificationType.DefaultTTLSeconds > 0 {
+		expiresAt := notification.CreatedAt.Add(notificationType.DefaultTTL())
+		notification.ExpiresAt = &expiresAt
+	}
+}
+
+// TypeNames returns the currently registered notification types.
+func (s *Service) TypeNames() []string {
+	return s.types.Names()
+}
+
+// Localize renders a notification for a subscriber's locale fallback chain.
+func (s *Service) Localize(notification model.Notification, chain []string) model.Notification {
+	return s.catalog.Render(notification, chain)
 }
@@ -3,15 +3,20 @@ package notify
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"sse_demo/internal/config"
 	"sse_demo/internal/domain"
+	"sse_demo/internal/i18n"
 	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
 	"sse_demo/internal/sse"
+	"sse_demo/internal/store/memory"
 )
 
 type repoMock struct {
@@ -23,16 +28,41 @@ func (m *repoMock) CreateNotification(ctx context.Context, n model.Notification)
 	return args.Get(0).(model.Notification), args.Error(1)
 }
 
-func (m *repoMock) ListNotifications(ctx context.Context, room string, limit int) ([]model.Notification, error) {
-	args := m.Called(ctx, room, limit)
+func (m *repoMock) CreateNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	args := m.Called(ctx, notifications)
 	return args.Get(0).([]model.Notification), args.Error(1)
 }
 
+func (m *repoMock) GetNotification(ctx context.Context, id int64) (model.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) UpdateNotification(ctx context.Context, n model.Notification) (model.Notification, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) DeleteNotification(ctx context.Context, id int64, deletedAt time.Time) (model.Notification, error) {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Get(0).(model.Notification), args.Error(1)
+}
+
+func (m *repoMock) ListNotifications(ctx context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
+	args := m.Called(ctx, room, opts)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (m *repoMock) SearchNotifications(ctx context.Context, query repository.SearchQuery) ([]model.SearchHit, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.SearchHit), args.Error(1)
+}
+
 func TestServiceCreate(t *testing.T) {
 	t.Run("invalid type", func(t *testing.T) {
 		repo := &repoMock{}
 		hub := sse.NewHub()
-		svc := NewService(repo, hub, zap.NewNop())
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 
 		_, err := svc.Create(context.Background(), model.Notification{
 			Room:  "room-1",
@@ -49,7 +79,7 @@ func TestServiceCreate(t *testing.T) {
 		repo := &repoMock{}
 		repo.On("CreateNotification", mock.Anything, mock.Anything).Return(model.Notification{}, storeErr).Once()
 		hub := sse.NewHub()
-		svc := NewService(repo, hub, zap.NewNop())
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 
 		_, err := svc.Create(context.Background(), model.Notification{
 			Room:  "room-1",
@@ -70,7 +100,7 @@ func TestServiceCreate(t *testing.T) {
 
 		client := &sse.Client{
 			Room: "room-1",
-			Ch:   make(chan model.Notification, 1),
+			Ch:   make(chan sse.Event, 1),
 		}
 		hub.Register(client)
 		defer hub.Unregister(client)
@@ -83,7 +113,7 @@ func TestServiceCreate(t *testing.T) {
 			Title: "title",
 			Body:  "body",
 		}, nil).Once()
-		svc := NewService(repo, hub, zap.NewNop())
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 
 		created, err := svc.Create(context.Background(), model.Notification{
 			Room:  "room-1",
@@ -97,38 +127,199 @@ func TestServiceCreate(t *testing.T) {
 
 		select {
 		case got := <-client.Ch:
-			require.Equal(t, int64(42), got.ID)
-			require.Equal(t, "room-1", got.Room)
-			require.Equal(t, domain.NotificationTypeInfo, got.Type)
+			require.Equal(t, sse.EventNotification, got.Type)
+			require.Equal(t, int64(42), got.Notification.ID)
+			require.Equal(t, "room-1", got.Notification.Room)
+			require.Equal(t, domain.NotificationTypeInfo, got.Notification.Type)
 		case <-time.After(200 * time.Millisecond):
 			t.Fatalf("expected broadcast to client")
 		}
 	})
+
+	t.Run("dedup id replays original", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{IdempotencyRetention: time.Hour}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		notification := model.Notification{
+			Room:    "room-1",
+			Type:    domain.NotificationTypeInfo,
+			Title:   "title",
+			Body:    "body",
+			DedupID: "msg-1",
+		}
+
+		created, replayed, err := svc.CreateIdempotent(context.Background(), notification)
+		require.NoError(t, err)
+		require.False(t, replayed)
+
+		again, replayed, err := svc.CreateIdempotent(context.Background(), notification)
+		require.NoError(t, err)
+		require.True(t, replayed)
+		require.Equal(t, created.ID, again.ID)
+
+		history, err := repo.ListNotifications(context.Background(), "room-1", repository.ListOptions{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+	})
+
+	t.Run("dedup id of a deleted notification is gone", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{IdempotencyRetention: time.Hour}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		notification := model.Notification{Room: "room-1", Type: domain.NotificationTypeInfo, Title: "title", Body: "body", DedupID: "msg-1"}
+
+		created, _, err := svc.CreateIdempotent(context.Background(), notification)
+		require.NoError(t, err)
+		_, err = repo.DeleteNotification(context.Background(), created.ID, time.Now().UTC())
+		require.NoError(t, err)
+
+		_, _, err = svc.CreateIdempotent(context.Background(), notification)
+		require.ErrorIs(t, err, domain.ErrIdempotentNotificationGone)
+	})
+
+	t.Run("abandoned claim is reclaimed after its lease", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{IdempotencyRetention: time.Hour}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		notification := model.Notification{Room: "room-1", Type: domain.NotificationTypeInfo, Title: "title", Body: "body", DedupID: "msg-1"}
+
+		now := time.Now().UTC()
+		_, claimed, err := repo.ClaimIdempotencyKey(context.Background(), "msg-1", now, now.Add(-time.Hour), now.Add(-idempotencyClaimLease))
+		require.NoError(t, err)
+		require.True(t, claimed)
+		_, _, err = svc.CreateIdempotent(context.Background(), notification)
+		require.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+
+		// A claim older than the lease that never completed is free again.
+		stale := now.Add(-2 * idempotencyClaimLease)
+		require.NoError(t, repo.ReleaseIdempotencyKey(context.Background(), "msg-1"))
+		_, claimed, err = repo.ClaimIdempotencyKey(context.Background(), "msg-1", stale, stale.Add(-time.Hour), stale.Add(-idempotencyClaimLease))
+		require.NoError(t, err)
+		require.True(t, claimed)
+		created, replayed, err := svc.CreateIdempotent(context.Background(), notification)
+		require.NoError(t, err)
+		require.False(t, replayed)
+		require.NotZero(t, created.ID)
+	})
+
+	t.Run("collapse key supersedes earlier notification", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		create := func(title, key string) model.Notification {
+			created, err := svc.Create(context.Background(), model.Notification{
+				Room:        "room-1",
+				Type:        domain.NotificationTypeInfo,
+				Title:       title,
+				Body:        "body",
+				CollapseKey: key,
+			})
+			require.NoError(t, err)
+			return created
+		}
+
+		running := create("build running", "build-7")
+		other := create("unrelated", "")
+		passed := create("build passed", "build-7")
+		require.Zero(t, running.Replaces)
+		require.Equal(t, running.ID, passed.Replaces)
+
+		history, err := svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, passed.ID, history[0].ID)
+		require.Equal(t, other.ID, history[1].ID)
+	})
+
+	t.Run("invalid dedup id", func(t *testing.T) {
+		repo := &repoMock{}
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+
+		_, err := svc.Create(context.Background(), model.Notification{
+			Room:    "room-1",
+			Type:    domain.NotificationTypeInfo,
+			Title:   "title",
+			Body:    "body",
+			DedupID: "bad\nkey",
+		})
+		require.ErrorIs(t, err, domain.ErrInvalidIdempotencyKey)
+		repo.AssertNotCalled(t, "CreateNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("template params are sanitized and bounded after rendering", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		catalog := i18n.NewCatalogFromMap(map[string]map[string]model.LocalizedContent{
+			"deploy": {"en": {Title: "Deploy {{service}} {{service}}", Body: "{{service}} is live"}},
+		})
+		svc := NewService(&config.Config{NotificationSanitize: domain.SanitizeHTML}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), catalog, nil, nil, nil, nil, zap.NewNop())
+		notification := model.Notification{
+			Room:           "room-1",
+			Type:           domain.NotificationTypeInfo,
+			Title:          "Deploy",
+			Body:           "body",
+			TemplateKey:    "deploy",
+			TemplateParams: map[string]string{"service": "<script>api</script>"},
+		}
+
+		created, err := svc.Create(context.Background(), notification)
+		require.NoError(t, err)
+		require.Equal(t, "api", created.TemplateParams["service"])
+		require.Equal(t, "<script>api</script>", notification.TemplateParams["service"])
+
+		// Each value fits on its own, but the template uses it twice.
+		notification.TemplateParams = map[string]string{"service": strings.Repeat("a", 200)}
+		_, err = svc.Create(context.Background(), notification)
+		require.ErrorContains(t, err, "template_params rendered title must not exceed 255 characters")
+	})
 }
 
 func TestServiceListHistory(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		expected := []model.Notification{{ID: 1, Room: "room-1", Type: domain.NotificationTypeInfo}}
 		repo := &repoMock{}
-		repo.On("ListNotifications", mock.Anything, "room-1", 10).Return(expected, nil).Once()
+		repo.On("ListNotifications", mock.Anything, "room-1", repository.ListOptions{Limit: 10}).Return(expected, nil).Once()
 		hub := sse.NewHub()
-		svc := NewService(repo, hub, zap.NewNop())
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 
-		got, err := svc.ListHistory(context.Background(), "room-1", 10)
+		got, err := svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10})
 		require.NoError(t, err)
 		require.Len(t, got, 1)
 		require.Equal(t, expected[0].ID, got[0].ID)
 		repo.AssertExpectations(t)
 	})
 
+	t.Run("min priority", func(t *testing.T) {
+		repo := memory.New(zap.NewNop())
+		svc := NewService(&config.Config{}, repo, repo, repo, sse.NewHub(), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
+		for _, n := range []model.Notification{
+			{Title: "low", Severity: domain.SeverityLow},
+			{Title: "critical", Severity: domain.SeverityCritical},
+			{Title: "explicit", Severity: domain.SeverityLow, Priority: domain.PriorityHigh},
+			{Title: "normal"},
+		} {
+			n.Room, n.Type, n.Body = "room-1", domain.NotificationTypeInfo, "body"
+			_, err := svc.Create(context.Background(), n)
+			require.NoError(t, err)
+		}
+
+		got, err := svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10, MinPriority: domain.PriorityHigh})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, "explicit", got[0].Title)
+		require.Equal(t, "critical", got[1].Title)
+
+		got, err = svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10, MinPriority: domain.PriorityNormal})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+
+		_, err = svc.ListHistory(context.Background(), "room-1", repository.ListOptions{MinPriority: "urgent"})
+		require.ErrorIs(t, err, domain.ErrInvalidPriority)
+	})
+
 	t.Run("store error", func(t *testing.T) {
 		storeErr := errors.New("list failed")
 		repo := &repoMock{}
-		repo.On("ListNotifications", mock.Anything, "room-1", 10).Return([]model.Notification(nil), storeErr).Once()
+		repo.On("ListNotifications", mock.Anything, "room-1", repository.ListOptions{Limit: 10}).Return([]model.Notification(nil), storeErr).Once()
 		hub := sse.NewHub()
-		svc := NewService(repo, hub, zap.NewNop())
+		svc := NewService(&config.Config{}, repo, memory.New(zap.NewNop()), nil, hub, domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), nil, nil, nil, nil, nil, zap.NewNop())
 
-		_, err := svc.ListHistory(context.Background(), "room-1", 10)
+		_, err := svc.ListHistory(context.Background(), "room-1", repository.ListOptions{Limit: 10})
 		require.ErrorIs(t, err, storeErr)
 		repo.AssertExpectations(t)
 	})
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/sse"
+)
+
+// StateValue is one key of a state frame.
+type StateValue struct {
+	Value     json.RawMessage `json:"value"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// StateEvent is the payload of state frames. The snapshot sent on connect
+// holds every key of the room; change frames hold only the changed key, with
+// null for a deleted one.
+type StateEvent struct {
+	Room     string                 `json:"room"`
+	Snapshot bool                   `json:"snapshot,omitempty"`
+	State    map[string]*StateValue `json:"state"`
+}
+
+// StateService keeps the last value of keyed room state, such as a build
+// status, so late joiners get the current values instead of a history.
+type StateService struct {
+	repo repository.RoomStateRepository
+	hub  *sse.Hub
+	log  *zap.Logger
+}
+
+func NewStateService(repo repository.RoomStateRepository, hub *sse.Hub, logger *zap.Logger) *StateService {
+	return &StateService{repo: repo, hub: hub, log: logger}
+}
+
+// Put stores the value of key in room and broadcasts the change.
+func (s *StateService) Put(ctx context.Context, room, key string, value json.RawMessage) (model.RoomState, error) {
+	if err := domain.ValidateRoomState(key, value); err != nil {
+		return model.RoomState{}, err
+	}
+	state := model.RoomState{Room: room, Key: key, Value: bytes.TrimSpace(value), UpdatedAt: time.Now().UTC()}
+	if err := s.repo.UpsertRoomState(ctx, state); err != nil {
+		s.log.Error("store upsert room state failed", zap.String("room", room), zap.String("key", key), zap.Error(err))
+		return model.RoomState{}, err
+	}
+	s.publish(room, key, &StateValue{Value: state.Value, UpdatedAt: state.UpdatedAt})
+	return state, nil
+}
+
+// Delete removes key from room and broadcasts it as null.
+func (s *StateService) Delete(ctx context.Context, room, key string) error {
+	deleted, err := s.repo.DeleteRoomState(ctx, room, key)
+	if err != nil {
+		s.log.Error("store delete room state failed", zap.String("room", room), zap.String("key", key), zap.Error(err))
+		return err
+	}
+	if !deleted {
+		return domain.ErrRoomStateNotFound
+	}
+	s.publish(room, key, nil)
+	return nil
+}
+
+// Snapshot returns the whole state of room.
+func (s *StateService) Snapshot(ctx context.Context, room string) (StateEvent, error) {
+	states, err := s.repo.ListRoomState(ctx, room)
+	if err != nil {
+		s.log.Error("store list room state failed", zap.String("room", room), zap.Error(err))
+		return StateEvent{}, err
+	}
+	snapshot := StateEvent{Room: room, Snapshot: true, State: make(map[string]*StateValue, len(states))}
+	for _, state := range states {
+		snapshot.State[state.Key] = &StateValue{Value: state.Value, UpdatedAt: state.UpdatedAt}
+	}
+	return snapshot, nil
+}
+
+func (s *StateService) publish(room, key string, value *StateValue) {
+	s.hub.Publish(sse.Event{Type: sse.EventState, Room: room, Payload: StateEvent{Room: room, State: map[string]*StateValue{key: value}}})
+}
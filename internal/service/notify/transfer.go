@@ -0,0 +1,225 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+const (
+	exportPageSize     = 500
+	importBatchSize    = 500
+	importMaxLineBytes = 1 << 20
+)
+
+// IDMode selects how notification ids are treated on import.
+type IDMode string
+
+const (
+	// IDsRemap assigns new ids and rewrites collapse relations between the
+	// imported notifications to match.
+	IDsRemap IDMode = "remap"
+	// IDsPreserve keeps the exported ids; the import fails on a taken id.
+	IDsPreserve IDMode = "preserve"
+)
+
+// ParseIDMode maps the request value to an IDMode; empty means remap.
+func ParseIDMode(value string) (IDMode, error) {
+	switch IDMode(value) {
+	case "", IDsRemap:
+		return IDsRemap, nil
+	case IDsPreserve:
+		return IDsPreserve, nil
+	default:
+		return "", domain.ErrInvalidIDMode
+	}
+}
+
+// ImportError reports the input line an import stopped at.
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// TransferService exports notification history as JSON Lines, one
+// notification per line, and imports such dumps. Imported notifications are
+// checked like new ones: field rules, registered types and, with
+// ROOMS_STRICT, registered rooms.
+type TransferService struct {
+	store       repository.TransferRepository
+	rooms       repository.RoomRepository
+	types       *domain.TypeRegistry
+	strictRooms bool
+	log         *zap.Logger
+}
+
+func NewTransferService(cfg *config.Config, store repository.TransferRepository, rooms repository.RoomRepository, types *domain.TypeRegistry, logger *zap.Logger) *TransferService {
+	return &TransferService{store: store, rooms: rooms, types: types, strictRooms: cfg.RoomsStrict, log: logger}
+}
+
+// Export writes the notifications matching filter to w in id order and
+// returns how many were written. Writers that can flush are flushed after
+// every page so HTTP clients receive the dump as it is produced.
+func (s *TransferService) Export(ctx context.Context, w io.Writer, filter repository.ExportFilter) (int, error) {
+	encoder := json.NewEncoder(w)
+	filter.Limit = exportPageSize
+	written := 0
+	for {
+		page, err := s.store.ExportNotifications(ctx, filter)
+		if err != nil {
+			s.log.Error("store export notifications failed", zap.Int64("after_id", filter.AfterID), zap.Error(err))
+			return written, err
+		}
+		for _, notification := range page {
+			if err := encoder.Encode(notification); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		if len(page) < exportPageSize {
+			return written, nil
+		}
+		filter.AfterID = page[len(page)-1].ID
+	}
+}
+
+// Import reads a JSON Lines dump from r and stores it in batches without
+// broadcasting anything; created_at and the other timestamps are kept. Each
+// batch is atomic, so on error the batches before it stay imported and the
+// returned count says how many notifications that was.
+func (s *TransferService) Import(ctx context.Context, r io.Reader, mode IDMode) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineBytes)
+
+	imported := 0
+	// stored maps exported ids to the ids they were imported under.
+	stored := make(map[int64]int64)
+	var batch []model.Notification
+	var exportedIDs []int64
+	pending := make(map[int64]struct{})
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created, err := s.store.ImportNotifications(ctx, batch)
+		if err != nil {
+			s.log.Error("store import notifications failed", zap.Int("batch_size", len(batch)), zap.Error(err))
+			return err
+		}
+		for i, notification := range created {
+			stored[exportedIDs[i]] = notification.ID
+		}
+		imported += len(created)
+		batch, exportedIDs = batch[:0], exportedIDs[:0]
+		clear(pending)
+		return nil
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var notification model.Notification
+		if err := json.Unmarshal(text, &notification); err != nil {
+			return imported, &ImportError{Line: line, Err: err}
+		}
+		if notification.Room == "" || notification.Type == "" || notification.Title == "" || notification.Body == "" {
+			return imported, &ImportError{Line: line, Err: domain.ErrInvalidImportRecord}
+		}
+		if err := s.validate(ctx, notification); err != nil {
+			var invalid *domain.ValidationError
+			if errors.As(err, &invalid) || errors.Is(err, domain.ErrRoomNotFound) {
+				return imported, &ImportError{Line: line, Err: err}
+			}
+			return imported, err
+		}
+
+		if mode == IDsRemap {
+			// The new id of a replaced notification is only known once its
+			// batch is stored.
+			if _, ok := pending[notification.Replaces]; ok {
+				if err := flush(); err != nil {
+					return imported, err
+				}
+			}
+			exportedID := notification.ID
+			notification.ID = 0
+			notification.Replaces = stored[notification.Replaces]
+			// Set again when the replacing notification is imported.
+			notification.SupersededBy = 0
+			if exportedID != 0 {
+				pending[exportedID] = struct{}{}
+			}
+			exportedIDs = append(exportedIDs, exportedID)
+		} else {
+			exportedIDs = append(exportedIDs, notification.ID)
+		}
+		batch = append(batch, notification)
+		if len(batch) >= importBatchSize {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, &ImportError{Line: line + 1, Err: err}
+	}
+	if err := flush(); err != nil {
+		return imported, err
+	}
+	return imported, nil
+}
+
+// validate checks an imported notification the way a new one is checked. The
+// content is kept as exported, so nothing is cleaned first.
+func (s *TransferService) validate(ctx context.Context, notification model.Notification) error {
+	invalid := domain.ValidateNotificationFields(notification)
+	if _, err := s.types.Validate(notification.Type, notification.Room); err != nil {
+		invalid.Add("type", err)
+	}
+	if notification.Severity != "" && !domain.IsValidSeverity(notification.Severity) {
+		invalid.Add("severity", fmt.Errorf("%w: must be one of: low, normal, high, critical", domain.ErrInvalidSeverity))
+	}
+	if notification.Priority != "" && !domain.IsValidPriority(notification.Priority) {
+		invalid.Add("priority", fmt.Errorf("%w: must be one of: low, normal, high", domain.ErrInvalidPriority))
+	}
+	if err := invalid.Err(); err != nil {
+		return err
+	}
+	if !s.strictRooms {
+		return nil
+	}
+	if _, err := s.rooms.GetRoom(ctx, notification.Room); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.ErrRoomNotFound
+		}
+		s.log.Error("store get room failed", zap.String("room", notification.Room), zap.Error(err))
+		return err
+	}
+	return nil
+}
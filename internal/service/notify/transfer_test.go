@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/store/memory"
+)
+
+func TestTransferService(t *testing.T) {
+	ctx := context.Background()
+	source := memory.New(zap.NewNop())
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, n := range []model.Notification{
+		{Room: "ops", Title: "build running", CollapseKey: "build-7"},
+		{Room: "dev", Title: "other room"},
+		{Room: "ops", Title: "build passed", CollapseKey: "build-7"},
+	} {
+		n.Type, n.Body, n.CreatedAt = domain.NotificationTypeInfo, "body", createdAt
+		_, err := source.CreateNotification(ctx, n)
+		require.NoError(t, err)
+	}
+	transfer := newTestTransferService(source)
+
+	var dump bytes.Buffer
+	written, err := transfer.Export(ctx, &dump, repository.ExportFilter{Room: "ops"})
+	require.NoError(t, err)
+	require.Equal(t, 2, written)
+	require.Equal(t, 2, strings.Count(dump.String(), "\n"))
+
+	t.Run("remap", func(t *testing.T) {
+		target := memory.New(zap.NewNop())
+		_, err := target.CreateNotification(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "existing", Body: "body"})
+		require.NoError(t, err)
+
+		imported, err := newTestTransferService(target).Import(ctx, bytes.NewReader(dump.Bytes()), IDsRemap)
+		require.NoError(t, err)
+		require.Equal(t, 2, imported)
+
+		history, err := target.ListNotifications(ctx, "ops", repository.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, "build passed", history[0].Title)
+		require.Equal(t, createdAt, history[0].CreatedAt)
+		require.NotZero(t, history[0].Replaces)
+		require.Equal(t, "existing", history[1].Title)
+	})
+
+	t.Run("preserve", func(t *testing.T) {
+		target := memory.New(zap.NewNop())
+		imported, err := newTestTransferService(target).Import(ctx, bytes.NewReader(dump.Bytes()), IDsPreserve)
+		require.NoError(t, err)
+		require.Equal(t, 2, imported)
+
+		passed, err := target.GetNotification(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), passed.Replaces)
+
+		_, err = newTestTransferService(target).Import(ctx, bytes.NewReader(dump.Bytes()), IDsPreserve)
+		require.Error(t, err)
+
+		created, err := target.CreateNotification(ctx, model.Notification{Room: "ops", Type: domain.NotificationTypeInfo, Title: "next", Body: "body"})
+		require.NoError(t, err)
+		require.Equal(t, int64(4), created.ID)
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		target := memory.New(zap.NewNop())
+		input := dump.String() + "\n{\"room\":\"ops\"}\n"
+		imported, err := newTestTransferService(target).Import(ctx, strings.NewReader(input), IDsRemap)
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		require.Equal(t, 4, importErr.Line)
+		require.ErrorIs(t, err, domain.ErrInvalidImportRecord)
+		// The collapse relation split the dump into two batches; the first
+		// one was stored before the bad line was reached.
+		require.Equal(t, 1, imported)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		for name, line := range map[string]string{
+			"unknown type":    `{"room":"ops","type":"page","title":"t","body":"b"}`,
+			"oversized title": `{"room":"ops","type":"info","title":"` + strings.Repeat("t", domain.MaxTitleLength+1) + `","body":"b"}`,
+			"bad room":        `{"room":"ops room","type":"info","title":"t","body":"b"}`,
+		} {
+			target := memory.New(zap.NewNop())
+			imported, err := newTestTransferService(target).Import(ctx, strings.NewReader(dump.String()+line+"\n"), IDsRemap)
+			var importErr *ImportError
+			require.ErrorAs(t, err, &importErr, name)
+			require.Equal(t, 3, importErr.Line, name)
+			var invalid *domain.ValidationError
+			require.ErrorAs(t, err, &invalid, name)
+			require.Equal(t, 1, imported, name)
+		}
+	})
+
+	t.Run("preserved ids sort before existing ones", func(t *testing.T) {
+		target := memory.New(zap.NewNop())
+		_, err := target.ImportNotifications(ctx, []model.Notification{
+			{ID: 10, Room: "ops", Type: domain.NotificationTypeInfo, Title: "existing", Body: "body"},
+			{ID: 12, Room: "ops", Type: domain.NotificationTypeInfo, Title: "existing", Body: "body"},
+		})
+		require.NoError(t, err)
+
+		// The dump keeps ids 1 and 3, below the existing ones, but is
+		// numbered after them.
+		imported, err := newTestTransferService(target).Import(ctx, bytes.NewReader(dump.Bytes()), IDsPreserve)
+		require.NoError(t, err)
+		require.Equal(t, 2, imported)
+
+		page, err := target.ListNotifications(ctx, "ops", repository.ListOptions{Limit: 1, AfterSeq: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, int64(12), page[0].ID)
+		page, err = target.ListNotifications(ctx, "ops", repository.ListOptions{Limit: 1, AfterSeq: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, int64(3), page[0].ID)
+		require.Equal(t, int64(4), page[0].Seq)
+	})
+}
+
+func newTestTransferService(store repository.TransferRepository) *TransferService {
+	return NewTransferService(&config.Config{}, store, memory.New(zap.NewNop()), domain.NewTypeRegistry(domain.DefaultNotificationTypes()...), zap.NewNop())
+}
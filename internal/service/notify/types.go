@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+// typeRefreshInterval is how long a type changed through another instance
+// can take to reach this one.
+const typeRefreshInterval = 15 * time.Second
+
+// TypeService manages the notification type registry. Types come from the
+// NOTIFICATION_TYPES_PATH file (or the built-in defaults) and can be added or
+// overridden at runtime through the store-backed admin API. Changes apply
+// immediately on the instance that made them; Run reloads the stored types
+// so the other instances follow.
+type TypeService struct {
+	repo     repository.NotificationTypeRepository
+	registry *domain.TypeRegistry
+	base     map[string]model.NotificationType
+	// mu orders reloads against admin changes so a reload that listed the
+	// store before a change cannot undo it.
+	mu  sync.Mutex
+	log *zap.Logger
+}
+
+func NewTypeService(cfg *config.Config, repo repository.NotificationTypeRepository, logger *zap.Logger) (*TypeService, error) {
+	configured, err := loadConfiguredTypes(cfg.NotificationTypesPath)
+	if err != nil {
+		logger.Error("load notification types failed", zap.String("path", cfg.NotificationTypesPath), zap.Error(err))
+		return nil, err
+	}
+	base := make(map[string]model.NotificationType, len(configured))
+	for _, t := range configured {
+		base[t.Name] = t
+	}
+
+	s := &TypeService{repo: repo, registry: domain.NewTypeRegistry(configured...), base: base, log: logger}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.reload(ctx); err != nil {
+		return nil, err
+	}
+	logger.Info("notification types loaded", zap.Strings("types", s.registry.Names()))
+	return s, nil
+}
+
+func (s *TypeService) Run(ctx context.Context) {
+	ticker := time.NewTicker(typeRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed reload keeps the current registry until the next tick.
+			_ = s.reload(ctx)
+		}
+	}
+}
+
+// reload rebuilds the registry from the configured types overlaid with the
+// stored ones.
+func (s *TypeService) reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.repo.ListNotificationTypes(ctx)
+	if err != nil {
+		s.log.Error("list stored notification types failed", zap.Error(err))
+		return err
+	}
+	types := make([]model.NotificationType, 0, len(s.base)+len(stored))
+	for _, t := range s.base {
+		types = append(types, t)
+	}
+	types = append(types, stored...)
+	s.registry.Replace(types...)
+	return nil
+}
+
+// NewTypeRegistry exposes the registry maintained by the type service.
+func NewTypeRegistry(types *TypeService) *domain.TypeRegistry {
+	return types.registry
+}
+
+func (s *TypeService) List() []model.NotificationType {
+	return s.registry.List()
+}
+
+func (s *TypeService) Upsert(ctx context.Context, notificationType model.NotificationType) (model.NotificationType, error) {
+	if err := domain.ValidateNotificationType(notificationType); err != nil {
+		return model.NotificationType{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.repo.UpsertNotificationType(ctx, notificationType); err != nil {
+		s.log.Error("store upsert notification type failed", zap.String("name", notificationType.Name), zap.Error(err))
+		return model.NotificationType{}, err
+	}
+	s.registry.Set(notificationType)
+	return notificationType, nil
+}
+
+// Delete removes a type managed through the API. Types from the configuration
+// file fall back to their configured definition once the override is removed.
+func (s *TypeService) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted, err := s.repo.DeleteNotificationType(ctx, name)
+	if err != nil {
+		s.log.Error("store delete notification type failed", zap.String("name", name), zap.Error(err))
+		return err
+	}
+	base, configured := s.base[name]
+	switch {
+	case deleted && configured:
+		s.registry.Set(base)
+	case deleted:
+		s.registry.Remove(name)
+	case configured:
+		return domain.ErrConfiguredTypeReadOnly
+	default:
+		return domain.ErrNotificationTypeNotFound
+	}
+	return nil
+}
+
+func loadConfiguredTypes(path string) ([]model.NotificationType, error) {
+	if path == "" {
+		return domain.DefaultNotificationTypes(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read notification types: %w", err)
+	}
+	var types []model.NotificationType
+	if err := json.Unmarshal(raw, &types); err != nil {
+		return nil, fmt.Errorf("parse notification types: %w", err)
+	}
+	for _, t := range types {
+		if err := domain.ValidateNotificationType(t); err != nil {
+			return nil, fmt.Errorf("notification type %q: %w", t.Name, err)
+		}
+	}
+	return types, nil
+}
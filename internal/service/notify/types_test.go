@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"sse_demo/internal/config"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+	"sse_demo/internal/store/memory"
+)
+
+func TestTypeServiceReload(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New(zap.NewNop())
+	// Two instances sharing one store.
+	first, err := NewTypeService(&config.Config{}, repo, zap.NewNop())
+	require.NoError(t, err)
+	second, err := NewTypeService(&config.Config{}, repo, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = first.Upsert(ctx, model.NotificationType{Name: "deploy"})
+	require.NoError(t, err)
+	_, err = first.Upsert(ctx, model.NotificationType{Name: domain.NotificationTypeInfo, AllowedRooms: []string{"ops"}})
+	require.NoError(t, err)
+	_, ok := second.registry.Lookup("deploy")
+	require.False(t, ok)
+
+	require.NoError(t, second.reload(ctx))
+	_, ok = second.registry.Lookup("deploy")
+	require.True(t, ok)
+	info, _ := second.registry.Lookup(domain.NotificationTypeInfo)
+	require.Equal(t, []string{"ops"}, info.AllowedRooms)
+
+	require.NoError(t, first.Delete(ctx, "deploy"))
+	require.NoError(t, first.Delete(ctx, domain.NotificationTypeInfo))
+	require.NoError(t, second.reload(ctx))
+	_, ok = second.registry.Lookup("deploy")
+	require.False(t, ok)
+	info, ok = second.registry.Lookup(domain.NotificationTypeInfo)
+	require.True(t, ok, "configured types fall back to their definition")
+	require.Empty(t, info.AllowedRooms)
+}
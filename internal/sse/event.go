@@ -0,0 +1,68 @@
+package sse
+
+import (
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+)
+
+// SSE event names sent to clients.
+const (
+	EventNotification = "notification"
+	EventAction       = "notification.action"
+	EventUpdated      = "notification.updated"
+	EventDeleted      = "notification.deleted"
+	EventSummary      = "notification.summary"
+	EventPinned       = "notification.pinned"
+	EventUnpinned     = "notification.unpinned"
+	EventAcked        = "notification.acked"
+	EventEscalated    = "notification.escalated"
+	EventState        = "state"
+	// Announcement events are not tied to a room and reach every client.
+	EventAnnouncement        = "announcement"
+	EventAnnouncementDeleted = "announcement.deleted"
+)
+
+// Event is a message delivered to every client subscribed to Room. Notification
+// events carry the notification so handlers can render it per client; other
+// events carry an arbitrary JSON-serializable Payload. NotificationType names
+// the type that action, ack and escalation frames are about, so that
+// opting out of the type drops them too.
+type Event struct {
+	Type             string
+	Room             string
+	Notification     model.Notification
+	NotificationType string
+	Payload          any
+}
+
+// Priority returns the delivery priority of the event. Only frames carrying a
+// notification have one; everything else is delivered as normal.
+func (e Event) Priority() string {
+	if e.carriesNotification() && e.Notification.Priority != "" {
+		return e.Notification.Priority
+	}
+	return domain.PriorityNormal
+}
+
+func (e Event) carriesNotification() bool {
+	return e.Type == EventNotification || e.Type == EventUpdated
+}
+
+// describesNotifications reports whether the frame reports on notifications
+// without carrying one. Muting the room drops these frames as well.
+func (e Event) describesNotifications() bool {
+	switch e.Type {
+	case EventSummary, EventAction, EventAcked, EventEscalated:
+		return true
+	default:
+		return false
+	}
+}
+
+// subject returns what subscriber preferences are checked against.
+func (e Event) subject() model.Notification {
+	if e.carriesNotification() {
+		return e.Notification
+	}
+	return model.Notification{Room: e.Room, Type: e.NotificationType}
+}
@@ -3,22 +3,52 @@ package sse
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"sse_demo/internal/domain"
 	"sse_demo/internal/model"
 )
 
 type Client struct {
 	Room string
-	Ch   chan model.Notification
+	// UserID and Preferences identify the subscriber; notifications the
+	// preferences filter out are not delivered. The hub updates Preferences
+	// of registered clients through SetPreferences.
+	UserID      string
+	Preferences model.UserPreferences
+	Ch          chan Event
+	// High, when set, receives high-priority events so the handler can send
+	// them ahead of the frames already buffered in Ch.
+	High chan Event
+	// DeferLimit caps how many events quiet hours in defer mode replay when
+	// they end; Ch should hold at least that many plus the summary. Holds
+	// live with the connection, so a client that disconnects during quiet
+	// hours catches up through history instead.
+	DeferLimit int
+	// quiet collects what was held back during the user's quiet hours.
+	quiet *quietHold
+}
+
+const (
+	quietCheckInterval = 30 * time.Second
+	quietSummaryLimit  = 100
+)
+
+// quietHold is the state of a client in quiet hours: the summary so far and,
+// in defer mode, the first events to deliver once quiet hours end.
+type quietHold struct {
+	summary model.SuppressionSummary
+	held    []Event
 }
 
 type Hub struct {
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan model.Notification
+	broadcast  chan Event
 	rooms      map[string]map[*Client]struct{}
+	onDelivery func([]model.NotificationDelivery)
 	mu         sync.RWMutex
 }
 
@@ -26,7 +56,7 @@ func NewHub() *Hub {
 	return &Hub{
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
-		broadcast:  make(chan model.Notification, 64),
+		broadcast:  make(chan Event, 64),
 		rooms:      make(map[string]map[*Client]struct{}),
 	}
 }
@@ -40,20 +70,62 @@ func (h *Hub) Unregister(client *Client) {
 }
 
 func (h *Hub) Broadcast(notification model.Notification) {
-	h.broadcast <- notification
+	h.broadcast <- Event{Type: EventNotification, Room: notification.Room, Notification: notification}
+}
+
+// Publish delivers an arbitrary event to the clients of event.Room.
+func (h *Hub) Publish(event Event) {
+	h.broadcast <- event
+}
+
+// PublishAll delivers event to every registered client, once per client,
+// whatever room it is subscribed to.
+func (h *Hub) PublishAll(event Event) {
+	event.Room = ""
+	h.broadcast <- event
+}
+
+// OnDelivery sets the function that receives, after each broadcast, how many
+// clients a notification was queued to and dropped for. It is called from the
+// hub loop and must not block.
+func (h *Hub) OnDelivery(fn func([]model.NotificationDelivery)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onDelivery = fn
+}
+
+// SetPreferences applies new preferences to every connection of userID.
+func (h *Hub) SetPreferences(userID string, prefs model.UserPreferences) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, room := range h.rooms {
+		for client := range room {
+			if client.UserID == userID {
+				client.Preferences = prefs
+			}
+		}
+	}
 }
 
 func (h *Hub) Run(ctx context.Context) {
+	quietTicker := time.NewTicker(quietCheckInterval)
+	defer quietTicker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case now := <-quietTicker.C:
+			h.releaseQuiet(now)
 		case client := <-h.register:
 			h.addClient(client)
 		case client := <-h.unregister:
 			h.removeClient(client)
-		case notification := <-h.broadcast:
-			h.broadcastToRoom(notification)
+		case event := <-h.broadcast:
+			if event.Room == "" {
+				h.broadcastAll(event)
+			} else {
+				h.broadcastToRoom(event)
+			}
 		}
 	}
 }
@@ -80,23 +152,224 @@ func (h *Hub) removeClient(client *Client) {
 	}
 }
 
-func (h *Hub) broadcastToRoom(notification model.Notification) {
+func (h *Hub) broadcastToRoom(event Event) {
 	_, span := otel.Tracer("sse").Start(context.Background(), "sse.broadcast")
 	span.SetAttributes(
-		attribute.String("sse.room", notification.Room),
-		attribute.String("notification.type", notification.Type),
+		attribute.String("sse.room", event.Room),
+		attribute.String("sse.event", event.Type),
 	)
+	if event.Type == EventNotification {
+		span.SetAttributes(
+			attribute.String("notification.type", event.Notification.Type),
+			attribute.String("notification.priority", event.Priority()),
+		)
+	}
 	defer span.End()
 
-	h.mu.RLock()
-	room := h.rooms[notification.Room]
+	now := time.Now()
+	// Quiet hours state is written here, so take the write lock.
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	room := h.rooms[event.Room]
 	span.SetAttributes(attribute.Int("sse.clients", len(room)))
+	var deliveries deliveryLog
 	for client := range room {
+		if event.carriesNotification() || event.describesNotifications() {
+			if !domain.PreferencesAllow(client.Preferences, event.subject(), now) {
+				continue
+			}
+		}
+		if event.carriesNotification() && holdQuiet(client, event, now) {
+			continue
+		}
+		deliveries.deliver(client, event)
+	}
+	if event.Type == EventNotification {
+		span.SetAttributes(
+			attribute.Int("sse.enqueued", deliveries.enqueued(event.Notification.ID)),
+			attribute.Int("sse.dropped", deliveries.dropped(event.Notification.ID)),
+		)
+	}
+	h.report(deliveries)
+}
+
+func (h *Hub) broadcastAll(event Event) {
+	_, span := otel.Tracer("sse").Start(context.Background(), "sse.broadcast_all")
+	span.SetAttributes(attribute.String("sse.event", event.Type))
+	defer span.End()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	delivered := make(map[*Client]struct{})
+	var deliveries deliveryLog
+	for _, room := range h.rooms {
+		for client := range room {
+			if _, ok := delivered[client]; ok {
+				continue
+			}
+			delivered[client] = struct{}{}
+			deliveries.deliver(client, event)
+		}
+	}
+	span.SetAttributes(attribute.Int("sse.clients", len(delivered)))
+	h.report(deliveries)
+}
+
+// report hands the outcome of a pass to the delivery callback. h.mu must be
+// held.
+func (h *Hub) report(deliveries deliveryLog) {
+	if h.onDelivery != nil && len(deliveries) > 0 {
+		h.onDelivery(deliveries)
+	}
+}
+
+// holdQuiet keeps event back while the client's user is in quiet hours and
+// reports whether it did. High-priority events are always delivered.
+func holdQuiet(client *Client, event Event, now time.Time) bool {
+	if event.Priority() == domain.PriorityHigh || !domain.InQuietHours(client.Preferences.QuietHours, now) {
+		return false
+	}
+	hold := client.quiet
+	if hold == nil {
+		hold = &quietHold{summary: model.SuppressionSummary{
+			Source:      domain.SuppressionSourceQuietHours,
+			Room:        client.Room,
+			Mode:        domain.QuietHoursMode(client.Preferences.QuietHours),
+			CountByType: make(map[string]int),
+			From:        now,
+		}}
+		client.quiet = hold
+	}
+	hold.summary.To = now
+	if event.Type == EventNotification {
+		hold.summary.Count++
+		hold.summary.CountByType[event.Notification.Type]++
+		if len(hold.summary.NotificationIDs) < quietSummaryLimit {
+			hold.summary.NotificationIDs = append(hold.summary.NotificationIDs, event.Notification.ID)
+		}
+	}
+	if hold.summary.Mode == domain.SuppressionDefer && len(hold.held) < client.DeferLimit {
+		hold.held = append(hold.held, event)
+		if event.Type == EventNotification {
+			hold.summary.Deferred++
+		}
+	}
+	return true
+}
+
+// releaseQuiet ends the holds of clients whose quiet hours are over: deferred
+// events are delivered, followed by a summary of everything held back.
+func (h *Hub) releaseQuiet(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var deliveries deliveryLog
+	for _, room := range h.rooms {
+		for client := range room {
+			if client.quiet == nil || domain.InQuietHours(client.Preferences.QuietHours, now) {
+				continue
+			}
+			hold := client.quiet
+			client.quiet = nil
+			for _, event := range hold.held {
+				deliveries.deliver(client, event)
+			}
+			if hold.summary.Count > 0 {
+				deliveries.deliver(client, Event{Type: EventSummary, Room: client.Room, Payload: hold.summary})
+			}
+		}
+	}
+	h.report(deliveries)
+}
+
+// deliveryLog collects per-client delivery outcomes of notification events
+// during one hub pass. A notification evicted from a full buffer moves from
+// enqueued to dropped for that client.
+type deliveryLog []model.NotificationDelivery
+
+func (l *deliveryLog) deliver(client *Client, event Event) {
+	queued, evicted := deliver(client, event)
+	if event.Type == EventNotification {
+		delivery := model.NotificationDelivery{NotificationID: event.Notification.ID, UserID: client.UserID, Enqueued: 1}
+		if !queued {
+			delivery.Enqueued, delivery.Dropped = 0, 1
+		}
+		*l = append(*l, delivery)
+	}
+	if evicted != nil && evicted.Type == EventNotification {
+		*l = append(*l, model.NotificationDelivery{NotificationID: evicted.Notification.ID, UserID: client.UserID, Enqueued: -1, Dropped: 1})
+	}
+}
+
+func (l deliveryLog) enqueued(id int64) int {
+	total := 0
+	for _, delivery := range l {
+		if delivery.NotificationID == id {
+			total += delivery.Enqueued
+		}
+	}
+	return total
+}
+
+func (l deliveryLog) dropped(id int64) int {
+	total := 0
+	for _, delivery := range l {
+		if delivery.NotificationID == id {
+			total += delivery.Dropped
+		}
+	}
+	return total
+}
+
+// deliver queues event for a client without blocking and reports whether it
+// was queued. High-priority events go to the client's High channel when it
+// has room. When Ch is full the oldest buffered event of the lowest priority
+// below the new one is dropped to make room and returned as evicted; if every
+// buffered event ranks at least as high, the new event is dropped instead.
+func deliver(client *Client, event Event) (queued bool, evicted *Event) {
+	rank := domain.PriorityRank(event.Priority())
+	if client.High != nil && event.Priority() == domain.PriorityHigh {
+		select {
+		case client.High <- event:
+			return true, nil
+		default:
+		}
+	}
+	select {
+	case client.Ch <- event:
+		return true, nil
+	default:
+	}
+
+	// The hub is the only sender, so the buffer can be drained and refilled
+	// while the client keeps reading.
+	buffered := make([]Event, 0, cap(client.Ch))
+drain:
+	for {
+		select {
+		case queued := <-client.Ch:
+			buffered = append(buffered, queued)
+		default:
+			break drain
+		}
+	}
+	victim := -1
+	for i, queued := range buffered {
+		queuedRank := domain.PriorityRank(queued.Priority())
+		if queuedRank < rank && (victim < 0 || queuedRank < domain.PriorityRank(buffered[victim].Priority())) {
+			victim = i
+		}
+	}
+	if victim >= 0 {
+		dropped := buffered[victim]
+		evicted = &dropped
+		buffered = append(buffered[:victim], buffered[victim+1:]...)
+		buffered = append(buffered, event)
+	}
+	for _, queued := range buffered {
 		select {
-		case client.Ch <- notification:
+		case client.Ch <- queued:
 		default:
-			// Drop if the client is too slow.
 		}
 	}
-	h.mu.RUnlock()
+	return victim >= 0, evicted
 }
@@ -0,0 +1,184 @@
+package sse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+)
+
+func notificationEvent(id int64, priority string) Event {
+	return Event{Type: EventNotification, Room: "room-1", Notification: model.Notification{ID: id, Room: "room-1", Priority: priority}}
+}
+
+func drain(ch chan Event) []int64 {
+	var ids []int64
+	for {
+		select {
+		case event := <-ch:
+			ids = append(ids, event.Notification.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	t.Run("high priority uses high channel", func(t *testing.T) {
+		client := &Client{Room: "room-1", Ch: make(chan Event, 2), High: make(chan Event, 1)}
+
+		deliver(client, notificationEvent(1, domain.PriorityNormal))
+		deliver(client, notificationEvent(2, domain.PriorityHigh))
+		deliver(client, notificationEvent(3, domain.PriorityHigh))
+
+		require.Equal(t, []int64{2}, drain(client.High))
+		require.Equal(t, []int64{1, 3}, drain(client.Ch))
+	})
+
+	t.Run("full buffer drops oldest lowest priority first", func(t *testing.T) {
+		client := &Client{Room: "room-1", Ch: make(chan Event, 3)}
+
+		deliver(client, notificationEvent(1, domain.PriorityNormal))
+		deliver(client, notificationEvent(2, domain.PriorityLow))
+		deliver(client, notificationEvent(3, domain.PriorityLow))
+		deliver(client, notificationEvent(4, domain.PriorityNormal))
+
+		require.Equal(t, []int64{1, 3, 4}, drain(client.Ch))
+	})
+
+	t.Run("full buffer drops new event without lower victim", func(t *testing.T) {
+		client := &Client{Room: "room-1", Ch: make(chan Event, 2)}
+
+		deliver(client, notificationEvent(1, domain.PriorityNormal))
+		deliver(client, notificationEvent(2, domain.PriorityHigh))
+		deliver(client, notificationEvent(3, domain.PriorityNormal))
+		deliver(client, notificationEvent(4, domain.PriorityLow))
+
+		require.Equal(t, []int64{1, 2}, drain(client.Ch))
+	})
+}
+
+func TestBroadcastAppliesPreferences(t *testing.T) {
+	hub := NewHub()
+	past := time.Now().Add(-time.Minute)
+	muted := &Client{Room: "room-1", UserID: "alice", Ch: make(chan Event, 8)}
+	optedOut := &Client{Room: "room-1", UserID: "bob", Ch: make(chan Event, 8),
+		Preferences: model.UserPreferences{OptOutTypes: []string{domain.NotificationTypeInfo}}}
+	expired := &Client{Room: "room-1", UserID: "carol", Ch: make(chan Event, 8),
+		Preferences: model.UserPreferences{MutedRooms: []model.RoomMute{{Room: "room-*", Until: &past}}}}
+	for _, client := range []*Client{muted, optedOut, expired} {
+		hub.addClient(client)
+	}
+	hub.SetPreferences("alice", model.UserPreferences{MutedRooms: []model.RoomMute{{Room: "room-*"}}})
+
+	event := notificationEvent(1, domain.PriorityNormal)
+	event.Notification.Type = domain.NotificationTypeInfo
+	hub.broadcastToRoom(event)
+	hub.broadcastToRoom(Event{Type: EventDeleted, Room: "room-1", Payload: map[string]int64{"id": 1}})
+	// Frames about notifications follow the same preferences: the room mute
+	// drops all of them, the opt-out only those about its type.
+	hub.broadcastToRoom(Event{Type: EventAction, Room: "room-1", NotificationType: domain.NotificationTypeInfo})
+	hub.broadcastToRoom(Event{Type: EventEscalated, Room: "room-1", NotificationType: domain.NotificationTypeWarning})
+	hub.broadcastToRoom(Event{Type: EventSummary, Room: "room-1"})
+
+	require.Equal(t, []string{EventDeleted}, drainTypes(muted.Ch))
+	require.Equal(t, []string{EventDeleted, EventEscalated, EventSummary}, drainTypes(optedOut.Ch))
+	require.Equal(t, []string{EventNotification, EventDeleted, EventAction, EventEscalated, EventSummary}, drainTypes(expired.Ch))
+}
+
+func drainTypes(ch chan Event) []string {
+	var types []string
+	for {
+		select {
+		case event := <-ch:
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestBroadcastHoldsQuietHours(t *testing.T) {
+	hub := NewHub()
+	now := time.Now().UTC()
+	quiet := func(mode string) model.UserPreferences {
+		return model.UserPreferences{QuietHours: &model.QuietHours{
+			Start: now.Add(-time.Hour).Format("15:04"),
+			End:   now.Add(time.Hour).Format("15:04"),
+			Mode:  mode,
+		}}
+	}
+	suppressed := &Client{Room: "room-1", UserID: "alice", Ch: make(chan Event, 4), Preferences: quiet("")}
+	// Bob's cap replays one held notification; the summary counts the rest.
+	deferred := &Client{Room: "room-1", UserID: "bob", Ch: make(chan Event, 4), Preferences: quiet(domain.SuppressionDefer), DeferLimit: 1}
+	hub.addClient(suppressed)
+	hub.addClient(deferred)
+
+	hub.broadcastToRoom(notificationEvent(1, domain.PriorityNormal))
+	hub.broadcastToRoom(notificationEvent(2, domain.PriorityHigh))
+	hub.broadcastToRoom(notificationEvent(3, domain.PriorityLow))
+	require.Equal(t, []int64{2}, drain(suppressed.Ch))
+	require.Equal(t, []int64{2}, drain(deferred.Ch))
+
+	hub.releaseQuiet(now)
+	require.Empty(t, drain(deferred.Ch))
+
+	hub.releaseQuiet(now.Add(2 * time.Hour))
+	replayed := <-deferred.Ch
+	require.Equal(t, int64(1), replayed.Notification.ID)
+	deferredSummary := (<-deferred.Ch).Payload.(model.SuppressionSummary)
+	require.Equal(t, 2, deferredSummary.Count)
+	require.Equal(t, 1, deferredSummary.Deferred)
+	require.Empty(t, drain(deferred.Ch))
+	summary := <-suppressed.Ch
+	require.Equal(t, EventSummary, summary.Type)
+	payload := summary.Payload.(model.SuppressionSummary)
+	require.Equal(t, domain.SuppressionSourceQuietHours, payload.Source)
+	require.Equal(t, 2, payload.Count)
+	require.Zero(t, payload.Deferred)
+	require.Equal(t, []int64{1, 3}, payload.NotificationIDs)
+	require.Nil(t, suppressed.quiet)
+}
+
+func TestBroadcastAllReachesEveryClientOnce(t *testing.T) {
+	hub := NewHub()
+	first := &Client{Room: "room-1", Ch: make(chan Event, 4)}
+	second := &Client{Room: "room-2", Ch: make(chan Event, 4)}
+	muted := &Client{Room: "room-2", UserID: "alice", Ch: make(chan Event, 4),
+		Preferences: model.UserPreferences{MutedRooms: []model.RoomMute{{Room: "*"}}}}
+	for _, client := range []*Client{first, second, muted} {
+		hub.addClient(client)
+	}
+
+	hub.broadcastAll(Event{Type: EventAnnouncement, Payload: model.Announcement{ID: 1}})
+
+	for _, client := range []*Client{first, second, muted} {
+		require.Len(t, drain(client.Ch), 1)
+	}
+}
+
+func TestBroadcastReportsDeliveries(t *testing.T) {
+	hub := NewHub()
+	var reported []model.NotificationDelivery
+	hub.OnDelivery(func(deliveries []model.NotificationDelivery) {
+		reported = append(reported, deliveries...)
+	})
+	client := &Client{Room: "room-1", UserID: "alice", Ch: make(chan Event, 1)}
+	hub.addClient(client)
+
+	hub.broadcastToRoom(notificationEvent(1, domain.PriorityLow))
+	hub.broadcastToRoom(notificationEvent(2, domain.PriorityNormal))
+	hub.broadcastToRoom(notificationEvent(3, domain.PriorityNormal))
+
+	// 2 evicts 1 from the full buffer; 3 ranks no higher than 2 and is
+	// dropped on arrival.
+	require.Equal(t, []model.NotificationDelivery{
+		{NotificationID: 1, UserID: "alice", Enqueued: 1},
+		{NotificationID: 2, UserID: "alice", Enqueued: 1},
+		{NotificationID: 1, UserID: "alice", Enqueued: -1, Dropped: 1},
+		{NotificationID: 3, UserID: "alice", Dropped: 1},
+	}, reported)
+	require.Equal(t, []int64{2}, drain(client.Ch))
+}
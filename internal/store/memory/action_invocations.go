@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateActionInvocation(_ context.Context, invocation model.ActionInvocation) (model.ActionInvocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	invocation.ID = s.nextInvocationID
+	s.nextInvocationID++
+	if invocation.InvokedAt.IsZero() {
+		invocation.InvokedAt = time.Now().UTC()
+	}
+	s.invocations = append(s.invocations, invocation)
+	return invocation, nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateAnnouncement(_ context.Context, announcement model.Announcement) (model.Announcement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	announcement.ID = s.nextAnnounceID
+	s.nextAnnounceID++
+	s.announcements = append(s.announcements, announcement)
+	return announcement, nil
+}
+
+func (s *Store) ListActiveAnnouncements(_ context.Context, now time.Time) ([]model.Announcement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []model.Announcement
+	for _, announcement := range s.announcements {
+		if announcement.ExpiresAt.After(now) {
+			result = append(result, announcement)
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteAnnouncement(_ context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.announcements, func(announcement model.Announcement) bool { return announcement.ID == id })
+	if i < 0 {
+		return false, nil
+	}
+	s.announcements = slices.Delete(s.announcements, i, i+1)
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) AddNotificationDeliveries(_ context.Context, deliveries []model.NotificationDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range deliveries {
+		users := s.deliveries[delivery.NotificationID]
+		if users == nil {
+			users = make(map[string]model.NotificationDelivery)
+			s.deliveries[delivery.NotificationID] = users
+		}
+		stored := users[delivery.UserID]
+		delivery.Enqueued += stored.Enqueued
+		delivery.Dropped += stored.Dropped
+		users[delivery.UserID] = delivery
+	}
+	return nil
+}
+
+func (s *Store) ListNotificationDeliveries(_ context.Context, notificationID int64) ([]model.NotificationDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]model.NotificationDelivery, 0, len(s.deliveries[notificationID]))
+	for _, delivery := range s.deliveries[notificationID] {
+		result = append(result, delivery)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
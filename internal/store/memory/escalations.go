@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"sse_demo/internal/domain"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateEscalation(_ context.Context, escalation model.Escalation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.escalations[escalation.NotificationID]; !ok {
+		s.escalations[escalation.NotificationID] = escalation
+	}
+	return nil
+}
+
+func (s *Store) ListDueEscalations(_ context.Context, now time.Time, limit int) ([]model.Escalation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []model.Escalation
+	for _, escalation := range s.escalations {
+		if escalation.Status == domain.EscalationPending && !escalation.DueAt.After(now) {
+			result = append(result, escalation)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DueAt.Equal(result[j].DueAt) {
+			return result[i].DueAt.Before(result[j].DueAt)
+		}
+		return result[i].NotificationID < result[j].NotificationID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (s *Store) ClaimEscalation(_ context.Context, notificationID int64, step int, now, leaseUntil time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	escalation, ok := s.escalations[notificationID]
+	if !ok || escalation.Status != domain.EscalationPending || escalation.Step != step || escalation.DueAt.After(now) {
+		return false, nil
+	}
+	escalation.DueAt = leaseUntil
+	escalation.Attempts++
+	escalation.UpdatedAt = now
+	s.escalations[notificationID] = escalation
+	return true, nil
+}
+
+func (s *Store) AdvanceEscalation(_ context.Context, next model.Escalation) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	escalation, ok := s.escalations[next.NotificationID]
+	if !ok || escalation.Status != domain.EscalationPending {
+		return false, nil
+	}
+	escalation.Step = next.Step
+	escalation.Attempts = next.Attempts
+	escalation.Status = next.Status
+	escalation.DueAt = next.DueAt
+	escalation.UpdatedAt = next.UpdatedAt
+	s.escalations[next.NotificationID] = escalation
+	return true, nil
+}
+
+func (s *Store) AckEscalation(_ context.Context, notificationID int64, userID string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	escalation, ok := s.escalations[notificationID]
+	if !ok || escalation.Status != domain.EscalationPending {
+		return false, nil
+	}
+	escalation.Status = domain.EscalationAcked
+	escalation.AckedBy = userID
+	escalation.AckedAt = &at
+	escalation.UpdatedAt = at
+	s.escalations[notificationID] = escalation
+	return true, nil
+}
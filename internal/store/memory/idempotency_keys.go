@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) ClaimIdempotencyKey(_ context.Context, key string, now, expiredBefore, abandonedBefore time.Time) (model.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.idempotencyKeys[key]; ok {
+		abandoned := existing.NotificationID == 0 && existing.CreatedAt.Before(abandonedBefore)
+		if !existing.CreatedAt.Before(expiredBefore) && !abandoned {
+			return existing, false, nil
+		}
+	}
+	s.idempotencyKeys[key] = model.IdempotencyKey{Key: key, CreatedAt: now}
+	return model.IdempotencyKey{}, true, nil
+}
+
+func (s *Store) CompleteIdempotencyKey(_ context.Context, key string, notificationID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.idempotencyKeys[key]; ok {
+		existing.NotificationID = notificationID
+		s.idempotencyKeys[key] = existing
+	}
+	return nil
+}
+
+func (s *Store) ReleaseIdempotencyKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.idempotencyKeys, key)
+	return nil
+}
+
+func (s *Store) PurgeIdempotencyKeys(_ context.Context, before time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var purged int64
+	for key, record := range s.idempotencyKeys {
+		if limit > 0 && purged >= int64(limit) {
+			break
+		}
+		if record.CreatedAt.Before(before) {
+			delete(s.idempotencyKeys, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateMaintenanceWindow(_ context.Context, window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	window.ID = s.nextWindowID
+	s.nextWindowID++
+	s.windows = append(s.windows, window)
+	return window, nil
+}
+
+func (s *Store) ListMaintenanceWindows(_ context.Context, endsAfter time.Time) ([]model.MaintenanceWindow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []model.MaintenanceWindow
+	for _, window := range s.windows {
+		if window.EndsAt.After(endsAfter) {
+			result = append(result, window)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartsAt.Before(result[j].StartsAt) })
+	return result, nil
+}
+
+func (s *Store) DeleteMaintenanceWindow(_ context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.windows, func(window model.MaintenanceWindow) bool { return window.ID == id })
+	if i < 0 {
+		return false, nil
+	}
+	s.windows = slices.Delete(s.windows, i, i+1)
+	return true, nil
+}
@@ -8,12 +8,44 @@ import (
 )
 
 type Store struct {
-	mu      sync.Mutex
-	nextID  int64
-	records []model.Notification
-	log     *zap.Logger
+	mu               sync.Mutex
+	nextID           int64
+	records          []model.Notification
+	roomSeqs         map[string]int64
+	pins             map[int64]model.NotificationPin
+	deliveries       map[int64]map[string]model.NotificationDelivery
+	escalations      map[int64]model.Escalation
+	terms            map[string]map[int64]int
+	types            map[string]model.NotificationType
+	rooms            map[string]model.Room
+	state            map[string]map[string]model.RoomState
+	preferences      map[string]model.UserPreferences
+	nextWindowID     int64
+	nextAnnounceID   int64
+	announcements    []model.Announcement
+	windows          []model.MaintenanceWindow
+	nextInvocationID int64
+	invocations      []model.ActionInvocation
+	idempotencyKeys  map[string]model.IdempotencyKey
+	log              *zap.Logger
 }
 
 func New(logger *zap.Logger) *Store {
-	return &Store{nextID: 1, log: logger}
+	return &Store{
+		nextID:           1,
+		nextInvocationID: 1,
+		nextWindowID:     1,
+		nextAnnounceID:   1,
+		roomSeqs:         make(map[string]int64),
+		pins:             make(map[int64]model.NotificationPin),
+		deliveries:       make(map[int64]map[string]model.NotificationDelivery),
+		escalations:      make(map[int64]model.Escalation),
+		terms:            make(map[string]map[int64]int),
+		types:            make(map[string]model.NotificationType),
+		rooms:            make(map[string]model.Room),
+		state:            make(map[string]map[string]model.RoomState),
+		preferences:      make(map[string]model.UserPreferences),
+		idempotencyKeys:  make(map[string]model.IdempotencyKey),
+		log:              logger,
+	}
 }
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) ListNotificationTypes(_ context.Context) ([]model.NotificationType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]model.NotificationType, 0, len(s.types))
+	for _, t := range s.types {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (s *Store) UpsertNotificationType(_ context.Context, notificationType model.NotificationType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types[notificationType.Name] = notificationType
+	return nil
+}
+
+func (s *Store) DeleteNotificationType(_ context.Context, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.types[name]; !ok {
+		return false, nil
+	}
+	delete(s.types, name)
+	return true, nil
+}
@@ -1,38 +1,188 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"time"
 
+	"sse_demo/internal/domain"
 	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
 )
 
 func (s *Store) CreateNotification(_ context.Context, notification model.Notification) (model.Notification, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.insertLocked(notification, time.Now().UTC()), nil
+}
+
+func (s *Store) CreateNotifications(_ context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	created := make([]model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		created = append(created, s.insertLocked(notification, now))
+	}
+	return created, nil
+}
+
+// insertLocked assigns an id and room sequence, supersedes the live record
+// sharing the collapse key and appends the notification. s.mu must be held.
+func (s *Store) insertLocked(notification model.Notification, now time.Time) model.Notification {
 	notification.ID = s.nextID
 	s.nextID++
+	s.roomSeqs[notification.Room]++
+	notification.Seq = s.roomSeqs[notification.Room]
 	if notification.CreatedAt.IsZero() {
-		notification.CreatedAt = time.Now().UTC()
+		notification.CreatedAt = now
+	}
+	if notification.CollapseKey != "" {
+		for i := len(s.records) - 1; i >= 0; i-- {
+			record := &s.records[i]
+			if record.Room == notification.Room && record.CollapseKey == notification.CollapseKey &&
+				record.DeletedAt == nil && record.SupersededBy == 0 {
+				record.SupersededBy = notification.ID
+				notification.Replaces = record.ID
+				break
+			}
+		}
 	}
 	s.records = append(s.records, notification)
-	return notification, nil
+	s.indexLocked(notification)
+	return notification
+}
+
+func (s *Store) GetNotification(_ context.Context, id int64) (model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.findLocked(id)
+	if record == nil {
+		return model.Notification{}, repository.ErrNotFound
+	}
+	return *record, nil
+}
+
+func (s *Store) UpdateNotification(_ context.Context, notification model.Notification) (model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.findLocked(notification.ID)
+	if record == nil {
+		return model.Notification{}, repository.ErrNotFound
+	}
+	if notification.UpdatedAt == nil {
+		now := time.Now().UTC()
+		notification.UpdatedAt = &now
+	}
+	s.unindexLocked(*record)
+	record.Title = notification.Title
+	record.Body = notification.Body
+	record.Severity = notification.Severity
+	record.Priority = notification.Priority
+	record.Data = notification.Data
+	record.Link = notification.Link
+	record.Actions = notification.Actions
+	record.Localizations = notification.Localizations
+	record.UpdatedAt = notification.UpdatedAt
+	s.indexLocked(*record)
+	return *record, nil
 }
 
-func (s *Store) ListNotifications(_ context.Context, room string, limit int) ([]model.Notification, error) {
+func (s *Store) DeleteNotification(_ context.Context, id int64, deletedAt time.Time) (model.Notification, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	record := s.findLocked(id)
+	if record == nil {
+		return model.Notification{}, repository.ErrNotFound
+	}
+	record.DeletedAt = &deletedAt
+	return *record, nil
+}
 
+// findLocked returns the live record with the given id. s.mu must be held.
+func (s *Store) findLocked(id int64) *model.Notification {
+	for i := range s.records {
+		if s.records[i].ID == id && s.records[i].DeletedAt == nil {
+			return &s.records[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) ListNotifications(_ context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Records are kept in id order. With an after cursor the scan walks
+	// forward from it and the page is flipped to newest first at the end.
+	now := time.Now().UTC()
+	if opts.AfterSeq > 0 {
+		return s.listBySeqLocked(room, opts, now), nil
+	}
+	forward := opts.AfterID > 0
 	var result []model.Notification
-	for i := len(s.records) - 1; i >= 0; i-- {
+	for n := range s.records {
+		i := len(s.records) - 1 - n
+		if forward {
+			i = n
+		}
 		record := s.records[i]
-		if record.Room != room {
+		if !matchesList(record, room, opts, now) {
 			continue
 		}
 		result = append(result, record)
-		if limit > 0 && len(result) >= limit {
+		if opts.Limit > 0 && len(result) >= opts.Limit {
 			break
 		}
 	}
+	if forward {
+		slices.Reverse(result)
+	}
 	return result, nil
 }
+
+// listBySeqLocked lists a resync page in room sequence order, which differs
+// from id order once notifications were imported with preserved ids. The
+// caller holds s.mu.
+func (s *Store) listBySeqLocked(room string, opts repository.ListOptions, now time.Time) []model.Notification {
+	var result []model.Notification
+	for _, record := range s.records {
+		if matchesList(record, room, opts, now) {
+			result = append(result, record)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.Notification) int {
+		return cmp.Or(cmp.Compare(a.Seq, b.Seq), cmp.Compare(a.ID, b.ID))
+	})
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	slices.Reverse(result)
+	return result
+}
+
+func matchesList(record model.Notification, room string, opts repository.ListOptions, now time.Time) bool {
+	if record.Room != room || record.DeletedAt != nil || record.SupersededBy != 0 {
+		return false
+	}
+	if record.ExpiresAt != nil && !record.ExpiresAt.After(now) {
+		return false
+	}
+	if opts.MinPriority != "" && domain.PriorityRank(record.Priority) < domain.PriorityRank(opts.MinPriority) {
+		return false
+	}
+	if (opts.BeforeID > 0 && record.ID >= opts.BeforeID) || record.ID <= opts.AfterID || record.Seq <= opts.AfterSeq {
+		return false
+	}
+	if opts.Type != "" && record.Type != opts.Type {
+		return false
+	}
+	if opts.Since != nil && record.CreatedAt.Before(*opts.Since) {
+		return false
+	}
+	if opts.Until != nil && !record.CreatedAt.Before(*opts.Until) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) PinNotification(_ context.Context, pin model.NotificationPin, maxPerRoom int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pins[pin.NotificationID]; ok {
+		return false, nil
+	}
+	if maxPerRoom > 0 && s.countLivePinsLocked(pin.Room) >= maxPerRoom {
+		return false, repository.ErrLimitReached
+	}
+	s.pins[pin.NotificationID] = pin
+	return true, nil
+}
+
+// countLivePinsLocked counts the pins of room whose notification is still
+// listed. The caller holds s.mu.
+func (s *Store) countLivePinsLocked(room string) int {
+	now := time.Now().UTC()
+	count := 0
+	for _, pin := range s.pins {
+		record := s.findLocked(pin.NotificationID)
+		if pin.Room == room && record != nil && matchesList(*record, room, repository.ListOptions{}, now) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Store) UnpinNotification(_ context.Context, notificationID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pins[notificationID]; !ok {
+		return false, nil
+	}
+	delete(s.pins, notificationID)
+	return true, nil
+}
+
+func (s *Store) ListPinnedNotifications(_ context.Context, room string) ([]model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	var result []model.Notification
+	for _, pin := range s.pins {
+		record := s.findLocked(pin.NotificationID)
+		if pin.Room != room || record == nil || !matchesList(*record, room, repository.ListOptions{}, now) {
+			continue
+		}
+		notification := *record
+		pinnedAt := pin.PinnedAt
+		notification.Pinned = true
+		notification.PinnedAt = &pinnedAt
+		result = append(result, notification)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PinnedAt.Equal(*result[j].PinnedAt) {
+			return result[i].PinnedAt.After(*result[j].PinnedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	return result, nil
+}
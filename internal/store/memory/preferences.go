@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) GetUserPreferences(_ context.Context, userID string) (model.UserPreferences, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefs, ok := s.preferences[userID]
+	if !ok {
+		return model.UserPreferences{}, repository.ErrNotFound
+	}
+	return clonePreferences(prefs), nil
+}
+
+func (s *Store) UpsertUserPreferences(_ context.Context, prefs model.UserPreferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.preferences[prefs.UserID] = clonePreferences(prefs)
+	return nil
+}
+
+// clonePreferences copies the slices so callers cannot modify stored
+// preferences.
+func clonePreferences(prefs model.UserPreferences) model.UserPreferences {
+	prefs.MutedRooms = slices.Clone(prefs.MutedRooms)
+	prefs.OptOutTypes = slices.Clone(prefs.OptOutTypes)
+	if prefs.QuietHours != nil {
+		quiet := *prefs.QuietHours
+		prefs.QuietHours = &quiet
+	}
+	return prefs
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) ListRoomTypes(_ context.Context) ([]model.RoomType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[model.RoomType]struct{})
+	var pairs []model.RoomType
+	for _, record := range s.records {
+		pair := model.RoomType{Room: record.Room, Type: record.Type}
+		if _, ok := seen[pair]; ok {
+			continue
+		}
+		seen[pair] = struct{}{}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// PurgeNotifications trims the records slice and drops the pins, deliveries
+// and escalations of the purged records. Survivors are copied into a new
+// slice so the memory of purged records is released.
+func (s *Store) PurgeNotifications(_ context.Context, scope repository.PurgeScope, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Walk newest first to find the ones past the count limit, then purge
+	// the oldest of the expired first so batches match MySQL.
+	var expired []int
+	kept := 0
+	for i := len(s.records) - 1; i >= 0; i-- {
+		record := s.records[i]
+		if record.Room != scope.Room || !slices.Contains(scope.Types, record.Type) {
+			continue
+		}
+		kept++
+		overCount := scope.Keep > 0 && kept > scope.Keep
+		tooOld := !scope.Before.IsZero() && record.CreatedAt.Before(scope.Before)
+		if overCount || tooOld {
+			expired = append(expired, i)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	slices.Reverse(expired)
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	records := make([]model.Notification, 0, len(s.records)-len(expired))
+	next := 0
+	for i, record := range s.records {
+		if next < len(expired) && expired[next] == i {
+			s.unindexLocked(record)
+			delete(s.pins, record.ID)
+			delete(s.deliveries, record.ID)
+			delete(s.escalations, record.ID)
+			next++
+			continue
+		}
+		records = append(records, record)
+	}
+	s.records = records
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) CreateRoom(_ context.Context, room model.Room) (model.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rooms[room.Name]; ok {
+		return model.Room{}, repository.ErrConflict
+	}
+	s.rooms[room.Name] = room
+	return room, nil
+}
+
+func (s *Store) GetRoom(_ context.Context, name string) (model.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[name]
+	if !ok {
+		return model.Room{}, repository.ErrNotFound
+	}
+	return room, nil
+}
+
+func (s *Store) ListRooms(_ context.Context) ([]model.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]model.Room, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		result = append(result, room)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (s *Store) UpdateRoom(_ context.Context, room model.Room) (model.Room, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.rooms[room.Name]
+	if !ok {
+		return model.Room{}, repository.ErrNotFound
+	}
+	room.CreatedAt = existing.CreatedAt
+	s.rooms[room.Name] = room
+	return room, nil
+}
+
+func (s *Store) DeleteRoom(_ context.Context, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rooms[name]; !ok {
+		return false, nil
+	}
+	delete(s.rooms, name)
+	return true, nil
+}
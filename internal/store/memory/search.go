@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+	"sse_demo/internal/search"
+)
+
+// SearchNotifications scores matches with tf-idf over the inverted index, so
+// rare words weigh more than common ones, roughly like MySQL's natural
+// language mode.
+func (s *Store) SearchNotifications(_ context.Context, query repository.SearchQuery) ([]model.SearchHit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores := make(map[int64]float64)
+	for _, term := range search.UniqueTerms(query.Text) {
+		postings := s.terms[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(s.records))/float64(len(postings)))
+		for id, frequency := range postings {
+			scores[id] += float64(frequency) * idf
+		}
+	}
+
+	hits := make([]model.SearchHit, 0, len(scores))
+	for id, score := range scores {
+		record := s.recordLocked(id)
+		if record == nil || record.DeletedAt != nil || record.SupersededBy != 0 {
+			continue
+		}
+		if (query.Room != "" && record.Room != query.Room) || (query.Type != "" && record.Type != query.Type) {
+			continue
+		}
+		hits = append(hits, model.SearchHit{Notification: *record, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b model.SearchHit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Notification.ID, a.Notification.ID)
+	})
+
+	if query.Offset >= len(hits) {
+		return nil, nil
+	}
+	hits = hits[query.Offset:]
+	if query.Limit > 0 && len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+// indexLocked adds the title and body words of a notification to the search
+// index. s.mu must be held.
+func (s *Store) indexLocked(notification model.Notification) {
+	for _, term := range search.Terms(notification.Title + " " + notification.Body) {
+		postings := s.terms[term]
+		if postings == nil {
+			postings = make(map[int64]int)
+			s.terms[term] = postings
+		}
+		postings[notification.ID]++
+	}
+}
+
+// unindexLocked removes a notification from the search index. s.mu must be
+// held.
+func (s *Store) unindexLocked(notification model.Notification) {
+	for _, term := range search.UniqueTerms(notification.Title + " " + notification.Body) {
+		delete(s.terms[term], notification.ID)
+		if len(s.terms[term]) == 0 {
+			delete(s.terms, term)
+		}
+	}
+}
+
+// recordLocked finds a record by id, deleted or not. Records are kept in id
+// order. s.mu must be held.
+func (s *Store) recordLocked(id int64) *model.Notification {
+	i, ok := slices.BinarySearchFunc(s.records, id, func(record model.Notification, id int64) int {
+		return cmp.Compare(record.ID, id)
+	})
+	if !ok {
+		return nil
+	}
+	return &s.records[i]
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"sse_demo/internal/model"
+)
+
+func (s *Store) UpsertRoomState(_ context.Context, state model.RoomState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := s.state[state.Room]
+	if room == nil {
+		room = make(map[string]model.RoomState)
+		s.state[state.Room] = room
+	}
+	state.Value = slices.Clone(state.Value)
+	room[state.Key] = state
+	return nil
+}
+
+func (s *Store) ListRoomState(_ context.Context, room string) ([]model.RoomState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]model.RoomState, 0, len(s.state[room]))
+	for _, state := range s.state[room] {
+		state.Value = slices.Clone(state.Value)
+		result = append(result, state)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+func (s *Store) DeleteRoomState(_ context.Context, room, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.state[room][key]; !ok {
+		return false, nil
+	}
+	delete(s.state[room], key)
+	if len(s.state[room]) == 0 {
+		delete(s.state, room)
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) ExportNotifications(_ context.Context, filter repository.ExportFilter) ([]model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []model.Notification
+	for _, record := range s.records {
+		if record.ID <= filter.AfterID || (filter.Room != "" && record.Room != filter.Room) {
+			continue
+		}
+		if filter.Since != nil && record.CreatedAt.Before(*filter.Since) {
+			continue
+		}
+		if filter.Until != nil && !record.CreatedAt.Before(*filter.Until) {
+			continue
+		}
+		result = append(result, record)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) ImportNotifications(_ context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check every id first so a conflict leaves the store untouched, like
+	// the rolled back MySQL transaction.
+	seen := make(map[int64]struct{})
+	for _, notification := range notifications {
+		if notification.ID == 0 {
+			continue
+		}
+		if _, dup := seen[notification.ID]; dup || s.recordLocked(notification.ID) != nil {
+			return nil, fmt.Errorf("import notification %d: id already exists", notification.ID)
+		}
+		seen[notification.ID] = struct{}{}
+	}
+
+	now := time.Now().UTC()
+	imported := make([]model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.ID == 0 {
+			notification.ID = s.nextID
+		}
+		s.nextID = max(s.nextID, notification.ID+1)
+		// Like MySQL, imported notifications are numbered after the room's
+		// existing ones even when a preserved id sorts before them; resync
+		// listings follow the sequence rather than the id.
+		s.roomSeqs[notification.Room]++
+		notification.Seq = s.roomSeqs[notification.Room]
+		if notification.CreatedAt.IsZero() {
+			notification.CreatedAt = now
+		}
+		// Preserved ids may be lower than existing ones; keep records in id
+		// order for lookups.
+		i, _ := slices.BinarySearchFunc(s.records, notification.ID, func(record model.Notification, id int64) int {
+			return cmp.Compare(record.ID, id)
+		})
+		s.records = slices.Insert(s.records, i, notification)
+		s.indexLocked(notification)
+		if notification.Replaces != 0 {
+			if replaced := s.recordLocked(notification.Replaces); replaced != nil {
+				replaced.SupersededBy = notification.ID
+			}
+		}
+		imported = append(imported, notification)
+	}
+	return imported, nil
+}
@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateActionInvocation(ctx context.Context, invocation model.ActionInvocation) (model.ActionInvocation, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_action_invocation")
+	defer span.End()
+
+	if invocation.InvokedAt.IsZero() {
+		invocation.InvokedAt = time.Now().UTC()
+	}
+	result, err := s.queries.CreateActionInvocation(ctx, db.CreateActionInvocationParams{
+		NotificationID: invocation.NotificationID,
+		ActionID:       invocation.ActionID,
+		UserID:         invocation.UserID,
+		InvokedAt:      invocation.InvokedAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create action invocation failed")
+		s.log.Error("sql create action invocation failed",
+			zap.Int64("notification_id", invocation.NotificationID),
+			zap.String("action_id", invocation.ActionID),
+			zap.Error(err),
+		)
+		return model.ActionInvocation{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "last insert id failed")
+		s.log.Error("sql last insert id failed", zap.Error(err))
+		return model.ActionInvocation{}, err
+	}
+	invocation.ID = id
+	return invocation, nil
+}
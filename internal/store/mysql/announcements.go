@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateAnnouncement(ctx context.Context, announcement model.Announcement) (model.Announcement, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_announcement")
+	defer span.End()
+
+	id, err := s.queries.CreateAnnouncement(ctx, db.CreateAnnouncementParams{
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		Severity:  announcement.Severity,
+		Link:      announcement.Link,
+		CreatedBy: announcement.CreatedBy,
+		CreatedAt: announcement.CreatedAt,
+		ExpiresAt: announcement.ExpiresAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create announcement failed")
+		s.log.Error("sql create announcement failed", zap.String("title", announcement.Title), zap.Error(err))
+		return model.Announcement{}, err
+	}
+	announcement.ID = id
+	return announcement, nil
+}
+
+func (s *Store) ListActiveAnnouncements(ctx context.Context, now time.Time) ([]model.Announcement, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_active_announcements")
+	defer span.End()
+
+	rows, err := s.queries.ListActiveAnnouncements(ctx, now)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list announcements failed")
+		s.log.Error("sql list announcements failed", zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.Announcement, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.Announcement{
+			ID:        row.ID,
+			Title:     row.Title,
+			Body:      row.Body,
+			Severity:  row.Severity,
+			Link:      row.Link,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt,
+			ExpiresAt: row.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteAnnouncement(ctx context.Context, id int64) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_announcement")
+	defer span.End()
+
+	affected, err := s.queries.DeleteAnnouncement(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete announcement failed")
+		s.log.Error("sql delete announcement failed", zap.Int64("id", id), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+// AddNotificationDeliveries applies the counts in one transaction so a
+// failed flush can be retried without counting anything twice.
+func (s *Store) AddNotificationDeliveries(ctx context.Context, deliveries []model.NotificationDelivery) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.add_notification_deliveries")
+	span.SetAttributes(attribute.Int("notification.deliveries", len(deliveries)))
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "begin transaction failed")
+		s.log.Error("sql begin transaction failed", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	for _, delivery := range deliveries {
+		if err := queries.AddNotificationDelivery(ctx, db.AddNotificationDeliveryParams{
+			NotificationID: delivery.NotificationID,
+			UserID:         delivery.UserID,
+			Enqueued:       int32(delivery.Enqueued),
+			Dropped:        int32(delivery.Dropped),
+			UpdatedAt:      delivery.UpdatedAt,
+		}); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "add notification delivery failed")
+			s.log.Error("sql add notification delivery failed", zap.Int64("notification_id", delivery.NotificationID), zap.Error(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit transaction failed")
+		s.log.Error("sql commit deliveries failed", zap.Int("deliveries", len(deliveries)), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) ListNotificationDeliveries(ctx context.Context, notificationID int64) ([]model.NotificationDelivery, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_notification_deliveries")
+	defer span.End()
+
+	rows, err := s.queries.ListNotificationDeliveries(ctx, notificationID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list notification deliveries failed")
+		s.log.Error("sql list notification deliveries failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.NotificationDelivery, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.NotificationDelivery{
+			NotificationID: row.NotificationID,
+			UserID:         row.UserID,
+			Enqueued:       int(row.Enqueued),
+			Dropped:        int(row.Dropped),
+			UpdatedAt:      row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateEscalation(ctx context.Context, escalation model.Escalation) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_escalation")
+	defer span.End()
+
+	if err := s.queries.CreateEscalation(ctx, db.CreateEscalationParams{
+		NotificationID: escalation.NotificationID,
+		Policy:         escalation.Policy,
+		Step:           int32(escalation.Step),
+		Status:         escalation.Status,
+		DueAt:          escalation.DueAt,
+		CreatedAt:      escalation.CreatedAt,
+		UpdatedAt:      escalation.UpdatedAt,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create escalation failed")
+		s.log.Error("sql create escalation failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) ListDueEscalations(ctx context.Context, now time.Time, limit int) ([]model.Escalation, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_due_escalations")
+	defer span.End()
+
+	rows, err := s.queries.ListDueEscalations(ctx, db.ListDueEscalationsParams{DueAt: now, Limit: int32(limit)})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list due escalations failed")
+		s.log.Error("sql list due escalations failed", zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.Escalation, 0, len(rows))
+	for _, row := range rows {
+		escalation := model.Escalation{
+			NotificationID: row.NotificationID,
+			Policy:         row.Policy,
+			Step:           int(row.Step),
+			Attempts:       int(row.Attempts),
+			Status:         row.Status,
+			DueAt:          row.DueAt,
+			AckedBy:        row.AckedBy,
+			CreatedAt:      row.CreatedAt,
+			UpdatedAt:      row.UpdatedAt,
+		}
+		if row.AckedAt.Valid {
+			ackedAt := row.AckedAt.Time
+			escalation.AckedAt = &ackedAt
+		}
+		result = append(result, escalation)
+	}
+	return result, nil
+}
+
+func (s *Store) ClaimEscalation(ctx context.Context, notificationID int64, step int, now, leaseUntil time.Time) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.claim_escalation")
+	defer span.End()
+
+	affected, err := s.queries.ClaimEscalation(ctx, db.ClaimEscalationParams{
+		LeaseUntil:     leaseUntil,
+		UpdatedAt:      now,
+		NotificationID: notificationID,
+		Step:           int32(step),
+		Now:            now,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "claim escalation failed")
+		s.log.Error("sql claim escalation failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *Store) AdvanceEscalation(ctx context.Context, escalation model.Escalation) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.advance_escalation")
+	defer span.End()
+
+	affected, err := s.queries.AdvanceEscalation(ctx, db.AdvanceEscalationParams{
+		Step:           int32(escalation.Step),
+		Attempts:       int32(escalation.Attempts),
+		Status:         escalation.Status,
+		DueAt:          escalation.DueAt,
+		UpdatedAt:      escalation.UpdatedAt,
+		NotificationID: escalation.NotificationID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "advance escalation failed")
+		s.log.Error("sql advance escalation failed", zap.Int64("notification_id", escalation.NotificationID), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *Store) AckEscalation(ctx context.Context, notificationID int64, userID string, at time.Time) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.ack_escalation")
+	defer span.End()
+
+	affected, err := s.queries.AckEscalation(ctx, db.AckEscalationParams{
+		AckedBy:        userID,
+		AckedAt:        sql.NullTime{Time: at, Valid: true},
+		UpdatedAt:      at,
+		NotificationID: notificationID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ack escalation failed")
+		s.log.Error("sql ack escalation failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
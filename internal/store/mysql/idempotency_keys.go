@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, key string, now, expiredBefore, abandonedBefore time.Time) (model.IdempotencyKey, bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.claim_idempotency_key")
+	defer span.End()
+
+	if err := s.queries.DeleteExpiredIdempotencyKey(ctx, db.DeleteExpiredIdempotencyKeyParams{
+		IdemKey:         key,
+		ExpiredBefore:   expiredBefore,
+		AbandonedBefore: abandonedBefore,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete expired idempotency key failed")
+		s.log.Error("sql delete expired idempotency key failed", zap.Error(err))
+		return model.IdempotencyKey{}, false, err
+	}
+
+	inserted, err := s.queries.InsertIdempotencyKey(ctx, db.InsertIdempotencyKeyParams{
+		IdemKey:   key,
+		CreatedAt: now,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "insert idempotency key failed")
+		s.log.Error("sql insert idempotency key failed", zap.Error(err))
+		return model.IdempotencyKey{}, false, err
+	}
+	if inserted > 0 {
+		return model.IdempotencyKey{}, true, nil
+	}
+
+	row, err := s.queries.GetIdempotencyKey(ctx, key)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get idempotency key failed")
+		s.log.Error("sql get idempotency key failed", zap.Error(err))
+		return model.IdempotencyKey{}, false, err
+	}
+	return model.IdempotencyKey{
+		Key:            row.IdemKey,
+		NotificationID: row.NotificationID.Int64,
+		CreatedAt:      row.CreatedAt,
+	}, false, nil
+}
+
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, key string, notificationID int64) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.complete_idempotency_key")
+	defer span.End()
+
+	if err := s.queries.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		NotificationID: sql.NullInt64{Int64: notificationID, Valid: true},
+		IdemKey:        key,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "complete idempotency key failed")
+		s.log.Error("sql complete idempotency key failed", zap.Int64("notification_id", notificationID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.release_idempotency_key")
+	defer span.End()
+
+	if err := s.queries.DeleteIdempotencyKey(ctx, key); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "release idempotency key failed")
+		s.log.Error("sql release idempotency key failed", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) PurgeIdempotencyKeys(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.purge_idempotency_keys")
+	defer span.End()
+
+	purged, err := s.queries.PurgeIdempotencyKeys(ctx, db.PurgeIdempotencyKeysParams{
+		CreatedAt: before,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "purge idempotency keys failed")
+		s.log.Error("sql purge idempotency keys failed", zap.Error(err))
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, window model.MaintenanceWindow) (model.MaintenanceWindow, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_maintenance_window")
+	defer span.End()
+
+	id, err := s.queries.CreateMaintenanceWindow(ctx, db.CreateMaintenanceWindowParams{
+		Room:      window.Room,
+		Type:      window.Type,
+		Mode:      window.Mode,
+		Reason:    window.Reason,
+		StartsAt:  window.StartsAt,
+		EndsAt:    window.EndsAt,
+		CreatedAt: window.CreatedAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create maintenance window failed")
+		s.log.Error("sql create maintenance window failed", zap.String("room", window.Room), zap.Error(err))
+		return model.MaintenanceWindow{}, err
+	}
+	window.ID = id
+	return window, nil
+}
+
+func (s *Store) ListMaintenanceWindows(ctx context.Context, endsAfter time.Time) ([]model.MaintenanceWindow, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_maintenance_windows")
+	defer span.End()
+
+	rows, err := s.queries.ListMaintenanceWindows(ctx, endsAfter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list maintenance windows failed")
+		s.log.Error("sql list maintenance windows failed", zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.MaintenanceWindow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.MaintenanceWindow{
+			ID:        row.ID,
+			Room:      row.Room,
+			Type:      row.Type,
+			Mode:      row.Mode,
+			Reason:    row.Reason,
+			StartsAt:  row.StartsAt,
+			EndsAt:    row.EndsAt,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteMaintenanceWindow(ctx context.Context, id int64) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_maintenance_window")
+	defer span.End()
+
+	affected, err := s.queries.DeleteMaintenanceWindow(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete maintenance window failed")
+		s.log.Error("sql delete maintenance window failed", zap.Int64("id", id), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -1,15 +1,18 @@
 package mysql
 
 import (
+	"database/sql"
+
 	"go.uber.org/zap"
 	"sse_demo/internal/db"
 )
 
 type Store struct {
+	db      *sql.DB
 	queries *db.Queries
 	log     *zap.Logger
 }
 
-func New(queries *db.Queries, logger *zap.Logger) *Store {
-	return &Store{queries: queries, log: logger}
+func New(sqlDB *sql.DB, logger *zap.Logger) *Store {
+	return &Store{db: sqlDB, queries: db.New(sqlDB), log: logger}
 }
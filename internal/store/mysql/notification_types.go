@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) ListNotificationTypes(ctx context.Context) ([]model.NotificationType, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_notification_types")
+	defer span.End()
+
+	rows, err := s.queries.ListNotificationTypes(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list notification types failed")
+		s.log.Error("sql list notification types failed", zap.Error(err))
+		return nil, err
+	}
+
+	result := make([]model.NotificationType, 0, len(rows))
+	for _, row := range rows {
+		notificationType := model.NotificationType{
+			Name:              row.Name,
+			DefaultSeverity:   row.DefaultSeverity,
+			DefaultTTLSeconds: int(row.DefaultTtlSeconds),
+		}
+		if err := unmarshalJSONColumn(row.AllowedRooms, &notificationType.AllowedRooms); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "decode notification type failed")
+			s.log.Error("sql decode notification type failed", zap.String("name", row.Name), zap.Error(err))
+			return nil, err
+		}
+		result = append(result, notificationType)
+	}
+	return result, nil
+}
+
+func (s *Store) UpsertNotificationType(ctx context.Context, notificationType model.NotificationType) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.upsert_notification_type")
+	defer span.End()
+
+	allowedRooms, err := marshalJSONList(notificationType.AllowedRooms)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "marshal allowed rooms failed")
+		return err
+	}
+	if err := s.queries.UpsertNotificationType(ctx, db.UpsertNotificationTypeParams{
+		Name:              notificationType.Name,
+		DefaultSeverity:   notificationType.DefaultSeverity,
+		DefaultTtlSeconds: int32(notificationType.DefaultTTLSeconds),
+		AllowedRooms:      allowedRooms,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upsert notification type failed")
+		s.log.Error("sql upsert notification type failed", zap.String("name", notificationType.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) DeleteNotificationType(ctx context.Context, name string) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_notification_type")
+	defer span.End()
+
+	affected, err := s.queries.DeleteNotificationType(ctx, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete notification type failed")
+		s.log.Error("sql delete notification type failed", zap.String("name", name), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -2,75 +2,433 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 	"sse_demo/internal/db"
+	"sse_demo/internal/domain"
 	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
 )
 
 func (s *Store) CreateNotification(ctx context.Context, notification model.Notification) (model.Notification, error) {
 	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_notification")
 	defer span.End()
 
-	if notification.CreatedAt.IsZero() {
-		notification.CreatedAt = time.Now().UTC()
+	// The room sequence must be taken in the same transaction as the insert,
+	// which the batch path provides.
+	created, err := s.CreateNotifications(ctx, []model.Notification{notification})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create notification failed")
+		return model.Notification{}, err
+	}
+	return created[0], nil
+}
+
+// CreateNotifications inserts the batch in a single transaction, so either
+// every notification is stored or none is. Each notification takes the next
+// sequence number of its room. Notifications with a collapse key supersede
+// the latest live notification sharing the key in their room.
+func (s *Store) CreateNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_notifications")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "begin transaction failed")
+		s.log.Error("sql begin transaction failed", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	now := time.Now().UTC()
+	created := make([]model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.CreatedAt.IsZero() {
+			notification.CreatedAt = now
+		}
+		if err := lookupReplaced(ctx, queries, &notification); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "lookup collapsed notification failed")
+			s.log.Error("sql lookup collapsed notification failed",
+				zap.String("room", notification.Room),
+				zap.String("collapse_key", notification.CollapseKey),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		seq, err := queries.NextRoomSequence(ctx, notification.Room)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "next room sequence failed")
+			s.log.Error("sql next room sequence failed", zap.String("room", notification.Room), zap.Error(err))
+			return nil, err
+		}
+		notification.Seq = seq
+		params, err := createParams(notification)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "marshal notification failed")
+			return nil, err
+		}
+		result, err := queries.CreateNotification(ctx, params)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "create notification failed")
+			s.log.Error("sql create notification failed",
+				zap.String("room", notification.Room),
+				zap.String("type", notification.Type),
+				zap.Int("batch_size", len(notifications)),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "last insert id failed")
+			s.log.Error("sql last insert id failed", zap.Error(err))
+			return nil, err
+		}
+		notification.ID = id
+		if notification.Replaces != 0 {
+			if err := queries.SupersedeNotification(ctx, db.SupersedeNotificationParams{
+				SupersededBy: toNullInt64(id),
+				ID:           notification.Replaces,
+			}); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "supersede notification failed")
+				s.log.Error("sql supersede notification failed", zap.Int64("id", notification.Replaces), zap.Error(err))
+				return nil, err
+			}
+		}
+		created = append(created, notification)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit transaction failed")
+		s.log.Error("sql commit batch failed", zap.Int("batch_size", len(notifications)), zap.Error(err))
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *Store) GetNotification(ctx context.Context, id int64) (model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.get_notification")
+	defer span.End()
+
+	row, err := s.queries.GetNotification(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Notification{}, repository.ErrNotFound
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get notification failed")
+		s.log.Error("sql get notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+	notification, err := toModel(row)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "decode notification failed")
+		s.log.Error("sql decode notification failed", zap.Int64("id", id), zap.Error(err))
+		return model.Notification{}, err
+	}
+	return notification, nil
+}
+
+func (s *Store) UpdateNotification(ctx context.Context, notification model.Notification) (model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.update_notification")
+	defer span.End()
+
+	if notification.UpdatedAt == nil {
+		now := time.Now().UTC()
+		notification.UpdatedAt = &now
 	}
-	result, err := s.queries.CreateNotification(ctx, db.CreateNotificationParams{
-		Room:  notification.Room,
-		Type:  notification.Type,
-		Title: notification.Title,
-		Body:  notification.Body,
+	params, err := createParams(notification)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "marshal notification failed")
+		return model.Notification{}, err
+	}
+	updated, err := s.queries.UpdateNotification(ctx, db.UpdateNotificationParams{
+		Title:         params.Title,
+		Body:          params.Body,
+		Severity:      params.Severity,
+		Priority:      params.Priority,
+		Data:          params.Data,
+		Link:          params.Link,
+		Actions:       params.Actions,
+		Localizations: params.Localizations,
+		UpdatedAt:     toNullTime(notification.UpdatedAt),
+		ID:            notification.ID,
 	})
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "create notification failed")
-		s.log.Error("sql create notification failed",
-			zap.String("room", notification.Room),
-			zap.String("type", notification.Type),
-			zap.String("title", notification.Title),
-			zap.Error(err),
-		)
+		span.SetStatus(codes.Error, "update notification failed")
+		s.log.Error("sql update notification failed", zap.Int64("id", notification.ID), zap.Error(err))
+		return model.Notification{}, err
+	}
+	if updated == 0 {
+		return model.Notification{}, repository.ErrNotFound
+	}
+	return notification, nil
+}
+
+func (s *Store) DeleteNotification(ctx context.Context, id int64, deletedAt time.Time) (model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_notification")
+	defer span.End()
+
+	notification, err := s.GetNotification(ctx, id)
+	if err != nil {
 		return model.Notification{}, err
 	}
-	id, err := result.LastInsertId()
+	deleted, err := s.queries.SoftDeleteNotification(ctx, db.SoftDeleteNotificationParams{
+		DeletedAt: toNullTime(&deletedAt),
+		ID:        id,
+	})
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "last insert id failed")
-		s.log.Error("sql last insert id failed", zap.Error(err))
+		span.SetStatus(codes.Error, "delete notification failed")
+		s.log.Error("sql delete notification failed", zap.Int64("id", id), zap.Error(err))
 		return model.Notification{}, err
 	}
-	notification.ID = id
+	if deleted == 0 {
+		return model.Notification{}, repository.ErrNotFound
+	}
+	notification.DeletedAt = &deletedAt
 	return notification, nil
 }
 
-func (s *Store) ListNotifications(ctx context.Context, room string, limit int) ([]model.Notification, error) {
+func (s *Store) ListNotifications(ctx context.Context, room string, opts repository.ListOptions) ([]model.Notification, error) {
 	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_notifications")
 	defer span.End()
 
-	rows, err := s.queries.ListNotificationsByRoom(ctx, db.ListNotificationsByRoomParams{
-		Room:  room,
-		Limit: int32(limit),
-	})
+	minPriority := 0
+	if opts.MinPriority != "" {
+		minPriority = domain.PriorityRank(opts.MinPriority)
+	}
+	beforeID := int64(math.MaxInt64)
+	if opts.BeforeID > 0 {
+		beforeID = opts.BeforeID
+	}
+	params := db.ListRoomNotificationsDescParams{
+		Room:        room,
+		MinPriority: int8(minPriority),
+		BeforeID:    beforeID,
+		AfterID:     opts.AfterID,
+		AfterSeq:    opts.AfterSeq,
+		Type:        sql.NullString{String: opts.Type, Valid: opts.Type != ""},
+		Since:       toNullTime(opts.Since),
+		Until:       toNullTime(opts.Until),
+		PageLimit:   int32(opts.Limit),
+	}
+	var rows []db.Notification
+	var err error
+	switch {
+	case opts.AfterSeq > 0:
+		rows, err = s.queries.ListRoomNotificationsBySeq(ctx, db.ListRoomNotificationsBySeqParams(params))
+		slices.Reverse(rows)
+	case opts.AfterID > 0:
+		// Walk forward from the cursor so the page starts right after it,
+		// then flip to the usual newest-first order.
+		rows, err = s.queries.ListRoomNotificationsAsc(ctx, db.ListRoomNotificationsAscParams(params))
+		slices.Reverse(rows)
+	default:
+		rows, err = s.queries.ListRoomNotificationsDesc(ctx, params)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "list notifications failed")
-		s.log.Error("sql list notifications failed", zap.String("room", room), zap.Int("limit", limit), zap.Error(err))
+		s.log.Error("sql list notifications failed", zap.String("room", room), zap.Int("limit", opts.Limit), zap.Error(err))
 		return nil, err
 	}
 
 	var result []model.Notification
 	for _, row := range rows {
-		result = append(result, model.Notification{
-			ID:        row.ID,
-			Room:      row.Room,
-			Type:      row.Type,
-			Title:     row.Title,
-			Body:      row.Body,
-			CreatedAt: row.CreatedAt,
-		})
+		notification, err := toModel(row)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "decode notification failed")
+			s.log.Error("sql decode notification failed", zap.Int64("id", row.ID), zap.Error(err))
+			return nil, err
+		}
+		result = append(result, notification)
 	}
 	return result, nil
 }
+
+func (s *Store) SearchNotifications(ctx context.Context, query repository.SearchQuery) ([]model.SearchHit, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.search_notifications")
+	defer span.End()
+
+	rows, err := s.queries.SearchNotifications(ctx, db.SearchNotificationsParams{
+		Query:      query.Text,
+		Room:       sql.NullString{String: query.Room, Valid: query.Room != ""},
+		Type:       sql.NullString{String: query.Type, Valid: query.Type != ""},
+		PageLimit:  int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "search notifications failed")
+		s.log.Error("sql search notifications failed", zap.String("room", query.Room), zap.Error(err))
+		return nil, err
+	}
+
+	hits := make([]model.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		notification, err := toModel(row.Notification)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "decode notification failed")
+			s.log.Error("sql decode notification failed", zap.Int64("id", row.Notification.ID), zap.Error(err))
+			return nil, err
+		}
+		hits = append(hits, model.SearchHit{Notification: notification, Score: row.Score})
+	}
+	return hits, nil
+}
+
+// lookupReplaced locks the live notification sharing the collapse key and
+// records its id in notification.Replaces.
+func lookupReplaced(ctx context.Context, queries *db.Queries, notification *model.Notification) error {
+	if notification.CollapseKey == "" {
+		return nil
+	}
+	id, err := queries.GetLatestCollapsedNotificationID(ctx, db.GetLatestCollapsedNotificationIDParams{
+		Room:        notification.Room,
+		CollapseKey: notification.CollapseKey,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	notification.Replaces = id
+	return nil
+}
+
+func createParams(notification model.Notification) (db.CreateNotificationParams, error) {
+	localizations, err := marshalJSONColumn(notification.Localizations)
+	if err != nil {
+		return db.CreateNotificationParams{}, err
+	}
+	actions, err := marshalJSONList(notification.Actions)
+	if err != nil {
+		return db.CreateNotificationParams{}, err
+	}
+	templateParams, err := marshalJSONColumn(notification.TemplateParams)
+	if err != nil {
+		return db.CreateNotificationParams{}, err
+	}
+	return db.CreateNotificationParams{
+		Room:           notification.Room,
+		Type:           notification.Type,
+		Title:          notification.Title,
+		Body:           notification.Body,
+		Severity:       notification.Severity,
+		Priority:       int8(domain.PriorityRank(notification.Priority)),
+		Data:           notification.Data,
+		Link:           notification.Link,
+		Actions:        actions,
+		Localizations:  localizations,
+		TemplateKey:    notification.TemplateKey,
+		TemplateParams: templateParams,
+		CreatedAt:      notification.CreatedAt,
+		ExpiresAt:      toNullTime(notification.ExpiresAt),
+		CollapseKey:    notification.CollapseKey,
+		ReplacesID:     toNullInt64(notification.Replaces),
+		Seq:            notification.Seq,
+	}, nil
+}
+
+func toModel(row db.Notification) (model.Notification, error) {
+	notification := model.Notification{
+		ID:           row.ID,
+		Seq:          row.Seq,
+		Room:         row.Room,
+		Type:         row.Type,
+		Title:        row.Title,
+		Body:         row.Body,
+		Severity:     row.Severity,
+		Priority:     domain.PriorityFromRank(int(row.Priority)),
+		Data:         row.Data,
+		Link:         row.Link,
+		TemplateKey:  row.TemplateKey,
+		CreatedAt:    row.CreatedAt,
+		ExpiresAt:    fromNullTime(row.ExpiresAt),
+		UpdatedAt:    fromNullTime(row.UpdatedAt),
+		DeletedAt:    fromNullTime(row.DeletedAt),
+		CollapseKey:  row.CollapseKey,
+		Replaces:     row.ReplacesID.Int64,
+		SupersededBy: row.SupersededBy.Int64,
+	}
+	if err := unmarshalJSONColumn(row.Actions, &notification.Actions); err != nil {
+		return model.Notification{}, err
+	}
+	if err := unmarshalJSONColumn(row.Localizations, &notification.Localizations); err != nil {
+		return model.Notification{}, err
+	}
+	if err := unmarshalJSONColumn(row.TemplateParams, &notification.TemplateParams); err != nil {
+		return model.Notification{}, err
+	}
+	return notification, nil
+}
+
+// marshalJSONColumn encodes optional values for nullable JSON columns; empty
+// maps are stored as NULL.
+func marshalJSONColumn[T any](value map[string]T) (json.RawMessage, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func marshalJSONList[T any](value []T) (json.RawMessage, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func unmarshalJSONColumn(raw json.RawMessage, dst any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+func toNullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *value, Valid: true}
+}
+
+func fromNullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time
+	return &t
+}
+
+func toNullInt64(value int64) sql.NullInt64 {
+	if value == 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: value, Valid: true}
+}
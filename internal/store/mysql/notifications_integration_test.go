@@ -5,14 +5,16 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
-	"sse_demo/internal/db"
 	"sse_demo/internal/domain"
 	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
 )
 
 func TestMySQLStoreIntegration(t *testing.T) {
@@ -24,26 +26,285 @@ func TestMySQLStoreIntegration(t *testing.T) {
 	require.NoError(t, err)
 	defer dbConn.Close()
 
-	queries := db.New(dbConn)
-	store := New(queries, zap.NewNop())
+	store := New(dbConn, zap.NewNop())
 
 	created, err := store.CreateNotification(ctx, model.Notification{
 		Room:  "room-1",
 		Type:  domain.NotificationTypeInfo,
 		Title: "title",
 		Body:  "body",
+		Data:  json.RawMessage(`{"invoice_id": 1234}`),
+		Link:  "https://example.com/invoices/1234",
+		Actions: []model.Action{
+			{ID: "pay", Label: "Pay now"},
+		},
 	})
 	require.NoError(t, err)
 	require.NotZero(t, created.ID)
 	require.Equal(t, "room-1", created.Room)
 	require.Equal(t, domain.NotificationTypeInfo, created.Type)
 
-	history, err := store.ListNotifications(ctx, "room-1", 10)
+	history, err := store.ListNotifications(ctx, "room-1", repository.ListOptions{Limit: 10})
 	require.NoError(t, err)
 	require.Len(t, history, 1)
 	require.Equal(t, created.ID, history[0].ID)
 	require.Equal(t, created.Type, history[0].Type)
+	require.JSONEq(t, `{"invoice_id": 1234}`, string(history[0].Data))
+	require.Equal(t, created.Link, history[0].Link)
+	require.Equal(t, created.Actions, history[0].Actions)
 
-}
+	batch, err := store.CreateNotifications(ctx, []model.Notification{
+		{Room: "room-2", Type: domain.NotificationTypeInfo, Title: "first", Body: "body"},
+		{Room: "room-2", Type: domain.NotificationTypeWarning, Title: "second", Body: "body"},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Less(t, batch[0].ID, batch[1].ID)
+	require.Equal(t, int64(1), created.Seq)
+	require.Equal(t, []int64{1, 2}, []int64{batch[0].Seq, batch[1].Seq})
+
+	history, err = store.ListNotifications(ctx, "room-2", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, int64(2), history[0].Seq)
+	history, err = store.ListNotifications(ctx, "room-2", repository.ListOptions{Limit: 10, AfterSeq: 1})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, batch[1].ID, history[0].ID)
+
+	edited := batch[0]
+	edited.Title = "edited"
+	_, err = store.UpdateNotification(ctx, edited)
+	require.NoError(t, err)
+	got, err := store.GetNotification(ctx, edited.ID)
+	require.NoError(t, err)
+	require.Equal(t, "edited", got.Title)
+	require.NotNil(t, got.UpdatedAt)
+
+	_, err = store.DeleteNotification(ctx, edited.ID, time.Now().UTC())
+	require.NoError(t, err)
+	_, err = store.GetNotification(ctx, edited.ID)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = store.DeleteNotification(ctx, edited.ID, time.Now().UTC())
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	history, err = store.ListNotifications(ctx, "room-2", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	first, err := store.CreateNotification(ctx, model.Notification{Room: "room-3", Type: domain.NotificationTypeInfo, Title: "running", Body: "body", CollapseKey: "build-7"})
+	require.NoError(t, err)
+	second, err := store.CreateNotification(ctx, model.Notification{Room: "room-3", Type: domain.NotificationTypeInfo, Title: "passed", Body: "body", CollapseKey: "build-7"})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.Replaces)
+
+	history, err = store.ListNotifications(ctx, "room-3", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, second.ID, history[0].ID)
+	require.Equal(t, first.ID, history[0].Replaces)
+
+	_, err = store.CreateNotification(ctx, model.Notification{Room: "room-4", Type: domain.NotificationTypeInfo, Title: "low", Body: "body", Priority: domain.PriorityLow})
+	require.NoError(t, err)
+	urgent, err := store.CreateNotification(ctx, model.Notification{Room: "room-4", Type: domain.NotificationTypeInfo, Title: "high", Body: "body", Priority: domain.PriorityHigh})
+	require.NoError(t, err)
+
+	history, err = store.ListNotifications(ctx, "room-4", repository.ListOptions{Limit: 10, MinPriority: domain.PriorityNormal})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, urgent.ID, history[0].ID)
+	require.Equal(t, domain.PriorityHigh, history[0].Priority)
+
+	history, err = store.ListNotifications(ctx, "room-4", repository.ListOptions{Limit: 10, BeforeID: urgent.ID})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "low", history[0].Title)
+
+	history, err = store.ListNotifications(ctx, "room-4", repository.ListOptions{Limit: 1, AfterID: history[0].ID, Type: domain.NotificationTypeInfo})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, urgent.ID, history[0].ID)
+
+	invoice, err := store.CreateNotification(ctx, model.Notification{Room: "room-5", Type: domain.NotificationTypeInfo, Title: "Invoice 1234 overdue", Body: "Please pay invoice 1234."})
+	require.NoError(t, err)
+	hits, err := store.SearchNotifications(ctx, repository.SearchQuery{Text: "invoice 1234", Room: "room-5", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, invoice.ID, hits[0].Notification.ID)
+	require.Positive(t, hits[0].Score)
+
+	var oldest model.Notification
+	for _, title := range []string{"old", "older", "newest"} {
+		created, err := store.CreateNotification(ctx, model.Notification{Room: "room-6", Type: domain.NotificationTypeInfo, Title: title, Body: "body"})
+		require.NoError(t, err)
+		if oldest.ID == 0 {
+			oldest = created
+		}
+	}
+	now := time.Now().UTC()
+	require.NoError(t, store.AddNotificationDeliveries(ctx, []model.NotificationDelivery{{NotificationID: oldest.ID, UserID: "alice", Enqueued: 1, UpdatedAt: now}}))
+	require.NoError(t, store.CreateEscalation(ctx, model.Escalation{NotificationID: oldest.ID, Policy: "oncall", Status: domain.EscalationPending, DueAt: now, CreatedAt: now, UpdatedAt: now}))
+	purged, err := store.PurgeNotifications(ctx, repository.PurgeScope{Room: "room-6", Types: []string{domain.NotificationTypeInfo}, Keep: 1}, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	purged, err = store.PurgeNotifications(ctx, repository.PurgeScope{Room: "room-6", Types: []string{domain.NotificationTypeInfo}, Keep: 1}, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	history, err = store.ListNotifications(ctx, "room-6", repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "newest", history[0].Title)
+	deliveries, err := store.ListNotificationDeliveries(ctx, oldest.ID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+	due, err := store.ListDueEscalations(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	for _, escalation := range due {
+		require.NotEqual(t, oldest.ID, escalation.NotificationID)
+	}
+
+	exported, err := store.ExportNotifications(ctx, repository.ExportFilter{Room: "room-6", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	restored := exported[0]
+	restored.ID += 1000
+	restored.Room = "room-7"
+	imported, err := store.ImportNotifications(ctx, []model.Notification{restored})
+	require.NoError(t, err)
+	require.Equal(t, restored.ID, imported[0].ID)
+	got, err = store.GetNotification(ctx, restored.ID)
+	require.NoError(t, err)
+	require.True(t, exported[0].CreatedAt.Equal(got.CreatedAt))
+	_, err = store.ImportNotifications(ctx, []model.Notification{restored})
+	require.Error(t, err)
 
-// setupMySQLContainer is defined in testhelpers_integration.go
+	now = time.Now().UTC().Truncate(time.Second)
+	room := model.Room{Name: "room-8", DisplayName: "Room 8", Visibility: domain.VisibilityPublic, HistoryLimit: 5, CreatedAt: now, UpdatedAt: now}
+	_, err = store.CreateRoom(ctx, room)
+	require.NoError(t, err)
+	_, err = store.CreateRoom(ctx, room)
+	require.ErrorIs(t, err, repository.ErrConflict)
+	// An update that changes nothing must not look like a missing room.
+	updated, err := store.UpdateRoom(ctx, room)
+	require.NoError(t, err)
+	require.Equal(t, "Room 8", updated.DisplayName)
+	_, err = store.UpdateRoom(ctx, model.Room{Name: "room-9", Visibility: domain.VisibilityPublic, UpdatedAt: now})
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	deleted, err := store.DeleteRoom(ctx, room.Name)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	_, err = store.GetRoom(ctx, room.Name)
+	require.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = store.GetUserPreferences(ctx, "user-1")
+	require.ErrorIs(t, err, repository.ErrNotFound)
+	until := now.Add(time.Hour)
+	prefs := model.UserPreferences{UserID: "user-1", MutedRooms: []model.RoomMute{{Room: "room-*", Until: &until}}, OptOutTypes: []string{domain.NotificationTypeInfo}, QuietHours: &model.QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}, UpdatedAt: now}
+	require.NoError(t, store.UpsertUserPreferences(ctx, prefs))
+	storedPrefs, err := store.GetUserPreferences(ctx, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, prefs.OptOutTypes, storedPrefs.OptOutTypes)
+	require.True(t, until.Equal(*storedPrefs.MutedRooms[0].Until))
+	require.Equal(t, prefs.QuietHours, storedPrefs.QuietHours)
+
+	window, err := store.CreateMaintenanceWindow(ctx, model.MaintenanceWindow{Room: "room-*", Mode: domain.SuppressionSuppress, StartsAt: now, EndsAt: now.Add(time.Hour), CreatedAt: now})
+	require.NoError(t, err)
+	require.NotZero(t, window.ID)
+	windows, err := store.ListMaintenanceWindows(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	windows, err = store.ListMaintenanceWindows(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, windows)
+	deleted, err = store.DeleteMaintenanceWindow(ctx, window.ID)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	pinnedNotification, err := store.CreateNotification(ctx, model.Notification{Room: "room-pins", Type: domain.NotificationTypeInfo, Title: "pinned", Body: "body", CreatedAt: now})
+	require.NoError(t, err)
+	pin := model.NotificationPin{NotificationID: pinnedNotification.ID, Room: pinnedNotification.Room, PinnedBy: "user-1", PinnedAt: now}
+	added, err := store.PinNotification(ctx, pin, 0)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = store.PinNotification(ctx, pin, 0)
+	require.NoError(t, err)
+	require.False(t, added)
+	other, err := store.CreateNotification(ctx, model.Notification{Room: "room-pins", Type: domain.NotificationTypeInfo, Title: "other", Body: "body", CreatedAt: now})
+	require.NoError(t, err)
+	_, err = store.PinNotification(ctx, model.NotificationPin{NotificationID: other.ID, Room: other.Room, PinnedAt: now}, 1)
+	require.ErrorIs(t, err, repository.ErrLimitReached)
+	// Re-pinning an existing pin at the limit keeps it instead of failing.
+	added, err = store.PinNotification(ctx, pin, 1)
+	require.NoError(t, err)
+	require.False(t, added)
+	pinned, err := store.ListPinnedNotifications(ctx, "room-pins")
+	require.NoError(t, err)
+	require.Len(t, pinned, 1)
+	require.True(t, pinned[0].Pinned)
+	removed, err := store.UnpinNotification(ctx, pinnedNotification.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.NoError(t, store.UpsertRoomState(ctx, model.RoomState{Room: "ci", Key: "build_status", Value: json.RawMessage(`"red"`), UpdatedAt: now}))
+	require.NoError(t, store.UpsertRoomState(ctx, model.RoomState{Room: "ci", Key: "build_status", Value: json.RawMessage(`"green"`), UpdatedAt: now}))
+	states, err := store.ListRoomState(ctx, "ci")
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.JSONEq(t, `"green"`, string(states[0].Value))
+	removed, err = store.DeleteRoomState(ctx, "ci", "build_status")
+	require.NoError(t, err)
+	require.True(t, removed)
+	announcement, err := store.CreateAnnouncement(ctx, model.Announcement{Title: "Restart", Body: "b", CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = store.CreateAnnouncement(ctx, model.Announcement{Title: "Expired", Body: "b", CreatedAt: now, ExpiresAt: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	announcements, err := store.ListActiveAnnouncements(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, announcements, 1)
+	require.Equal(t, announcement.ID, announcements[0].ID)
+	removed, err = store.DeleteAnnouncement(ctx, announcement.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.NoError(t, store.AddNotificationDeliveries(ctx, []model.NotificationDelivery{
+		{NotificationID: pinnedNotification.ID, UserID: "alice", Enqueued: 1},
+		{NotificationID: pinnedNotification.ID, UserID: "", Enqueued: 1},
+	}))
+	require.NoError(t, store.AddNotificationDeliveries(ctx, []model.NotificationDelivery{
+		{NotificationID: pinnedNotification.ID, UserID: "alice", Enqueued: -1, Dropped: 1},
+	}))
+	deliveries, err = store.ListNotificationDeliveries(ctx, pinnedNotification.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, "", deliveries[0].UserID)
+	require.Equal(t, "alice", deliveries[1].UserID)
+	require.Zero(t, deliveries[1].Enqueued)
+	require.Equal(t, 1, deliveries[1].Dropped)
+	require.NoError(t, store.CreateEscalation(ctx, model.Escalation{NotificationID: pinnedNotification.ID, Policy: "ops", Status: domain.EscalationPending, DueAt: now, CreatedAt: now, UpdatedAt: now}))
+	due, err = store.ListDueEscalations(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	claimed, err := store.ClaimEscalation(ctx, pinnedNotification.ID, 0, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, claimed)
+	claimed, err = store.ClaimEscalation(ctx, pinnedNotification.ID, 0, now, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, claimed)
+	advanced, err := store.AdvanceEscalation(ctx, model.Escalation{NotificationID: pinnedNotification.ID, Step: 1, Status: domain.EscalationPending, DueAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	require.True(t, advanced)
+	acked, err := store.AckEscalation(ctx, pinnedNotification.ID, "alice", now)
+	require.NoError(t, err)
+	require.True(t, acked)
+	due, err = store.ListDueEscalations(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+	stale := now.Add(-2 * time.Minute)
+	_, claimedKey, err := store.ClaimIdempotencyKey(ctx, "abandoned", stale, stale.Add(-time.Hour), stale.Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, claimedKey)
+	_, claimedKey, err = store.ClaimIdempotencyKey(ctx, "abandoned", now, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, claimedKey, "an unfinished claim past its lease is free")
+	_, claimedKey, err = store.ClaimIdempotencyKey(ctx, "abandoned", now, now.Add(-time.Hour), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.False(t, claimedKey)
+}
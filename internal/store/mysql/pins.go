@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+// PinNotification checks for an existing pin, counts the live pins of the
+// room and inserts the pin in one transaction. The transaction first locks
+// the room's sequence row, which every stored notification of the room has,
+// so concurrent pins in a room are serialized even when it has no pins yet.
+// An existing pin is kept unchanged, whatever the limit.
+func (s *Store) PinNotification(ctx context.Context, pin model.NotificationPin, maxPerRoom int) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.pin_notification")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "begin transaction failed")
+		s.log.Error("sql begin transaction failed", zap.Error(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	if _, err := queries.LockRoomSequence(ctx, pin.Room); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "lock room failed")
+		s.log.Error("sql lock room failed", zap.String("room", pin.Room), zap.Error(err))
+		return false, err
+	}
+	if _, err := queries.GetNotificationPin(ctx, pin.NotificationID); err == nil {
+		return false, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get pin failed")
+		s.log.Error("sql get pin failed", zap.Int64("id", pin.NotificationID), zap.Error(err))
+		return false, err
+	}
+	if maxPerRoom > 0 {
+		count, err := queries.CountLivePinnedNotifications(ctx, pin.Room)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "count pinned notifications failed")
+			s.log.Error("sql count pinned notifications failed", zap.String("room", pin.Room), zap.Error(err))
+			return false, err
+		}
+		if count >= int64(maxPerRoom) {
+			return false, repository.ErrLimitReached
+		}
+	}
+
+	affected, err := queries.PinNotification(ctx, db.PinNotificationParams{
+		NotificationID: pin.NotificationID,
+		Room:           pin.Room,
+		PinnedBy:       pin.PinnedBy,
+		PinnedAt:       pin.PinnedAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "pin notification failed")
+		s.log.Error("sql pin notification failed", zap.Int64("id", pin.NotificationID), zap.Error(err))
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit transaction failed")
+		s.log.Error("sql commit pin failed", zap.Int64("id", pin.NotificationID), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *Store) UnpinNotification(ctx context.Context, notificationID int64) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.unpin_notification")
+	defer span.End()
+
+	affected, err := s.queries.UnpinNotification(ctx, notificationID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "unpin notification failed")
+		s.log.Error("sql unpin notification failed", zap.Int64("id", notificationID), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *Store) ListPinnedNotifications(ctx context.Context, room string) ([]model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_pinned_notifications")
+	defer span.End()
+
+	rows, err := s.queries.ListPinnedNotifications(ctx, room)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list pinned notifications failed")
+		s.log.Error("sql list pinned notifications failed", zap.String("room", room), zap.Error(err))
+		return nil, err
+	}
+
+	result := make([]model.Notification, 0, len(rows))
+	for _, row := range rows {
+		notification, err := toModel(row.Notification)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "decode notification failed")
+			s.log.Error("sql decode notification failed", zap.Int64("id", row.Notification.ID), zap.Error(err))
+			return nil, err
+		}
+		pinnedAt := row.PinnedAt
+		notification.Pinned = true
+		notification.PinnedAt = &pinnedAt
+		result = append(result, notification)
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) GetUserPreferences(ctx context.Context, userID string) (model.UserPreferences, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.get_user_preferences")
+	defer span.End()
+
+	row, err := s.queries.GetUserPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserPreferences{}, repository.ErrNotFound
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get user preferences failed")
+		s.log.Error("sql get user preferences failed", zap.String("user_id", userID), zap.Error(err))
+		return model.UserPreferences{}, err
+	}
+
+	prefs := model.UserPreferences{UserID: row.UserID, UpdatedAt: row.UpdatedAt}
+	err = unmarshalJSONColumn(row.MutedRooms, &prefs.MutedRooms)
+	if err == nil {
+		err = unmarshalJSONColumn(row.OptOutTypes, &prefs.OptOutTypes)
+	}
+	if err == nil {
+		err = unmarshalJSONColumn(row.QuietHours, &prefs.QuietHours)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "decode user preferences failed")
+		s.log.Error("sql decode user preferences failed", zap.String("user_id", userID), zap.Error(err))
+		return model.UserPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (s *Store) UpsertUserPreferences(ctx context.Context, prefs model.UserPreferences) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.upsert_user_preferences")
+	defer span.End()
+
+	mutedRooms, err := marshalJSONList(prefs.MutedRooms)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "marshal muted rooms failed")
+		return err
+	}
+	optOutTypes, err := marshalJSONList(prefs.OptOutTypes)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "marshal opt-out types failed")
+		return err
+	}
+	var quietHours json.RawMessage
+	if prefs.QuietHours != nil {
+		if quietHours, err = json.Marshal(prefs.QuietHours); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "marshal quiet hours failed")
+			return err
+		}
+	}
+	if err := s.queries.UpsertUserPreferences(ctx, db.UpsertUserPreferencesParams{
+		UserID:      prefs.UserID,
+		MutedRooms:  mutedRooms,
+		OptOutTypes: optOutTypes,
+		QuietHours:  quietHours,
+		UpdatedAt:   prefs.UpdatedAt,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upsert user preferences failed")
+		s.log.Error("sql upsert user preferences failed", zap.String("user_id", prefs.UserID), zap.Error(err))
+		return err
+	}
+	return nil
+}
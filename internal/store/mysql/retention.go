@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) ListRoomTypes(ctx context.Context) ([]model.RoomType, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_room_types")
+	defer span.End()
+
+	rows, err := s.queries.ListNotificationRoomTypes(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list room types failed")
+		s.log.Error("sql list room types failed", zap.Error(err))
+		return nil, err
+	}
+	pairs := make([]model.RoomType, len(rows))
+	for i, row := range rows {
+		pairs[i] = model.RoomType{Room: row.Room, Type: row.Type}
+	}
+	return pairs, nil
+}
+
+// PurgeNotifications deletes one batch per call so each statement only holds
+// its row locks briefly; callers loop until fewer than limit rows go.
+func (s *Store) PurgeNotifications(ctx context.Context, scope repository.PurgeScope, limit int) (int64, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.purge_notifications")
+	defer span.End()
+	span.SetAttributes(attribute.String("notification.room", scope.Room))
+
+	if len(scope.Types) == 0 {
+		return 0, nil
+	}
+	var maxID int64
+	if scope.Keep > 0 {
+		id, err := s.queries.GetNotificationCountCutoffID(ctx, db.GetNotificationCountCutoffIDParams{
+			Room:  scope.Room,
+			Types: scope.Types,
+			Keep:  int32(scope.Keep),
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "find count cutoff failed")
+			s.log.Error("sql find notification count cutoff failed", zap.String("room", scope.Room), zap.Error(err))
+			return 0, err
+		}
+		maxID = id
+	}
+	before := sql.NullTime{Time: scope.Before, Valid: !scope.Before.IsZero()}
+	if !before.Valid && maxID == 0 {
+		return 0, nil
+	}
+
+	// The batch and the pins, deliveries and escalations pointing at it go
+	// in one transaction, so nothing is left referring to a purged id.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "begin transaction failed")
+		s.log.Error("sql begin transaction failed", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	ids, err := queries.ListPurgeNotificationIDs(ctx, db.ListPurgeNotificationIDsParams{
+		Room:       scope.Room,
+		Types:      scope.Types,
+		Before:     before,
+		MaxID:      maxID,
+		PurgeLimit: int32(limit),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list purge batch failed")
+		s.log.Error("sql list purge batch failed", zap.String("room", scope.Room), zap.Error(err))
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	for _, purge := range []func(context.Context, []int64) error{
+		queries.PurgeNotificationPins,
+		queries.PurgeNotificationDeliveries,
+		queries.PurgeNotificationEscalations,
+	} {
+		if err := purge(ctx, ids); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "purge dependent rows failed")
+			s.log.Error("sql purge dependent rows failed", zap.String("room", scope.Room), zap.Error(err))
+			return 0, err
+		}
+	}
+	purged, err := queries.PurgeNotifications(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "purge notifications failed")
+		s.log.Error("sql purge notifications failed", zap.String("room", scope.Room), zap.Error(err))
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit transaction failed")
+		s.log.Error("sql commit purge failed", zap.String("room", scope.Room), zap.Error(err))
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int64("notification.purged", purged))
+	return purged, nil
+}
@@ -0,0 +1,133 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) CreateRoom(ctx context.Context, room model.Room) (model.Room, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.create_room")
+	defer span.End()
+
+	inserted, err := s.queries.CreateRoom(ctx, db.CreateRoomParams{
+		Name:              room.Name,
+		DisplayName:       room.DisplayName,
+		Description:       room.Description,
+		Owner:             room.Owner,
+		Visibility:        room.Visibility,
+		HistoryLimit:      int32(room.HistoryLimit),
+		RetentionDays:     int32(room.RetentionDays),
+		RetentionMaxCount: int32(room.RetentionMaxCount),
+		CreatedAt:         room.CreatedAt,
+		UpdatedAt:         room.UpdatedAt,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "create room failed")
+		s.log.Error("sql create room failed", zap.String("name", room.Name), zap.Error(err))
+		return model.Room{}, err
+	}
+	if inserted == 0 {
+		return model.Room{}, repository.ErrConflict
+	}
+	return room, nil
+}
+
+func (s *Store) GetRoom(ctx context.Context, name string) (model.Room, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.get_room")
+	defer span.End()
+
+	row, err := s.queries.GetRoom(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Room{}, repository.ErrNotFound
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "get room failed")
+		s.log.Error("sql get room failed", zap.String("name", name), zap.Error(err))
+		return model.Room{}, err
+	}
+	return roomFromRow(row), nil
+}
+
+func (s *Store) ListRooms(ctx context.Context) ([]model.Room, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_rooms")
+	defer span.End()
+
+	rows, err := s.queries.ListRooms(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list rooms failed")
+		s.log.Error("sql list rooms failed", zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.Room, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, roomFromRow(row))
+	}
+	return result, nil
+}
+
+func (s *Store) UpdateRoom(ctx context.Context, room model.Room) (model.Room, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.update_room")
+	defer span.End()
+
+	_, err := s.queries.UpdateRoom(ctx, db.UpdateRoomParams{
+		DisplayName:       room.DisplayName,
+		Description:       room.Description,
+		Owner:             room.Owner,
+		Visibility:        room.Visibility,
+		HistoryLimit:      int32(room.HistoryLimit),
+		RetentionDays:     int32(room.RetentionDays),
+		RetentionMaxCount: int32(room.RetentionMaxCount),
+		UpdatedAt:         room.UpdatedAt,
+		Name:              room.Name,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "update room failed")
+		s.log.Error("sql update room failed", zap.String("name", room.Name), zap.Error(err))
+		return model.Room{}, err
+	}
+	// MySQL does not count rows whose values did not change, so the affected
+	// row count cannot tell an unchanged room from a missing one. Reading the
+	// room back does, and also returns its created_at.
+	return s.GetRoom(ctx, room.Name)
+}
+
+func (s *Store) DeleteRoom(ctx context.Context, name string) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_room")
+	defer span.End()
+
+	affected, err := s.queries.DeleteRoom(ctx, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete room failed")
+		s.log.Error("sql delete room failed", zap.String("name", name), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func roomFromRow(row db.Room) model.Room {
+	return model.Room{
+		Name:              row.Name,
+		DisplayName:       row.DisplayName,
+		Description:       row.Description,
+		Owner:             row.Owner,
+		Visibility:        row.Visibility,
+		HistoryLimit:      int(row.HistoryLimit),
+		RetentionDays:     int(row.RetentionDays),
+		RetentionMaxCount: int(row.RetentionMaxCount),
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+}
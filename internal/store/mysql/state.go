@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+)
+
+func (s *Store) UpsertRoomState(ctx context.Context, state model.RoomState) error {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.upsert_room_state")
+	defer span.End()
+
+	if err := s.queries.UpsertRoomState(ctx, db.UpsertRoomStateParams{
+		Room:      state.Room,
+		StateKey:  state.Key,
+		Value:     state.Value,
+		UpdatedAt: state.UpdatedAt,
+	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upsert room state failed")
+		s.log.Error("sql upsert room state failed", zap.String("room", state.Room), zap.String("key", state.Key), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *Store) ListRoomState(ctx context.Context, room string) ([]model.RoomState, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.list_room_state")
+	defer span.End()
+
+	rows, err := s.queries.ListRoomState(ctx, room)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list room state failed")
+		s.log.Error("sql list room state failed", zap.String("room", room), zap.Error(err))
+		return nil, err
+	}
+	result := make([]model.RoomState, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, model.RoomState{
+			Room:      row.Room,
+			Key:       row.StateKey,
+			Value:     row.Value,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *Store) DeleteRoomState(ctx context.Context, room, key string) (bool, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.delete_room_state")
+	defer span.End()
+
+	affected, err := s.queries.DeleteRoomState(ctx, db.DeleteRoomStateParams{Room: room, StateKey: key})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete room state failed")
+		s.log.Error("sql delete room state failed", zap.String("room", room), zap.String("key", key), zap.Error(err))
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"sse_demo/internal/db"
+	"sse_demo/internal/model"
+	"sse_demo/internal/repository"
+)
+
+func (s *Store) ExportNotifications(ctx context.Context, filter repository.ExportFilter) ([]model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.export_notifications")
+	defer span.End()
+
+	rows, err := s.queries.ExportNotifications(ctx, db.ExportNotificationsParams{
+		AfterID:   filter.AfterID,
+		Room:      sql.NullString{String: filter.Room, Valid: filter.Room != ""},
+		Since:     toNullTime(filter.Since),
+		Until:     toNullTime(filter.Until),
+		PageLimit: int32(filter.Limit),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "export notifications failed")
+		s.log.Error("sql export notifications failed", zap.String("room", filter.Room), zap.Int64("after_id", filter.AfterID), zap.Error(err))
+		return nil, err
+	}
+
+	notifications := make([]model.Notification, 0, len(rows))
+	for _, row := range rows {
+		notification, err := toModel(row)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "decode notification failed")
+			s.log.Error("sql decode notification failed", zap.Int64("id", row.ID), zap.Error(err))
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+func (s *Store) ImportNotifications(ctx context.Context, notifications []model.Notification) ([]model.Notification, error) {
+	ctx, span := otel.Tracer("mysql").Start(ctx, "mysql.import_notifications")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "begin transaction failed")
+		s.log.Error("sql begin transaction failed", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	imported := make([]model.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		// Imported notifications are numbered after the room's existing
+		// ones rather than keeping the sequence of their source.
+		seq, err := queries.NextRoomSequence(ctx, notification.Room)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "next room sequence failed")
+			s.log.Error("sql next room sequence failed", zap.String("room", notification.Room), zap.Error(err))
+			return nil, err
+		}
+		notification.Seq = seq
+		params, err := createParams(notification)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "marshal notification failed")
+			return nil, err
+		}
+		id, err := queries.ImportNotification(ctx, db.ImportNotificationParams{
+			ID:             toNullInt64(notification.ID),
+			Room:           params.Room,
+			Type:           params.Type,
+			Title:          params.Title,
+			Body:           params.Body,
+			Severity:       params.Severity,
+			Data:           params.Data,
+			Link:           params.Link,
+			Actions:        params.Actions,
+			Localizations:  params.Localizations,
+			TemplateKey:    params.TemplateKey,
+			TemplateParams: params.TemplateParams,
+			CreatedAt:      params.CreatedAt,
+			ExpiresAt:      params.ExpiresAt,
+			UpdatedAt:      toNullTime(notification.UpdatedAt),
+			DeletedAt:      toNullTime(notification.DeletedAt),
+			CollapseKey:    params.CollapseKey,
+			ReplacesID:     params.ReplacesID,
+			SupersededBy:   toNullInt64(notification.SupersededBy),
+			Priority:       params.Priority,
+			Seq:            params.Seq,
+		})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "import notification failed")
+			s.log.Error("sql import notification failed",
+				zap.Int64("id", notification.ID),
+				zap.String("room", notification.Room),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		if notification.ID == 0 {
+			notification.ID = id
+		}
+		if notification.Replaces != 0 {
+			if err := queries.SupersedeNotification(ctx, db.SupersedeNotificationParams{
+				SupersededBy: toNullInt64(notification.ID),
+				ID:           notification.Replaces,
+			}); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "supersede notification failed")
+				s.log.Error("sql supersede notification failed", zap.Int64("id", notification.Replaces), zap.Error(err))
+				return nil, err
+			}
+		}
+		imported = append(imported, notification)
+	}
+
+	if err := tx.Commit(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "commit transaction failed")
+		s.log.Error("sql commit import failed", zap.Int("batch_size", len(notifications)), zap.Error(err))
+		return nil, err
+	}
+	return imported, nil
+}
@@ -2,7 +2,6 @@ package store
 
 import (
 	"database/sql"
-	"sse_demo/internal/db"
 
 	_ "github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
@@ -12,7 +11,26 @@ import (
 	"sse_demo/internal/store/mysql"
 )
 
-func NewStore(cfg *config.Config, logger *zap.Logger) (repository.NotificationRepository, error) {
+// Store is implemented by every storage backend and bundles the repositories
+// they provide. Consumers depend on the narrower repository interfaces.
+type Store interface {
+	repository.NotificationRepository
+	repository.NotificationTypeRepository
+	repository.ActionInvocationRepository
+	repository.IdempotencyKeyRepository
+	repository.RetentionRepository
+	repository.TransferRepository
+	repository.RoomRepository
+	repository.PreferenceRepository
+	repository.MaintenanceWindowRepository
+	repository.PinRepository
+	repository.RoomStateRepository
+	repository.AnnouncementRepository
+	repository.DeliveryRepository
+	repository.EscalationRepository
+}
+
+func NewStore(cfg *config.Config, logger *zap.Logger) (Store, error) {
 	if cfg.MySQLDSN == "" {
 		return memory.New(logger), nil
 	}
@@ -25,6 +43,5 @@ func NewStore(cfg *config.Config, logger *zap.Logger) (repository.NotificationRe
 		logger.Error("mysql ping failed", zap.Error(err))
 		return nil, err
 	}
-	queries := db.New(sqlDB)
-	return mysql.New(queries, logger), nil
+	return mysql.New(sqlDB, logger), nil
 }
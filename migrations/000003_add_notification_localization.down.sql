@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+  DROP COLUMN template_params,
+  DROP COLUMN template_key,
+  DROP COLUMN localizations;
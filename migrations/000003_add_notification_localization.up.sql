@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+  ADD COLUMN localizations JSON NULL AFTER body,
+  ADD COLUMN template_key VARCHAR(128) NOT NULL DEFAULT '' AFTER localizations,
+  ADD COLUMN template_params JSON NULL AFTER template_key;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notification_types;
+
+ALTER TABLE notifications
+  DROP COLUMN expires_at,
+  DROP COLUMN severity;
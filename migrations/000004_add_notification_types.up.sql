@@ -0,0 +1,11 @@
+ALTER TABLE notifications
+  ADD COLUMN severity VARCHAR(32) NOT NULL DEFAULT '' AFTER body,
+  ADD COLUMN expires_at TIMESTAMP NULL AFTER created_at;
+
+CREATE TABLE IF NOT EXISTS notification_types (
+  name VARCHAR(64) PRIMARY KEY,
+  default_severity VARCHAR(32) NOT NULL DEFAULT '',
+  default_ttl_seconds INT NOT NULL DEFAULT 0,
+  allowed_rooms JSON NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
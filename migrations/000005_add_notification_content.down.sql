@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+  DROP COLUMN actions,
+  DROP COLUMN link,
+  DROP COLUMN data;
@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+  ADD COLUMN data JSON NULL AFTER severity,
+  ADD COLUMN link VARCHAR(2048) NOT NULL DEFAULT '' AFTER data,
+  ADD COLUMN actions JSON NULL AFTER link;
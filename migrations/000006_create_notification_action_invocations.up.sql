@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS notification_action_invocations (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  notification_id BIGINT NOT NULL,
+  action_id VARCHAR(64) NOT NULL,
+  user_id VARCHAR(255) NOT NULL,
+  invoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_action_invocations_notification (notification_id)
+);
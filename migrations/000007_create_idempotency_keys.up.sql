@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  idem_key VARCHAR(255) PRIMARY KEY,
+  notification_id BIGINT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_idempotency_keys_created_at (created_at)
+);
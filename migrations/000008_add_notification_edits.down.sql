@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+  DROP COLUMN deleted_at,
+  DROP COLUMN updated_at;
@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+  ADD COLUMN updated_at TIMESTAMP NULL AFTER expires_at,
+  ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at;
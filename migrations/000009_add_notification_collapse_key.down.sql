@@ -0,0 +1,5 @@
+ALTER TABLE notifications
+  DROP INDEX idx_notifications_room_collapse_key,
+  DROP COLUMN superseded_by,
+  DROP COLUMN replaces_id,
+  DROP COLUMN collapse_key;
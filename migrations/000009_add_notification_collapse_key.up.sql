@@ -0,0 +1,5 @@
+ALTER TABLE notifications
+  ADD COLUMN collapse_key VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_at,
+  ADD COLUMN replaces_id BIGINT NULL AFTER collapse_key,
+  ADD COLUMN superseded_by BIGINT NULL AFTER replaces_id,
+  ADD INDEX idx_notifications_room_collapse_key (room, collapse_key);
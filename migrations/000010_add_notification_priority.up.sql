@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+  ADD COLUMN priority TINYINT NOT NULL DEFAULT 1 AFTER superseded_by;
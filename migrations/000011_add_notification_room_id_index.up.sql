@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+  ADD INDEX idx_notifications_room_id (room, id);
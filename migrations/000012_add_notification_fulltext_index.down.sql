@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+  DROP INDEX ftx_notifications_title_body;
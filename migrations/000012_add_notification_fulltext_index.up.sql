@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+  ADD FULLTEXT INDEX ftx_notifications_title_body (title, body);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS rooms (
+  name VARCHAR(255) PRIMARY KEY,
+  display_name VARCHAR(255) NOT NULL DEFAULT '',
+  description VARCHAR(1024) NOT NULL DEFAULT '',
+  owner VARCHAR(255) NOT NULL DEFAULT '',
+  visibility VARCHAR(16) NOT NULL DEFAULT 'public',
+  history_limit INT NOT NULL DEFAULT 0,
+  retention_days INT NOT NULL DEFAULT 0,
+  retention_max_count INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
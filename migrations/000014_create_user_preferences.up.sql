@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+  user_id VARCHAR(255) PRIMARY KEY,
+  muted_rooms JSON NULL,
+  opt_out_types JSON NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+ALTER TABLE user_preferences
+  ADD COLUMN quiet_hours JSON NULL AFTER opt_out_types;
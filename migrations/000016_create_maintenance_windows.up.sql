@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  room VARCHAR(255) NOT NULL,
+  type VARCHAR(64) NOT NULL DEFAULT '',
+  mode VARCHAR(16) NOT NULL,
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  starts_at TIMESTAMP NOT NULL,
+  ends_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_maintenance_windows_ends_at (ends_at)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS notification_pins (
+  notification_id BIGINT PRIMARY KEY,
+  room VARCHAR(255) NOT NULL,
+  pinned_by VARCHAR(255) NOT NULL DEFAULT '',
+  pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_notification_pins_room (room, pinned_at)
+);
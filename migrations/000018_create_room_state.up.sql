@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS room_state (
+  room VARCHAR(255) NOT NULL,
+  state_key VARCHAR(128) NOT NULL,
+  value JSON NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room, state_key)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE notifications
+  DROP INDEX idx_notifications_room_seq,
+  DROP COLUMN seq;
+
+DROP TABLE IF EXISTS room_sequences;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS room_sequences (
+  room VARCHAR(255) NOT NULL PRIMARY KEY,
+  seq BIGINT NOT NULL
+);
+
+ALTER TABLE notifications
+  ADD COLUMN seq BIGINT NOT NULL DEFAULT 0 AFTER priority,
+  ADD INDEX idx_notifications_room_seq (room, seq);
+
+UPDATE notifications
+JOIN (SELECT id, ROW_NUMBER() OVER (PARTITION BY room ORDER BY id) AS seq FROM notifications) numbered
+  ON numbered.id = notifications.id
+SET notifications.seq = numbered.seq;
+
+INSERT INTO room_sequences (room, seq)
+SELECT room, MAX(seq) FROM notifications GROUP BY room;
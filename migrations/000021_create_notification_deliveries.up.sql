@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+  notification_id BIGINT NOT NULL,
+  user_id VARCHAR(255) NOT NULL DEFAULT '',
+  enqueued INT NOT NULL DEFAULT 0,
+  dropped INT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (notification_id, user_id)
+);
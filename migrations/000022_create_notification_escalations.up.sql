@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notification_escalations (
+  notification_id BIGINT PRIMARY KEY,
+  policy VARCHAR(64) NOT NULL,
+  step INT NOT NULL DEFAULT 0,
+  status VARCHAR(16) NOT NULL,
+  due_at TIMESTAMP NOT NULL,
+  acked_by VARCHAR(255) NOT NULL DEFAULT '',
+  acked_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_notification_escalations_due (status, due_at)
+);
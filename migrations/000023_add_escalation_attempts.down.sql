@@ -0,0 +1,2 @@
+ALTER TABLE notification_escalations
+  DROP COLUMN attempts;
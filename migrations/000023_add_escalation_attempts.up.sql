@@ -0,0 +1,2 @@
+ALTER TABLE notification_escalations
+  ADD COLUMN attempts INT NOT NULL DEFAULT 0 AFTER step;
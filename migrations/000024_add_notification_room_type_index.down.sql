@@ -0,0 +1,2 @@
+ALTER TABLE notifications
+  DROP INDEX idx_notifications_room_type;